@@ -64,6 +64,52 @@ func main() {
 				return server.Start()
 			},
 		},
+		{
+			Name:        "export",
+			Description: "export renders the published posts, author pages, feeds and sitemap to a directory of static files, only posts updated since the previous export are rendered again unless full is set",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "dir", Value: "public"},
+				&cli.BoolFlag{Name: "full"},
+			},
+			Action: func(c *cli.Context) error {
+				return server.Export(c.String("dir"), c.Bool("full"))
+			},
+		},
+		{
+			Name:        "import-wordpress",
+			Description: "import-wordpress imports the posts, authors, categories and tags of a WordPress WXR export, a dry run summary is reported and confirmed before the import is written in a single transaction",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "file", Required: true},
+				&cli.BoolFlag{Name: "dry-run"},
+				&cli.BoolFlag{Name: "yes", Usage: "write the import without a summary or confirmation"},
+			},
+			Action: func(c *cli.Context) error {
+				return server.ImportWordPress(c.String("file"), c.Bool("dry-run"), c.Bool("yes"))
+			},
+		},
+		{
+			Name:        "import-markdown",
+			Description: "import-markdown imports the .md files with YAML or TOML front matter in a directory, posts are matched by slug so importing again updates them, a dry run summary is reported and confirmed first",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "dir", Required: true},
+				&cli.BoolFlag{Name: "dry-run"},
+				&cli.BoolFlag{Name: "yes", Usage: "write the import without a summary or confirmation"},
+			},
+			Action: func(c *cli.Context) error {
+				return server.ImportMarkdown(c.String("dir"), c.Bool("dry-run"), c.Bool("yes"))
+			},
+		},
+		{
+			Name:        "export-markdown",
+			Description: "export-markdown writes every post as a .md file with front matter, the files can be imported back with import-markdown",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "dir", Value: "posts"},
+				&cli.StringFlag{Name: "format", Value: "yaml", Usage: "front matter format, yaml or toml"},
+			},
+			Action: func(c *cli.Context) error {
+				return server.ExportMarkdown(c.String("dir"), c.String("format"))
+			},
+		},
 	}
 
 	err := app.Run(os.Args)
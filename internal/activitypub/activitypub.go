@@ -0,0 +1,131 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	ContentType = "application/activity+json"
+
+	// LDContentType is the media type the specification mandates, most servers also accept ContentType.
+	LDContentType = `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+
+	ActivityStreamsContext = "https://www.w3.org/ns/activitystreams"
+	SecurityContext        = "https://w3id.org/security/v1"
+
+	// Public addresses an activity to everyone.
+	Public = "https://www.w3.org/ns/activitystreams#Public"
+)
+
+var ErrActivity = errors.New("activitypub: activity is malformed")
+
+type Actor struct {
+	Context           []string   `json:"@context"`
+	ID                string     `json:"id"`
+	Type              string     `json:"type"`
+	PreferredUsername string     `json:"preferredUsername"`
+	Name              string     `json:"name"`
+	URL               string     `json:"url,omitempty"`
+	Inbox             string     `json:"inbox"`
+	Outbox            string     `json:"outbox"`
+	Followers         string     `json:"followers"`
+	Endpoints         *Endpoints `json:"endpoints,omitempty"`
+	PublicKey         PublicKey  `json:"publicKey"`
+}
+
+type Endpoints struct {
+	SharedInbox string `json:"sharedInbox,omitempty"`
+}
+
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+// Activity is an activity as it is sent, the object is anything from an IRI to an embedded object.
+type Activity struct {
+	Context   interface{} `json:"@context,omitempty"`
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Actor     string      `json:"actor"`
+	Published string      `json:"published,omitempty"`
+	To        []string    `json:"to,omitempty"`
+	Cc        []string    `json:"cc,omitempty"`
+	Object    interface{} `json:"object"`
+}
+
+type Article struct {
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	AttributedTo []string `json:"attributedTo"`
+	Name         string   `json:"name"`
+	Summary      string   `json:"summary,omitempty"`
+	Content      string   `json:"content"`
+	URL          string   `json:"url"`
+	Published    string   `json:"published,omitempty"`
+	Updated      string   `json:"updated,omitempty"`
+	To           []string `json:"to"`
+	Cc           []string `json:"cc,omitempty"`
+	Tag          []Tag    `json:"tag,omitempty"`
+}
+
+type Tag struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type Tombstone struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type OrderedCollection struct {
+	Context      string        `json:"@context"`
+	ID           string        `json:"id"`
+	Type         string        `json:"type"`
+	TotalItems   int           `json:"totalItems"`
+	OrderedItems []interface{} `json:"orderedItems,omitempty"`
+}
+
+// IncomingActivity is an activity received in an inbox, only the fields needed to route it are decoded.
+type IncomingActivity struct {
+	ID     string          `json:"id"`
+	Type   string          `json:"type"`
+	Actor  string          `json:"actor"`
+	Object json.RawMessage `json:"object"`
+}
+
+// ParseActivity decodes an incoming activity and makes sure it names its type and actor.
+func ParseActivity(data []byte) (*IncomingActivity, error) {
+	activity := new(IncomingActivity)
+	err := json.Unmarshal(data, activity)
+	if err != nil || activity.Type == "" || activity.Actor == "" {
+		return nil, ErrActivity
+	}
+	return activity, nil
+}
+
+// ObjectID returns the id of the activity object, whether it is embedded or only referenced.
+func (a *IncomingActivity) ObjectID() string {
+	var id string
+	if json.Unmarshal(a.Object, &id) == nil {
+		return id
+	}
+
+	var object struct {
+		ID string `json:"id"`
+	}
+	json.Unmarshal(a.Object, &object)
+	return object.ID
+}
+
+// ObjectType returns the type of an embedded object, empty when the object is only referenced.
+func (a *IncomingActivity) ObjectType() string {
+	var object struct {
+		Type string `json:"type"`
+	}
+	json.Unmarshal(a.Object, &object)
+	return object.Type
+}
@@ -0,0 +1,109 @@
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/security/outbound"
+)
+
+const maxResponseSize = 1 << 20
+
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the URLs found in activities, which are chosen by remote servers, so it
+// only connects to public addresses over https.
+func NewClient(timeout time.Duration) *Client {
+	return &Client{httpClient: outbound.NewClient(timeout, "https")}
+}
+
+// Deliver posts a signed activity to an inbox, any status other than 2xx is an error.
+func (c *Client) Deliver(ctx context.Context, inbox string, activity []byte, keyID string, key *rsa.PrivateKey) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, inbox, bytes.NewReader(activity))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", LDContentType)
+	req.Header.Set("Accept", ContentType)
+
+	err = Sign(req, keyID, key, activity)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("activitypub: %s responded %s", inbox, resp.Status)
+	}
+	return nil
+}
+
+// FetchActor retrieves a remote actor, the request is signed for servers running in authorized fetch mode.
+func (c *Client) FetchActor(ctx context.Context, id string, keyID string, key *rsa.PrivateKey) (*Actor, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, id, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", ContentType)
+
+	err = Sign(req, keyID, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("activitypub: %s responded %s", id, resp.Status)
+	}
+
+	actor := new(Actor)
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(actor)
+	if err != nil {
+		return nil, err
+	}
+	if actor.ID == "" || actor.Inbox == "" {
+		return nil, ErrActivity
+	}
+	return actor, nil
+}
+
+// FetchKey resolves a key id to the public key of the actor owning it, the key id is the actor id with a fragment.
+func (c *Client) FetchKey(keyID string, key *rsa.PrivateKey) KeyFetcher {
+	return func(ctx context.Context, remoteKeyID string) (*rsa.PublicKey, string, error) {
+		actorID := strings.SplitN(remoteKeyID, "#", 2)[0]
+		actor, err := c.FetchActor(ctx, actorID, keyID, key)
+		if err != nil {
+			return nil, "", err
+		}
+		if actor.PublicKey.ID != remoteKeyID || actor.PublicKey.Owner != actor.ID {
+			return nil, "", ErrSignature
+		}
+
+		publicKey, err := ParsePublicKey(actor.PublicKey.PublicKeyPem)
+		if err != nil {
+			return nil, "", err
+		}
+		return publicKey, actor.ID, nil
+	}
+}
@@ -0,0 +1,135 @@
+package activitypub
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrSignature = errors.New("activitypub: request signature is invalid")
+
+// MaxClockSkew is how far the Date of a signed request may drift from the local clock.
+const MaxClockSkew = 12 * time.Hour
+
+var signedHeaders = []string{"(request-target)", "host", "date", "digest"}
+
+// KeyFetcher resolves the key id of a signature to the public key and the actor owning it.
+type KeyFetcher func(ctx context.Context, keyID string) (key *rsa.PublicKey, owner string, err error)
+
+// Digest returns the value of the Digest header for a body.
+func Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Sign adds Date, Digest and a draft-cavage HTTP Signature header to a request.
+func Sign(r *http.Request, keyID string, key *rsa.PrivateKey, body []byte) error {
+	r.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	r.Header.Set("Digest", Digest(body))
+
+	hash := sha256.Sum256([]byte(signingString(r, r.URL.Host, signedHeaders)))
+	signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set("Signature", fmt.Sprintf(
+		`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyID, strings.Join(signedHeaders, " "), base64.StdEncoding.EncodeToString(signature),
+	))
+	return nil
+}
+
+// Verify checks the signature of a request and returns the actor that signed it.
+// The signature has to cover the request target, host, date and, for requests with a body, the digest.
+func Verify(r *http.Request, body []byte, fetchKey KeyFetcher) (string, error) {
+	params := parseSignature(r.Header.Get("Signature"))
+	if params["keyId"] == "" || params["signature"] == "" {
+		return "", ErrSignature
+	}
+
+	algorithm := params["algorithm"]
+	if algorithm != "" && algorithm != "rsa-sha256" && algorithm != "hs2019" {
+		return "", ErrSignature
+	}
+
+	headers := []string{"date"}
+	if params["headers"] != "" {
+		headers = strings.Fields(strings.ToLower(params["headers"]))
+	}
+	for _, required := range []string{"(request-target)", "host", "date"} {
+		if !contains(headers, required) {
+			return "", ErrSignature
+		}
+	}
+
+	date, err := http.ParseTime(r.Header.Get("Date"))
+	if err != nil || time.Since(date) > MaxClockSkew || time.Until(date) > MaxClockSkew {
+		return "", ErrSignature
+	}
+
+	if len(body) > 0 {
+		if !contains(headers, "digest") || r.Header.Get("Digest") != Digest(body) {
+			return "", ErrSignature
+		}
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return "", ErrSignature
+	}
+
+	key, owner, err := fetchKey(r.Context(), params["keyId"])
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(signingString(r, r.Host, headers)))
+	if rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature) != nil {
+		return "", ErrSignature
+	}
+	return owner, nil
+}
+
+func signingString(r *http.Request, host string, headers []string) string {
+	lines := make([]string, 0, len(headers))
+	for _, header := range headers {
+		switch header {
+		case "(request-target)":
+			lines = append(lines, fmt.Sprintf("(request-target): %s %s", strings.ToLower(r.Method), r.URL.RequestURI()))
+		case "host":
+			lines = append(lines, "host: "+host)
+		default:
+			lines = append(lines, header+": "+r.Header.Get(header))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func parseSignature(header string) map[string]string {
+	params := make(map[string]string)
+	for _, part := range strings.Split(header, ",") {
+		pair := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+		params[pair[0]] = strings.Trim(pair[1], `"`)
+	}
+	return params
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
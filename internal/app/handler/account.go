@@ -113,6 +113,7 @@ func (h *accountHandler) List() http.HandlerFunc {
 // @Produce json
 // @Param account_id path int true "account id" Format(int64)
 // @Success 200 {object} model.AccountResponse
+// @Header 200 {string} ETag "resource version"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
@@ -137,6 +138,7 @@ func (h *accountHandler) Get() http.HandlerFunc {
 			}
 		}
 
+		web.SetETag(w, res.Version)
 		web.MarshalPayload(w, http.StatusOK, res)
 	}
 }
@@ -148,12 +150,15 @@ func (h *accountHandler) Get() http.HandlerFunc {
 // @Accept json
 // @Produce json
 // @Param account_id path int true "account id" Format(int64)
+// @Param If-Match header string false "ETag of the version being changed"
 // @Param payload body model.AccountUpdateRequest true "body request"
 // @Success 200 {object} model.AccountResponse
+// @Header 200 {string} ETag "resource version"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 409 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security ApiKeyAuth
 func (h *accountHandler) Update() http.HandlerFunc {
@@ -164,7 +169,13 @@ func (h *accountHandler) Update() http.HandlerFunc {
 			return
 		}
 
-		req := model.AccountUpdateRequest{ID: id}
+		version, err := web.GetIfMatch(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusPreconditionFailed, err)
+			return
+		}
+
+		req := model.AccountUpdateRequest{ID: id, Version: version}
 		err = json.NewDecoder(r.Body).Decode(&req)
 		if err != nil {
 			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
@@ -189,12 +200,16 @@ func (h *accountHandler) Update() http.HandlerFunc {
 			case constant.ErrAccountNotFound:
 				web.MarshalError(w, http.StatusNotFound, err)
 				return
+			case constant.ErrPrecondition:
+				web.MarshalError(w, http.StatusPreconditionFailed, err)
+				return
 			default:
 				web.MarshalError(w, http.StatusInternalServerError, err)
 				return
 			}
 		}
 
+		web.SetETag(w, res.Version)
 		web.MarshalPayload(w, http.StatusOK, res)
 	}
 }
@@ -206,11 +221,14 @@ func (h *accountHandler) Update() http.HandlerFunc {
 // @Accept json
 // @Produce json
 // @Param account_id path int true "account id" Format(int64)
+// @Param If-Match header string false "ETag of the version being changed"
 // @Param payload body model.AccountPasswordUpdateRequest true "body request"
 // @Success 200 {object} model.AccountResponse
+// @Header 200 {string} ETag "resource version"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security ApiKeyAuth
 func (h *accountHandler) UpdatePassword() http.HandlerFunc {
@@ -221,7 +239,13 @@ func (h *accountHandler) UpdatePassword() http.HandlerFunc {
 			return
 		}
 
-		req := model.AccountPasswordUpdateRequest{ID: id}
+		version, err := web.GetIfMatch(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusPreconditionFailed, err)
+			return
+		}
+
+		req := model.AccountPasswordUpdateRequest{ID: id, Version: version}
 		err = json.NewDecoder(r.Body).Decode(&req)
 		if err != nil {
 			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
@@ -243,12 +267,16 @@ func (h *accountHandler) UpdatePassword() http.HandlerFunc {
 			case constant.ErrAccountNotFound:
 				web.MarshalError(w, http.StatusNotFound, err)
 				return
+			case constant.ErrPrecondition:
+				web.MarshalError(w, http.StatusPreconditionFailed, err)
+				return
 			default:
 				web.MarshalError(w, http.StatusInternalServerError, err)
 				return
 			}
 		}
 
+		web.SetETag(w, res.Version)
 		web.MarshalPayload(w, http.StatusOK, res)
 	}
 }
@@ -259,10 +287,12 @@ func (h *accountHandler) UpdatePassword() http.HandlerFunc {
 // @Description TODO
 // @Produce json
 // @Param account_id path int true "account id" Format(int64)
+// @Param If-Match header string false "ETag of the version being changed"
 // @Success 204
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security ApiKeyAuth
 func (h *accountHandler) Delete() http.HandlerFunc {
@@ -273,7 +303,13 @@ func (h *accountHandler) Delete() http.HandlerFunc {
 			return
 		}
 
-		req := model.AccountDeleteRequest{ID: id}
+		version, err := web.GetIfMatch(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusPreconditionFailed, err)
+			return
+		}
+
+		req := model.AccountDeleteRequest{ID: id, Version: version}
 		err = h.accountService.Delete(r.Context(), req)
 		if err != nil {
 			switch err {
@@ -283,6 +319,9 @@ func (h *accountHandler) Delete() http.HandlerFunc {
 			case constant.ErrAccountNotFound:
 				web.MarshalError(w, http.StatusNotFound, err)
 				return
+			case constant.ErrPrecondition:
+				web.MarshalError(w, http.StatusPreconditionFailed, err)
+				return
 			default:
 				web.MarshalError(w, http.StatusInternalServerError, err)
 				return
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/activitypub"
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+// maxActivitySize bounds the body read from an inbox request.
+const maxActivitySize = 1 << 20
+
+type ActivityPubHandler interface {
+	WebFinger() http.HandlerFunc
+	GetActor() http.HandlerFunc
+	GetOutbox() http.HandlerFunc
+	GetFollowers() http.HandlerFunc
+	GetObject() http.HandlerFunc
+	Inbox() http.HandlerFunc
+}
+
+func NewActivityPubHandler(activityPubService service.ActivityPubService) ActivityPubHandler {
+	return &activityPubHandler{activityPubService}
+}
+
+type activityPubHandler struct {
+	activityPubService service.ActivityPubService
+}
+
+// WebFinger serves /.well-known/webfinger, it has to live at the root of the host for remote servers to
+// discover the actors.
+func (h *activityPubHandler) WebFinger() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := model.WebFingerRequest{Resource: web.GetUrlQueryString(r, "resource")}
+		res, err := h.activityPubService.WebFinger(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrWebFingerResource:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			case constant.ErrAccountNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		marshalDocument(w, http.StatusOK, "application/jrd+json", res)
+	}
+}
+
+// @Router /accounts/{account_id}/actor [get]
+// @Tags activitypub
+// @Summary Get ActivityPub actor
+// @Description TODO
+// @Produce application/activity+json
+// @Param account_id path int true "account id" Format(int64)
+// @Success 200 {object} activitypub.Actor
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *activityPubHandler) GetActor() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.ActorGetRequest{AccountID: accountID}
+		res, err := h.activityPubService.GetActor(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrAccountNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		marshalDocument(w, http.StatusOK, activitypub.ContentType, res)
+	}
+}
+
+// @Router /accounts/{account_id}/outbox [get]
+// @Tags activitypub
+// @Summary Get ActivityPub outbox
+// @Description TODO
+// @Produce application/activity+json
+// @Param account_id path int true "account id" Format(int64)
+// @Success 200 {object} activitypub.OrderedCollection
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *activityPubHandler) GetOutbox() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.OutboxGetRequest{AccountID: accountID}
+		res, err := h.activityPubService.GetOutbox(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrAccountNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		marshalDocument(w, http.StatusOK, activitypub.ContentType, res)
+	}
+}
+
+// @Router /accounts/{account_id}/followers [get]
+// @Tags activitypub
+// @Summary Get ActivityPub followers
+// @Description TODO
+// @Produce application/activity+json
+// @Param account_id path int true "account id" Format(int64)
+// @Success 200 {object} activitypub.OrderedCollection
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *activityPubHandler) GetFollowers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.FollowersGetRequest{AccountID: accountID}
+		res, err := h.activityPubService.GetFollowers(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrAccountNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		marshalDocument(w, http.StatusOK, activitypub.ContentType, res)
+	}
+}
+
+// @Router /posts/{post_id}/object [get]
+// @Tags activitypub
+// @Summary Get ActivityPub object of post
+// @Description TODO
+// @Produce application/activity+json
+// @Param post_id path int true "post id" Format(int64)
+// @Success 200 {object} activitypub.Article
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *activityPubHandler) GetObject() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.ObjectGetRequest{PostID: postID}
+		res, err := h.activityPubService.GetObject(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrPostNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		marshalDocument(w, http.StatusOK, activitypub.ContentType, res)
+	}
+}
+
+// @Router /accounts/{account_id}/inbox [post]
+// @Tags activitypub
+// @Summary Post activity to ActivityPub inbox
+// @Description TODO
+// @Accept application/activity+json
+// @Produce json
+// @Param account_id path int true "account id" Format(int64)
+// @Param Signature header string true "HTTP signature of the remote actor"
+// @Param payload body object true "activity"
+// @Success 202
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *activityPubHandler) Inbox() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxActivitySize))
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+			return
+		}
+
+		req := model.InboxRequest{AccountID: accountID, Request: r, Body: body}
+		err = h.activityPubService.Inbox(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrActivity:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			case constant.ErrSignature:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrAccountNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// marshalDocument writes a JSON payload under the media type the protocol expects instead of plain JSON.
+func marshalDocument(w http.ResponseWriter, code int, contentType string, payload interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/validation"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type BookmarkHandler interface {
+	Create() http.HandlerFunc
+	List() http.HandlerFunc
+	ListFolders() http.HandlerFunc
+	Delete() http.HandlerFunc
+}
+
+func NewBookmarkHandler(bookmarkService service.BookmarkService) BookmarkHandler {
+	return &bookmarkHandler{bookmarkService}
+}
+
+type bookmarkHandler struct {
+	bookmarkService service.BookmarkService
+}
+
+// @Router /accounts/{account_id}/bookmarks/{post_id} [put]
+// @Tags bookmarks
+// @Summary Create bookmark
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param account_id path int true "account id" Format(int64)
+// @Param post_id path int true "post id" Format(int64)
+// @Param payload body model.BookmarkCreateRequest false "body request"
+// @Success 200 {object} model.BookmarkResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *bookmarkHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.BookmarkCreateRequest{AccountID: accountID, PostID: postID}
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil && err != io.EOF {
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+			return
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.bookmarkService.Create(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrPostNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /accounts/{account_id}/bookmarks [get]
+// @Tags bookmarks
+// @Summary List bookmarks
+// @Description TODO
+// @Produce json
+// @Param account_id path int true "account id" Format(int64)
+// @Param limit query int false "pagination limit"
+// @Param cursor query string false "pagination cursor"
+// @Param folder query string false "bookmark folder"
+// @Success 200 {object} model.BookmarkListResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *bookmarkHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		limit, _, err := web.GetPagination(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.BookmarkListRequest{
+			AccountID: accountID,
+			Limit:     limit,
+		}
+
+		if cursor := web.GetUrlQueryString(r, "cursor"); cursor != "" {
+			req.Cursor, err = model.DecodeBookmarkCursor(cursor)
+			if err != nil {
+				web.MarshalError(w, http.StatusBadRequest, constant.ErrUrlQueryParameter)
+				return
+			}
+		}
+
+		if folder, found := r.URL.Query()["folder"]; found {
+			req.Folder = &folder[0]
+		}
+
+		res, err := h.bookmarkService.List(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /accounts/{account_id}/bookmarks/folders [get]
+// @Tags bookmarks
+// @Summary List bookmark folders
+// @Description TODO
+// @Produce json
+// @Param account_id path int true "account id" Format(int64)
+// @Success 200 {array} model.BookmarkFolderResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *bookmarkHandler) ListFolders() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.BookmarkFolderListRequest{AccountID: accountID}
+		res, err := h.bookmarkService.ListFolders(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /accounts/{account_id}/bookmarks/{post_id} [delete]
+// @Tags bookmarks
+// @Summary Delete bookmark
+// @Description TODO
+// @Produce json
+// @Param account_id path int true "account id" Format(int64)
+// @Param post_id path int true "post id" Format(int64)
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *bookmarkHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.BookmarkDeleteRequest{AccountID: accountID, PostID: postID}
+		err = h.bookmarkService.Delete(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
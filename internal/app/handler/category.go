@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/validation"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type CategoryHandler interface {
+	Create() http.HandlerFunc
+	List() http.HandlerFunc
+	Get() http.HandlerFunc
+	Update() http.HandlerFunc
+	Delete() http.HandlerFunc
+	ListPosts() http.HandlerFunc
+}
+
+func NewCategoryHandler(categoryService service.CategoryService) CategoryHandler {
+	return &categoryHandler{categoryService}
+}
+
+type categoryHandler struct {
+	categoryService service.CategoryService
+}
+
+// @Router /categories [post]
+// @Tags categories
+// @Summary Create category
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param payload body model.CategoryCreateRequest true "body request"
+// @Success 201 {object} model.CategoryResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *categoryHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req model.CategoryCreateRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+			return
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.categoryService.Create(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrCategoryNotFound:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusCreated, res)
+	}
+}
+
+// @Router /categories [get]
+// @Tags categories
+// @Summary List categories
+// @Description TODO
+// @Produce json
+// @Param limit query int false "pagination limit"
+// @Param offset query int false "pagination offset"
+// @Param name query string false "category name"
+// @Param parent_id query int false "parent category id" Format(int64)
+// @Success 200 {array} model.CategoryResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *categoryHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := web.GetPagination(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.CategoryListRequest{
+			Limit:  limit,
+			Offset: offset,
+			Name:   web.GetUrlQueryString(r, "name"),
+		}
+
+		if web.GetUrlQueryString(r, "parent_id") != "" {
+			parentID, err := web.GetUrlQueryInt64(r, "parent_id")
+			if err != nil {
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			}
+			req.ParentID = &parentID
+		}
+
+		res, err := h.categoryService.List(r.Context(), req)
+		if err != nil {
+			web.MarshalError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /categories/{category_id} [get]
+// @Tags categories
+// @Summary Get category
+// @Description TODO
+// @Produce json
+// @Param category_id path int true "category id" Format(int64)
+// @Success 200 {object} model.CategoryResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *categoryHandler) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "category_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.CategoryGetRequest{ID: id}
+		res, err := h.categoryService.Get(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrCategoryNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /categories/{category_id} [put]
+// @Tags categories
+// @Summary Update category
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param category_id path int true "category id" Format(int64)
+// @Param payload body model.CategoryUpdateRequest true "body request"
+// @Success 200 {object} model.CategoryResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *categoryHandler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "category_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.CategoryUpdateRequest{ID: id}
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+			return
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.categoryService.Update(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrCategoryParent:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrCategoryNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /categories/{category_id} [delete]
+// @Tags categories
+// @Summary Delete category
+// @Description TODO
+// @Produce json
+// @Param category_id path int true "category id" Format(int64)
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *categoryHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "category_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.CategoryDeleteRequest{ID: id}
+		err = h.categoryService.Delete(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrCategoryNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			case constant.ErrCategoryHasChildren:
+				web.MarshalError(w, http.StatusConflict, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// @Router /categories/{category_id}/posts [get]
+// @Tags categories
+// @Summary List category posts
+// @Description TODO
+// @Produce json
+// @Param category_id path int true "category id" Format(int64)
+// @Param limit query int false "pagination limit"
+// @Param offset query int false "pagination offset"
+// @Success 200 {array} model.PostResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *categoryHandler) ListPosts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "category_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		limit, offset, err := web.GetPagination(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.CategoryPostListRequest{
+			ID:     id,
+			Limit:  limit,
+			Offset: offset,
+		}
+
+		res, err := h.categoryService.ListPosts(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrCategoryNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/validation"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type CommentHandler interface {
+	Create() http.HandlerFunc
+	List() http.HandlerFunc
+	Update() http.HandlerFunc
+	Delete() http.HandlerFunc
+}
+
+func NewCommentHandler(commentService service.CommentService) CommentHandler {
+	return &commentHandler{commentService}
+}
+
+type commentHandler struct {
+	commentService service.CommentService
+}
+
+// @Router /posts/{post_id}/comments [post]
+// @Tags comments
+// @Summary Create comment
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param payload body model.CommentCreateRequest true "body request"
+// @Success 201 {object} model.CommentResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *commentHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.CommentCreateRequest{PostID: postID}
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+			return
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.commentService.Create(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrCommentParent:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrPostNotFound, constant.ErrCommentNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusCreated, res)
+	}
+}
+
+// @Router /posts/{post_id}/comments [get]
+// @Tags comments
+// @Summary List comments
+// @Description TODO
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param limit query int false "pagination limit, applied to top level comments in tree mode"
+// @Param offset query int false "pagination offset, applied to top level comments in tree mode"
+// @Param mode query string false "listing mode" Enums(flat, tree) default(flat)
+// @Success 200 {array} model.CommentResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *commentHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		limit, offset, err := web.GetPagination(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.CommentListRequest{
+			PostID: postID,
+			Limit:  limit,
+			Offset: offset,
+		}
+
+		switch web.GetUrlQueryString(r, "mode") {
+		case "", constant.COMMENT_MODE_FLAT:
+		case constant.COMMENT_MODE_TREE:
+			req.Tree = true
+		default:
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrUrlQueryParameter)
+			return
+		}
+
+		res, err := h.commentService.List(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrPostNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /posts/{post_id}/comments/{comment_id} [put]
+// @Tags comments
+// @Summary Update comment
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param comment_id path int true "comment id" Format(int64)
+// @Param payload body model.CommentUpdateRequest true "body request"
+// @Success 200 {object} model.CommentResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *commentHandler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		id, err := web.GetUrlPathInt64(r, "comment_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.CommentUpdateRequest{ID: id, PostID: postID}
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+			return
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.commentService.Update(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrCommentNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /posts/{post_id}/comments/{comment_id} [delete]
+// @Tags comments
+// @Summary Delete comment
+// @Description TODO
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param comment_id path int true "comment id" Format(int64)
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *commentHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		id, err := web.GetUrlPathInt64(r, "comment_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.CommentDeleteRequest{ID: id, PostID: postID}
+		err = h.commentService.Delete(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrPostNotFound, constant.ErrCommentNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/feed"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type FeedHandler interface {
+	RSS() http.HandlerFunc
+	Atom() http.HandlerFunc
+	JSON() http.HandlerFunc
+	AccountRSS() http.HandlerFunc
+	AccountAtom() http.HandlerFunc
+}
+
+func NewFeedHandler(feedService service.FeedService) FeedHandler {
+	return &feedHandler{feedService}
+}
+
+type feedHandler struct {
+	feedService service.FeedService
+}
+
+// @Router /feed.rss [get]
+// @Tags feeds
+// @Summary Get RSS feed
+// @Description TODO
+// @Produce xml
+// @Param content query string false "full post content or summaries only" Enums(full, summary)
+// @Success 200 {string} string "RSS 2.0 document"
+// @Success 304
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *feedHandler) RSS() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, 0, feed.RSSContentType, feed.RSS)
+	}
+}
+
+// @Router /feed.atom [get]
+// @Tags feeds
+// @Summary Get Atom feed
+// @Description TODO
+// @Produce xml
+// @Param content query string false "full post content or summaries only" Enums(full, summary)
+// @Success 200 {string} string "Atom 1.0 document"
+// @Success 304
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *feedHandler) Atom() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, 0, feed.AtomContentType, feed.Atom)
+	}
+}
+
+// @Router /feed.json [get]
+// @Tags feeds
+// @Summary Get JSON feed
+// @Description TODO
+// @Produce json
+// @Param limit query int false "pagination limit"
+// @Param offset query int false "pagination offset"
+// @Param content query string false "full post content or summaries only" Enums(full, summary)
+// @Success 200 {string} string "JSON Feed 1.1 document"
+// @Success 304
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *feedHandler) JSON() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := web.GetPagination(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		content, err := getFeedContent(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.FeedListRequest{
+			Limit:   limit,
+			Offset:  offset,
+			Content: content,
+			Path:    r.URL.Path,
+		}
+		res, err := h.feedService.List(r.Context(), req)
+		if err != nil {
+			web.MarshalError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		data, err := feed.JSON(res)
+		if err != nil {
+			web.MarshalError(w, http.StatusInternalServerError, constant.ErrServer)
+			return
+		}
+
+		web.ServeDocument(w, r, feed.JSONContentType, res.Updated, data)
+	}
+}
+
+// @Router /accounts/{account_id}/feed.rss [get]
+// @Tags feeds
+// @Summary Get account RSS feed
+// @Description TODO
+// @Produce xml
+// @Param account_id path int true "account id" Format(int64)
+// @Param content query string false "full post content or summaries only" Enums(full, summary)
+// @Success 200 {string} string "RSS 2.0 document"
+// @Success 304
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *feedHandler) AccountRSS() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		h.serve(w, r, accountID, feed.RSSContentType, feed.RSS)
+	}
+}
+
+// @Router /accounts/{account_id}/feed.atom [get]
+// @Tags feeds
+// @Summary Get account Atom feed
+// @Description TODO
+// @Produce xml
+// @Param account_id path int true "account id" Format(int64)
+// @Param content query string false "full post content or summaries only" Enums(full, summary)
+// @Success 200 {string} string "Atom 1.0 document"
+// @Success 304
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *feedHandler) AccountAtom() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		h.serve(w, r, accountID, feed.AtomContentType, feed.Atom)
+	}
+}
+
+// serve builds the feed of the account, or of the whole blog when accountID is zero, and writes it in the
+// format of render.
+func (h *feedHandler) serve(w http.ResponseWriter, r *http.Request, accountID int64, contentType string, render func(*feed.Feed) ([]byte, error)) {
+	content, err := getFeedContent(r)
+	if err != nil {
+		web.MarshalError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	req := model.FeedRequest{
+		AccountID: accountID,
+		Content:   content,
+		Path:      r.URL.RequestURI(),
+	}
+
+	res, err := h.feedService.Get(r.Context(), req)
+	if err != nil {
+		switch err {
+		case constant.ErrAccountNotFound:
+			web.MarshalError(w, http.StatusNotFound, err)
+			return
+		default:
+			web.MarshalError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	data, err := render(res)
+	if err != nil {
+		web.MarshalError(w, http.StatusInternalServerError, constant.ErrServer)
+		return
+	}
+
+	web.ServeDocument(w, r, contentType, res.Updated, data)
+}
+
+// getFeedContent returns whether the feed carries full posts or summaries, defaulting to the configured mode.
+func getFeedContent(r *http.Request) (string, error) {
+	content := web.GetUrlQueryString(r, "content")
+	switch content {
+	case "":
+		return config.Cfg().FeedContent, nil
+	case constant.FEED_CONTENT_FULL, constant.FEED_CONTENT_SUMMARY:
+		return content, nil
+	default:
+		return "", constant.ErrUrlQueryParameter
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type ImportHandler interface {
+	WordPress() http.HandlerFunc
+}
+
+func NewImportHandler(importService service.ImportService) ImportHandler {
+	return &importHandler{importService}
+}
+
+type importHandler struct {
+	importService service.ImportService
+}
+
+// @Router /imports/wordpress [post]
+// @Tags imports
+// @Summary Import a WordPress export
+// @Description TODO
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "WordPress eXtended RSS (WXR) export"
+// @Param dry_run query bool false "report what would be imported without writing anything"
+// @Success 200 {object} model.ImportResponse
+// @Success 201 {object} model.ImportResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 413 {object} model.ErrorResponse
+// @Failure 422 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *importHandler) WordPress() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := web.GetImportFile(w, r, "file")
+		if err != nil {
+			switch err {
+			case constant.ErrImportTooLarge:
+				web.MarshalError(w, http.StatusRequestEntityTooLarge, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		defer file.Close()
+		defer r.MultipartForm.RemoveAll()
+
+		req := model.WordPressImportRequest{
+			File:   file,
+			DryRun: web.GetUrlQueryString(r, "dry_run") == "true",
+		}
+
+		res, err := h.importService.WordPress(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrImportFormat:
+				web.MarshalError(w, http.StatusUnprocessableEntity, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		if res.DryRun {
+			web.MarshalPayload(w, http.StatusOK, res)
+			return
+		}
+		web.MarshalPayload(w, http.StatusCreated, res)
+	}
+}
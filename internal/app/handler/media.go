@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/validation"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type MediaHandler interface {
+	Create() http.HandlerFunc
+	List() http.HandlerFunc
+	Get() http.HandlerFunc
+	Update() http.HandlerFunc
+	Delete() http.HandlerFunc
+}
+
+func NewMediaHandler(mediaService service.MediaService) MediaHandler {
+	return &mediaHandler{mediaService}
+}
+
+type mediaHandler struct {
+	mediaService service.MediaService
+}
+
+// @Router /media [post]
+// @Tags media
+// @Summary Upload media
+// @Description TODO
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "media file (jpeg, png, gif or webp)"
+// @Param alt_text formData string false "alternative text"
+// @Param caption formData string false "caption"
+// @Success 201 {object} model.MediaResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 413 {object} model.ErrorResponse
+// @Failure 415 {object} model.ErrorResponse
+// @Failure 422 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *mediaHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := web.GetUploadedFile(w, r, "file")
+		if err != nil {
+			switch err {
+			case constant.ErrMediaTooLarge:
+				web.MarshalError(w, http.StatusRequestEntityTooLarge, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		defer file.Close()
+		defer r.MultipartForm.RemoveAll()
+
+		req := model.MediaCreateRequest{
+			Filename: header.Filename,
+			Size:     header.Size,
+			File:     file,
+			AltText:  r.FormValue("alt_text"),
+			Caption:  r.FormValue("caption"),
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.mediaService.Create(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrMediaTooLarge:
+				web.MarshalError(w, http.StatusRequestEntityTooLarge, err)
+				return
+			case constant.ErrMediaType:
+				web.MarshalError(w, http.StatusUnsupportedMediaType, err)
+				return
+			case constant.ErrMediaImage:
+				web.MarshalError(w, http.StatusUnprocessableEntity, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusCreated, res)
+	}
+}
+
+// @Router /media [get]
+// @Tags media
+// @Summary List my media
+// @Description TODO
+// @Produce json
+// @Param limit query int false "pagination limit"
+// @Param offset query int false "pagination offset"
+// @Param q query string false "search filename, alt text and caption"
+// @Success 200 {array} model.MediaResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *mediaHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := web.GetPagination(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.MediaListRequest{
+			Limit:  limit,
+			Offset: offset,
+			Query:  web.GetUrlQueryString(r, "q"),
+		}
+
+		res, err := h.mediaService.List(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /media/{media_id} [get]
+// @Tags media
+// @Summary Get media
+// @Description TODO
+// @Produce json
+// @Param media_id path int true "media id" Format(int64)
+// @Success 200 {object} model.MediaResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *mediaHandler) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "media_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.MediaGetRequest{ID: id}
+		res, err := h.mediaService.Get(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrMediaNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /media/{media_id} [put]
+// @Tags media
+// @Summary Update media
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param media_id path int true "media id" Format(int64)
+// @Param payload body model.MediaUpdateRequest true "body request"
+// @Success 200 {object} model.MediaResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *mediaHandler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "media_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.MediaUpdateRequest{ID: id}
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+			return
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.mediaService.Update(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrMediaNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /media/{media_id} [delete]
+// @Tags media
+// @Summary Delete media
+// @Description TODO
+// @Produce json
+// @Param media_id path int true "media id" Format(int64)
+// @Param force query bool false "delete even when posts still use it"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *mediaHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "media_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.MediaDeleteRequest{
+			ID:    id,
+			Force: web.GetUrlQueryString(r, "force") == "true",
+		}
+
+		err = h.mediaService.Delete(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrMediaNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			case constant.ErrMediaInUse:
+				web.MarshalError(w, http.StatusConflict, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type OEmbedHandler interface {
+	Get() http.HandlerFunc
+}
+
+func NewOEmbedHandler(oembedService service.OEmbedService) OEmbedHandler {
+	return &oembedHandler{oembedService}
+}
+
+type oembedHandler struct {
+	oembedService service.OEmbedService
+}
+
+// @Router /oembed [get]
+// @Tags oembed
+// @Summary Get oEmbed of post
+// @Description TODO
+// @Produce json
+// @Param url query string true "post URL"
+// @Param format query string false "response format" Enums(json)
+// @Param maxwidth query int false "maximum embed width"
+// @Param maxheight query int false "maximum embed height"
+// @Success 200 {object} model.OEmbedResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure 501 {object} model.ErrorResponse
+func (h *oembedHandler) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := model.OEmbedRequest{
+			URL:    web.GetUrlQueryString(r, "url"),
+			Format: web.GetUrlQueryString(r, "format"),
+		}
+
+		for key, dest := range map[string]*int{"maxwidth": &req.MaxWidth, "maxheight": &req.MaxHeight} {
+			if web.GetUrlQueryString(r, key) == "" {
+				continue
+			}
+
+			size, err := web.GetUrlQueryInt64(r, key)
+			if err != nil || size < 0 {
+				web.MarshalError(w, http.StatusBadRequest, constant.ErrUrlQueryParameter)
+				return
+			}
+			*dest = int(size)
+		}
+
+		res, err := h.oembedService.Get(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrOEmbedURL:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			case constant.ErrOEmbedFormat:
+				web.MarshalError(w, http.StatusNotImplemented, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
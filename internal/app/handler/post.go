@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/anonychun/go-blog-api/internal/app/model"
 	"github.com/anonychun/go-blog-api/internal/app/service"
@@ -14,9 +15,18 @@ import (
 type PostHandler interface {
 	Create() http.HandlerFunc
 	List() http.HandlerFunc
+	ListTrending() http.HandlerFunc
+	Search() http.HandlerFunc
 	Get() http.HandlerFunc
 	Update() http.HandlerFunc
 	Delete() http.HandlerFunc
+	ListStats() http.HandlerFunc
+	Submit() http.HandlerFunc
+	Approve() http.HandlerFunc
+	RequestChanges() http.HandlerFunc
+	Publish() http.HandlerFunc
+	Unpublish() http.HandlerFunc
+	ListReviews() http.HandlerFunc
 }
 
 func NewPostHandler(postService service.PostService) PostHandler {
@@ -57,6 +67,9 @@ func (h *postHandler) Create() http.HandlerFunc {
 		res, err := h.postService.Create(r.Context(), req)
 		if err != nil {
 			switch err {
+			case constant.ErrCategoryNotFound, constant.ErrMediaNotFound:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
 			case constant.ErrUnauthorized:
 				web.MarshalError(w, http.StatusUnauthorized, err)
 				return
@@ -78,8 +91,13 @@ func (h *postHandler) Create() http.HandlerFunc {
 // @Param limit query int false "pagination limit"
 // @Param offset query int false "pagination offset"
 // @Param title query string false "post title"
+// @Param tag query []string false "tag names" collectionFormat(multi)
+// @Param tag_match query string false "match any or all of the given tags" Enums(any, all) default(any)
+// @Param status query string false "post status" Enums(draft, in_review, changes_requested, approved, published) default(published)
+// @Param account_id query int false "author account id" Format(int64)
 // @Success 200 {array} model.PostResponse
 // @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 func (h *postHandler) List() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -90,12 +108,129 @@ func (h *postHandler) List() http.HandlerFunc {
 		}
 
 		req := model.PostListRequest{
+			Limit:    limit,
+			Offset:   offset,
+			Title:    web.GetUrlQueryString(r, "title"),
+			Tags:     web.GetUrlQueryStrings(r, "tag"),
+			TagMatch: web.GetUrlQueryString(r, "tag_match"),
+			Status:   web.GetUrlQueryString(r, "status"),
+		}
+		if req.Status == "" {
+			req.Status = constant.POST_STATUS_PUBLISHED
+		}
+
+		if web.GetUrlQueryString(r, "account_id") != "" {
+			req.AccountID, err = web.GetUrlQueryInt64(r, "account_id")
+			if err != nil {
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		switch req.TagMatch {
+		case "":
+			req.TagMatch = constant.TAG_MATCH_ANY
+		case constant.TAG_MATCH_ANY, constant.TAG_MATCH_ALL:
+		default:
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrUrlQueryParameter)
+			return
+		}
+
+		res, err := h.postService.List(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrPostStatus:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /posts/trending [get]
+// @Tags posts
+// @Summary List trending posts
+// @Description TODO
+// @Produce json
+// @Param limit query int false "pagination limit"
+// @Param offset query int false "pagination offset"
+// @Param window query string false "time window" Enums(day, week, month) default(week)
+// @Success 200 {array} model.PostResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *postHandler) ListTrending() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := web.GetPagination(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.PostTrendingListRequest{
 			Limit:  limit,
 			Offset: offset,
-			Title:  web.GetUrlQueryString(r, "title"),
+			Window: web.GetUrlQueryString(r, "window"),
+		}
+		if req.Window == "" {
+			req.Window = constant.TRENDING_WINDOW_WEEK
 		}
 
-		res, err := h.postService.List(r.Context(), req)
+		res, err := h.postService.ListTrending(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrTrendingWindow:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /search [get]
+// @Tags posts
+// @Summary Search posts
+// @Description TODO
+// @Produce json
+// @Param q query string true "search query"
+// @Param limit query int false "pagination limit"
+// @Param offset query int false "pagination offset"
+// @Success 200 {array} model.PostSearchResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *postHandler) Search() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := web.GetPagination(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.PostSearchRequest{
+			Limit:  limit,
+			Offset: offset,
+			Query:  web.GetUrlQueryString(r, "q"),
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.postService.Search(r.Context(), req)
 		if err != nil {
 			web.MarshalError(w, http.StatusInternalServerError, err)
 			return
@@ -113,6 +248,7 @@ func (h *postHandler) List() http.HandlerFunc {
 // @Produce json
 // @Param post_id path int true "post id" Format(int64)
 // @Success 200 {object} model.PostResponse
+// @Header 200 {string} ETag "resource version"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
@@ -124,7 +260,11 @@ func (h *postHandler) Get() http.HandlerFunc {
 			return
 		}
 
-		req := model.PostGetRequest{ID: id}
+		req := model.PostGetRequest{
+			ID:         id,
+			RemoteAddr: r.RemoteAddr,
+			UserAgent:  r.UserAgent(),
+		}
 		res, err := h.postService.Get(r.Context(), req)
 		if err != nil {
 			switch err {
@@ -137,6 +277,7 @@ func (h *postHandler) Get() http.HandlerFunc {
 			}
 		}
 
+		web.SetETag(w, res.Version)
 		web.MarshalPayload(w, http.StatusOK, res)
 	}
 }
@@ -148,11 +289,15 @@ func (h *postHandler) Get() http.HandlerFunc {
 // @Accept json
 // @Produce json
 // @Param post_id path int true "post id" Format(int64)
+// @Param If-Match header string false "ETag of the version being changed"
 // @Param payload body model.PostUpdateRequest true "body request"
 // @Success 200 {object} model.PostResponse
+// @Header 200 {string} ETag "resource version"
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security ApiKeyAuth
 func (h *postHandler) Update() http.HandlerFunc {
@@ -163,7 +308,13 @@ func (h *postHandler) Update() http.HandlerFunc {
 			return
 		}
 
-		req := model.PostUpdateRequest{ID: id}
+		version, err := web.GetIfMatch(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusPreconditionFailed, err)
+			return
+		}
+
+		req := model.PostUpdateRequest{ID: id, Version: version}
 		err = json.NewDecoder(r.Body).Decode(&req)
 		if err != nil {
 			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
@@ -179,18 +330,28 @@ func (h *postHandler) Update() http.HandlerFunc {
 		res, err := h.postService.Update(r.Context(), req)
 		if err != nil {
 			switch err {
+			case constant.ErrCategoryNotFound, constant.ErrMediaNotFound:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
 			case constant.ErrUnauthorized:
 				web.MarshalError(w, http.StatusUnauthorized, err)
 				return
 			case constant.ErrPostNotFound:
 				web.MarshalError(w, http.StatusNotFound, err)
 				return
+			case constant.ErrPostInReview, constant.ErrPostPublished:
+				web.MarshalError(w, http.StatusConflict, err)
+				return
+			case constant.ErrPrecondition:
+				web.MarshalError(w, http.StatusPreconditionFailed, err)
+				return
 			default:
 				web.MarshalError(w, http.StatusInternalServerError, err)
 				return
 			}
 		}
 
+		web.SetETag(w, res.Version)
 		web.MarshalPayload(w, http.StatusOK, res)
 	}
 }
@@ -201,10 +362,12 @@ func (h *postHandler) Update() http.HandlerFunc {
 // @Description TODO
 // @Produce json
 // @Param post_id path int true "post id" Format(int64)
+// @Param If-Match header string false "ETag of the version being changed"
 // @Success 204
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
+// @Failure 412 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security ApiKeyAuth
 func (h *postHandler) Delete() http.HandlerFunc {
@@ -215,7 +378,13 @@ func (h *postHandler) Delete() http.HandlerFunc {
 			return
 		}
 
-		req := model.PostDeleteRequest{ID: id}
+		version, err := web.GetIfMatch(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusPreconditionFailed, err)
+			return
+		}
+
+		req := model.PostDeleteRequest{ID: id, Version: version}
 		err = h.postService.Delete(r.Context(), req)
 		if err != nil {
 			switch err {
@@ -225,6 +394,9 @@ func (h *postHandler) Delete() http.HandlerFunc {
 			case constant.ErrPostNotFound:
 				web.MarshalError(w, http.StatusNotFound, err)
 				return
+			case constant.ErrPrecondition:
+				web.MarshalError(w, http.StatusPreconditionFailed, err)
+				return
 			default:
 				web.MarshalError(w, http.StatusInternalServerError, err)
 				return
@@ -234,3 +406,62 @@ func (h *postHandler) Delete() http.HandlerFunc {
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+// @Router /posts/{post_id}/stats [get]
+// @Tags posts
+// @Summary List post stats
+// @Description TODO
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param from query string false "first day (YYYY-MM-DD), defaults to 29 days before to"
+// @Param to query string false "last day (YYYY-MM-DD), defaults to today"
+// @Success 200 {array} model.PostStatResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *postHandler) ListStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		to, err := web.GetUrlQueryDate(r, "to", time.Now().UTC().Truncate(24*time.Hour))
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		from, err := web.GetUrlQueryDate(r, "from", to.AddDate(0, 0, -29))
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.PostStatListRequest{
+			PostID: id,
+			From:   from,
+			To:     to,
+		}
+
+		res, err := h.postService.ListStats(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrPostNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
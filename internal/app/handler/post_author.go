@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/validation"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type PostAuthorHandler interface {
+	List() http.HandlerFunc
+	Update() http.HandlerFunc
+	Delete() http.HandlerFunc
+}
+
+func NewPostAuthorHandler(postAuthorService service.PostAuthorService) PostAuthorHandler {
+	return &postAuthorHandler{postAuthorService}
+}
+
+type postAuthorHandler struct {
+	postAuthorService service.PostAuthorService
+}
+
+// @Router /posts/{post_id}/authors [get]
+// @Tags posts
+// @Summary List post authors
+// @Description TODO
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Success 200 {array} model.PostAuthorResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *postAuthorHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.PostAuthorListRequest{PostID: postID}
+		res, err := h.postAuthorService.List(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrPostNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /posts/{post_id}/authors/{account_id} [put]
+// @Tags posts
+// @Summary Add or change post author
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param account_id path int true "account id" Format(int64)
+// @Param payload body model.PostAuthorUpdateRequest true "body request"
+// @Success 200 {array} model.PostAuthorResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *postAuthorHandler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		accountID, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.PostAuthorUpdateRequest{PostID: postID, AccountID: accountID}
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+			return
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.postAuthorService.Update(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrPostNotFound, constant.ErrAccountNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			case constant.ErrPostLastOwner:
+				web.MarshalError(w, http.StatusConflict, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /posts/{post_id}/authors/{account_id} [delete]
+// @Tags posts
+// @Summary Remove post author
+// @Description TODO
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param account_id path int true "account id" Format(int64)
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *postAuthorHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		accountID, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.PostAuthorDeleteRequest{PostID: postID, AccountID: accountID}
+		err = h.postAuthorService.Delete(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrPostNotFound, constant.ErrPostAuthorNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			case constant.ErrPostLastOwner:
+				web.MarshalError(w, http.StatusConflict, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/validation"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+// @Router /posts/{post_id}/review/submit [post]
+// @Tags posts
+// @Summary Submit post for review
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param payload body model.PostReviewRequest false "body request"
+// @Success 200 {object} model.PostResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *postHandler) Submit() http.HandlerFunc {
+	return h.transition(constant.REVIEW_SUBMIT)
+}
+
+// @Router /posts/{post_id}/review/approve [post]
+// @Tags posts
+// @Summary Approve post
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param payload body model.PostReviewRequest false "body request"
+// @Success 200 {object} model.PostResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *postHandler) Approve() http.HandlerFunc {
+	return h.transition(constant.REVIEW_APPROVE)
+}
+
+// @Router /posts/{post_id}/review/request-changes [post]
+// @Tags posts
+// @Summary Request changes on post
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param payload body model.PostReviewRequest true "body request"
+// @Success 200 {object} model.PostResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *postHandler) RequestChanges() http.HandlerFunc {
+	return h.transition(constant.REVIEW_REQUEST_CHANGES)
+}
+
+// @Router /posts/{post_id}/publish [post]
+// @Tags posts
+// @Summary Publish post
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param payload body model.PostReviewRequest false "body request"
+// @Success 200 {object} model.PostResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *postHandler) Publish() http.HandlerFunc {
+	return h.transition(constant.REVIEW_PUBLISH)
+}
+
+// @Router /posts/{post_id}/unpublish [post]
+// @Tags posts
+// @Summary Unpublish post
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param payload body model.PostReviewRequest false "body request"
+// @Success 200 {object} model.PostResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *postHandler) Unpublish() http.HandlerFunc {
+	return h.transition(constant.REVIEW_UNPUBLISH)
+}
+
+// @Router /posts/{post_id}/reviews [get]
+// @Tags posts
+// @Summary List post reviews
+// @Description TODO
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Success 200 {array} model.PostReviewResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *postHandler) ListReviews() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.PostReviewListRequest{PostID: postID}
+		res, err := h.postService.ListReviews(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrPostNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// transition applies a review action to a post, the body is optional since only requesting changes
+// needs a comment.
+func (h *postHandler) transition(action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.PostReviewRequest{PostID: postID, Action: action}
+		if r.ContentLength != 0 {
+			err = json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+				return
+			}
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.postService.Transition(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrReviewComment, constant.ErrReviewerIsAuthor:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrPostNotFound, constant.ErrAccountNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			case constant.ErrPostTransition:
+				web.MarshalError(w, http.StatusConflict, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type ReactionHandler interface {
+	Add() http.HandlerFunc
+	Remove() http.HandlerFunc
+}
+
+func NewReactionHandler(reactionService service.ReactionService) ReactionHandler {
+	return &reactionHandler{reactionService}
+}
+
+type reactionHandler struct {
+	reactionService service.ReactionService
+}
+
+// @Router /posts/{post_id}/reactions/{reaction_type} [put]
+// @Tags reactions
+// @Summary Add reaction
+// @Description TODO
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param reaction_type path string true "reaction type" Enums(like, heart, laugh, hooray, rocket, eyes)
+// @Success 200 {array} model.ReactionResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *reactionHandler) Add() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := newReactionRequest(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.reactionService.Add(r.Context(), req)
+		if err != nil {
+			writeReactionError(w, err)
+			return
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /posts/{post_id}/reactions/{reaction_type} [delete]
+// @Tags reactions
+// @Summary Remove reaction
+// @Description TODO
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param reaction_type path string true "reaction type" Enums(like, heart, laugh, hooray, rocket, eyes)
+// @Success 200 {array} model.ReactionResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *reactionHandler) Remove() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := newReactionRequest(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.reactionService.Remove(r.Context(), req)
+		if err != nil {
+			writeReactionError(w, err)
+			return
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+func newReactionRequest(r *http.Request) (model.ReactionRequest, error) {
+	postID, err := web.GetUrlPathInt64(r, "post_id")
+	if err != nil {
+		return model.ReactionRequest{}, err
+	}
+
+	return model.ReactionRequest{
+		PostID: postID,
+		Type:   web.GetUrlPathString(r, "reaction_type"),
+	}, nil
+}
+
+func writeReactionError(w http.ResponseWriter, err error) {
+	switch err {
+	case constant.ErrReactionType:
+		web.MarshalError(w, http.StatusBadRequest, err)
+	case constant.ErrUnauthorized:
+		web.MarshalError(w, http.StatusUnauthorized, err)
+	case constant.ErrPostNotFound:
+		web.MarshalError(w, http.StatusNotFound, err)
+	default:
+		web.MarshalError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/validation"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type SeriesHandler interface {
+	Create() http.HandlerFunc
+	List() http.HandlerFunc
+	Get() http.HandlerFunc
+	Update() http.HandlerFunc
+	UpdatePosts() http.HandlerFunc
+	Delete() http.HandlerFunc
+}
+
+func NewSeriesHandler(seriesService service.SeriesService) SeriesHandler {
+	return &seriesHandler{seriesService}
+}
+
+type seriesHandler struct {
+	seriesService service.SeriesService
+}
+
+// @Router /series [post]
+// @Tags series
+// @Summary Create series
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param payload body model.SeriesCreateRequest true "body request"
+// @Success 201 {object} model.SeriesResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *seriesHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req model.SeriesCreateRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+			return
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.seriesService.Create(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusCreated, res)
+	}
+}
+
+// @Router /series [get]
+// @Tags series
+// @Summary List series
+// @Description TODO
+// @Produce json
+// @Param limit query int false "pagination limit"
+// @Param offset query int false "pagination offset"
+// @Param title query string false "series title"
+// @Success 200 {array} model.SeriesResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *seriesHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := web.GetPagination(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.SeriesListRequest{
+			Limit:  limit,
+			Offset: offset,
+			Title:  web.GetUrlQueryString(r, "title"),
+		}
+
+		res, err := h.seriesService.List(r.Context(), req)
+		if err != nil {
+			web.MarshalError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /series/{series_id} [get]
+// @Tags series
+// @Summary Get series
+// @Description TODO
+// @Produce json
+// @Param series_id path int true "series id" Format(int64)
+// @Success 200 {object} model.SeriesResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *seriesHandler) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "series_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.SeriesGetRequest{ID: id}
+		res, err := h.seriesService.Get(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrSeriesNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /series/{series_id} [put]
+// @Tags series
+// @Summary Update series
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param series_id path int true "series id" Format(int64)
+// @Param payload body model.SeriesUpdateRequest true "body request"
+// @Success 200 {object} model.SeriesResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *seriesHandler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "series_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.SeriesUpdateRequest{ID: id}
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+			return
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.seriesService.Update(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrSeriesNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /series/{series_id}/posts [put]
+// @Tags series
+// @Summary Update series posts
+// @Description TODO
+// @Accept json
+// @Produce json
+// @Param series_id path int true "series id" Format(int64)
+// @Param payload body model.SeriesPostUpdateRequest true "body request"
+// @Success 200 {object} model.SeriesResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *seriesHandler) UpdatePosts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "series_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.SeriesPostUpdateRequest{ID: id}
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+			return
+		}
+
+		err = validation.Struct(req)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res, err := h.seriesService.UpdatePosts(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrSeriesPostDuplicate:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrSeriesNotFound, constant.ErrPostNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			case constant.ErrPostInOtherSeries:
+				web.MarshalError(w, http.StatusConflict, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
+
+// @Router /series/{series_id} [delete]
+// @Tags series
+// @Summary Delete series
+// @Description TODO
+// @Produce json
+// @Param series_id path int true "series id" Format(int64)
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+func (h *seriesHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "series_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.SeriesDeleteRequest{ID: id}
+		err = h.seriesService.Delete(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrUnauthorized:
+				web.MarshalError(w, http.StatusUnauthorized, err)
+				return
+			case constant.ErrSeriesNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
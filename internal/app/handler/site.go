@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/theme"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type SiteHandler interface {
+	Index() http.HandlerFunc
+	Post() http.HandlerFunc
+	Account() http.HandlerFunc
+	Static() http.Handler
+}
+
+func NewSiteHandler(siteTheme *theme.Theme, postService service.PostService, accountService service.AccountService) SiteHandler {
+	return &siteHandler{siteTheme, postService, accountService}
+}
+
+type siteHandler struct {
+	siteTheme      *theme.Theme
+	postService    service.PostService
+	accountService service.AccountService
+}
+
+// Index renders the latest published posts, a page at a time.
+func (h *siteHandler) Index() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, valid := getPage(r)
+		if !valid {
+			h.renderError(w, http.StatusNotFound, constant.ErrPageNotFound)
+			return
+		}
+
+		posts, pagination, err := h.listPosts(r, page, 0)
+		if err != nil {
+			h.renderError(w, http.StatusInternalServerError, err)
+			return
+		} else if len(posts) == 0 && page > 1 {
+			h.renderError(w, http.StatusNotFound, constant.ErrPageNotFound)
+			return
+		}
+
+		h.render(w, http.StatusOK, "index", &model.SitePage{
+			Title:      config.Cfg().SiteTitle,
+			Posts:      posts,
+			Pagination: pagination,
+		})
+	}
+}
+
+func (h *siteHandler) Post() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			h.renderError(w, http.StatusNotFound, constant.ErrPostNotFound)
+			return
+		}
+
+		req := model.PostGetRequest{
+			ID:         id,
+			RemoteAddr: r.RemoteAddr,
+			UserAgent:  r.UserAgent(),
+		}
+		res, err := h.postService.Get(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrPostNotFound:
+				h.renderError(w, http.StatusNotFound, err)
+				return
+			default:
+				h.renderError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		h.render(w, http.StatusOK, "post", &model.SitePage{
+			Title: res.Title,
+			Post:  res,
+		})
+	}
+}
+
+// Account renders the author page, the account with the posts it published.
+func (h *siteHandler) Account() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := web.GetUrlPathInt64(r, "account_id")
+		if err != nil {
+			h.renderError(w, http.StatusNotFound, constant.ErrAccountNotFound)
+			return
+		}
+
+		page, valid := getPage(r)
+		if !valid {
+			h.renderError(w, http.StatusNotFound, constant.ErrPageNotFound)
+			return
+		}
+
+		account, err := h.accountService.Get(r.Context(), model.AccountGetRequest{ID: id})
+		if err != nil {
+			switch err {
+			case constant.ErrAccountNotFound:
+				h.renderError(w, http.StatusNotFound, err)
+				return
+			default:
+				h.renderError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		posts, pagination, err := h.listPosts(r, page, account.ID)
+		if err != nil {
+			h.renderError(w, http.StatusInternalServerError, err)
+			return
+		} else if len(posts) == 0 && page > 1 {
+			h.renderError(w, http.StatusNotFound, constant.ErrPageNotFound)
+			return
+		}
+
+		h.render(w, http.StatusOK, "account", &model.SitePage{
+			Title:      account.Name,
+			Account:    account,
+			Posts:      posts,
+			Pagination: pagination,
+		})
+	}
+}
+
+func (h *siteHandler) Static() http.Handler {
+	return http.StripPrefix("/static", h.siteTheme.Static())
+}
+
+// listPosts lists a page of published posts, one more post than shown tells whether there is a next page.
+func (h *siteHandler) listPosts(r *http.Request, page int, accountID int64) ([]*model.PostResponse, *model.SitePagination, error) {
+	limit := config.Cfg().PaginationLimit
+	req := model.PostListRequest{
+		Limit:     limit + 1,
+		Offset:    (page - 1) * limit,
+		TagMatch:  constant.TAG_MATCH_ANY,
+		Status:    constant.POST_STATUS_PUBLISHED,
+		AccountID: accountID,
+	}
+
+	posts, err := h.postService.List(r.Context(), req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &model.SitePagination{Page: page}
+	if page > 1 {
+		pagination.PrevURL = pageURL(r, page-1)
+	}
+	if len(posts) > limit {
+		posts = posts[:limit]
+		pagination.NextURL = pageURL(r, page+1)
+	}
+	return posts, pagination, nil
+}
+
+func (h *siteHandler) render(w http.ResponseWriter, code int, name string, page *model.SitePage) {
+	site := strings.TrimSuffix(config.Cfg().SiteUrl, "/")
+	page.Site = &model.SiteInfo{
+		Title:         config.Cfg().SiteTitle,
+		Description:   config.Cfg().SiteDescription,
+		URL:           site,
+		FeedURL:       site + "/v1/feed.atom",
+		WebmentionURL: site + "/v1/webmention",
+	}
+	if page.Status == 0 {
+		page.Status = code
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="webmention"`, page.Site.WebmentionURL))
+
+	var buf bytes.Buffer
+	err := h.siteTheme.Render(&buf, name, page)
+	if err != nil {
+		logger.Log().Err(err).Msgf("failed to render %s page", name)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(code)
+	buf.WriteTo(w)
+}
+
+func (h *siteHandler) renderError(w http.ResponseWriter, code int, err error) {
+	h.render(w, code, "error", &model.SitePage{
+		Title:   http.StatusText(code),
+		Message: err.Error(),
+	})
+}
+
+// getPage reads the 1-based page number of a listing, the first page has no page segment. Pages are part
+// of the path rather than the query so the listings can be exported as static files.
+func getPage(r *http.Request) (int, bool) {
+	value := web.GetUrlPathString(r, "page")
+	if value == "" {
+		return 1, true
+	}
+
+	page, err := strconv.Atoi(value)
+	return page, err == nil && page > 0
+}
+
+// pageURL builds the path of another page of the listing, e.g. /accounts/1/page/2.
+func pageURL(r *http.Request, page int) string {
+	base := r.URL.Path
+	if i := strings.LastIndex(base, "/page/"); i >= 0 {
+		base = base[:i]
+	}
+	base = strings.TrimSuffix(base, "/")
+
+	if page == 1 {
+		if base == "" {
+			return "/"
+		}
+		return base
+	}
+	return fmt.Sprintf("%s/page/%d", base, page)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/sitemap"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type SitemapHandler interface {
+	Get() http.HandlerFunc
+	GetPage() http.HandlerFunc
+}
+
+func NewSitemapHandler(sitemapService service.SitemapService) SitemapHandler {
+	return &sitemapHandler{sitemapService}
+}
+
+type sitemapHandler struct {
+	sitemapService service.SitemapService
+}
+
+// Get serves /sitemap.xml, it is mounted outside of the API since a sitemap may only list URLs below its
+// own location.
+func (h *sitemapHandler) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, model.SitemapGetRequest{})
+	}
+}
+
+// GetPage serves the /sitemap-{page}.xml pages listed by the sitemap index.
+func (h *sitemapHandler) GetPage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := web.GetUrlPathInt(r, "page")
+		if err != nil || page < 1 {
+			web.MarshalError(w, http.StatusNotFound, constant.ErrSitemapNotFound)
+			return
+		}
+
+		h.serve(w, r, model.SitemapGetRequest{Page: page})
+	}
+}
+
+func (h *sitemapHandler) serve(w http.ResponseWriter, r *http.Request, req model.SitemapGetRequest) {
+	res, err := h.sitemapService.Get(r.Context(), req)
+	if err != nil {
+		switch err {
+		case constant.ErrSitemapNotFound:
+			web.MarshalError(w, http.StatusNotFound, err)
+			return
+		default:
+			web.MarshalError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	web.ServeDocument(w, r, sitemap.ContentType, res.Modified, res.Data)
+}
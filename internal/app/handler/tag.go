@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type TagHandler interface {
+	List() http.HandlerFunc
+}
+
+func NewTagHandler(tagService service.TagService) TagHandler {
+	return &tagHandler{tagService}
+}
+
+type tagHandler struct {
+	tagService service.TagService
+}
+
+// @Router /tags [get]
+// @Tags tags
+// @Summary List tags
+// @Description TODO
+// @Produce json
+// @Param limit query int false "pagination limit"
+// @Param offset query int false "pagination offset"
+// @Param name query string false "tag name"
+// @Success 200 {array} model.TagResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *tagHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset, err := web.GetPagination(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.TagListRequest{
+			Limit:  limit,
+			Offset: offset,
+			Name:   web.GetUrlQueryString(r, "name"),
+		}
+
+		res, err := h.tagService.List(r.Context(), req)
+		if err != nil {
+			web.MarshalError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/web"
+)
+
+type WebmentionHandler interface {
+	Create() http.HandlerFunc
+	List() http.HandlerFunc
+}
+
+func NewWebmentionHandler(webmentionService service.WebmentionService) WebmentionHandler {
+	return &webmentionHandler{webmentionService}
+}
+
+type webmentionHandler struct {
+	webmentionService service.WebmentionService
+}
+
+// @Router /webmention [post]
+// @Tags webmentions
+// @Summary Receive webmention
+// @Description TODO
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param source formData string true "URL of the page mentioning the post"
+// @Param target formData string true "URL of the mentioned post"
+// @Success 202
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *webmentionHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, constant.ErrRequestBody)
+			return
+		}
+
+		req := model.WebmentionCreateRequest{
+			Source: r.PostForm.Get("source"),
+			Target: r.PostForm.Get("target"),
+		}
+
+		err = h.webmentionService.Create(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrWebmentionSource, constant.ErrWebmentionTarget:
+				web.MarshalError(w, http.StatusBadRequest, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// @Router /posts/{post_id}/webmentions [get]
+// @Tags webmentions
+// @Summary List webmentions of post
+// @Description TODO
+// @Produce json
+// @Param post_id path int true "post id" Format(int64)
+// @Param limit query int false "pagination limit"
+// @Param offset query int false "pagination offset"
+// @Success 200 {array} model.WebmentionResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+func (h *webmentionHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		postID, err := web.GetUrlPathInt64(r, "post_id")
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		limit, offset, err := web.GetPagination(r)
+		if err != nil {
+			web.MarshalError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		req := model.WebmentionListRequest{
+			PostID: postID,
+			Limit:  limit,
+			Offset: offset,
+		}
+
+		res, err := h.webmentionService.List(r.Context(), req)
+		if err != nil {
+			switch err {
+			case constant.ErrPostNotFound:
+				web.MarshalError(w, http.StatusNotFound, err)
+				return
+			default:
+				web.MarshalError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		web.MarshalPayload(w, http.StatusOK, res)
+	}
+}
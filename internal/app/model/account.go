@@ -12,12 +12,14 @@ type Account struct {
 	Name      string
 	Email     string
 	Password  string
+	Role      string
 	CreatedAt time.Time
 	UpdatedAt sql.NullTime
+	Version   int64
 }
 
 func (a *Account) GenerateClaims() jwt.MapClaims {
-	return jwt.MapClaims{"id": a.ID}
+	return jwt.MapClaims{"id": a.ID, "role": a.Role}
 }
 
 type AccountCreateRequest struct {
@@ -37,27 +39,32 @@ type AccountGetRequest struct {
 }
 
 type AccountUpdateRequest struct {
-	ID    int64  `json:"-"`
-	Name  string `json:"name" validate:"required"`
-	Email string `json:"email" validate:"required,email"`
+	ID      int64  `json:"-"`
+	Version int64  `json:"-"`
+	Name    string `json:"name" validate:"required"`
+	Email   string `json:"email" validate:"required,email"`
 }
 
 type AccountPasswordUpdateRequest struct {
 	ID          int64  `json:"-"`
+	Version     int64  `json:"-"`
 	OldPassword string `json:"old_password" validate:"required,gte=8"`
 	NewPassword string `json:"new_password" validate:"required,gte=8"`
 }
 
 type AccountDeleteRequest struct {
-	ID int64
+	ID      int64
+	Version int64
 }
 
 type AccountResponse struct {
 	ID        int64      `json:"id"`
 	Name      string     `json:"name"`
 	Email     string     `json:"email"`
+	Role      string     `json:"role"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at"`
+	Version   int64      `json:"-"`
 }
 
 func NewAccountResponse(payload *Account) *AccountResponse {
@@ -65,7 +72,9 @@ func NewAccountResponse(payload *Account) *AccountResponse {
 		ID:        payload.ID,
 		Name:      payload.Name,
 		Email:     payload.Email,
+		Role:      payload.Role,
 		CreatedAt: payload.CreatedAt,
+		Version:   payload.Version,
 	}
 	if payload.UpdatedAt.Valid {
 		res.UpdatedAt = &payload.UpdatedAt.Time
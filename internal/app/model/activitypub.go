@@ -0,0 +1,71 @@
+package model
+
+import (
+	"net/http"
+	"time"
+)
+
+type ActorKey struct {
+	AccountID  int64
+	PublicKey  string
+	PrivateKey string
+	CreatedAt  time.Time
+}
+
+type Follower struct {
+	AccountID int64
+	Actor     string
+	Inbox     string
+	CreatedAt time.Time
+}
+
+// ActivityDelivery is an activity waiting to be posted to a remote inbox.
+type ActivityDelivery struct {
+	ID            int64
+	AccountID     int64
+	Inbox         string
+	Activity      []byte
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+}
+
+type WebFingerRequest struct {
+	Resource string
+}
+
+type WebFingerResponse struct {
+	Subject string          `json:"subject"`
+	Aliases []string        `json:"aliases"`
+	Links   []WebFingerLink `json:"links"`
+}
+
+type WebFingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href"`
+}
+
+type ActorGetRequest struct {
+	AccountID int64
+}
+
+type OutboxGetRequest struct {
+	AccountID int64
+}
+
+type FollowersGetRequest struct {
+	AccountID int64
+}
+
+type ObjectGetRequest struct {
+	PostID int64
+}
+
+// InboxRequest is a signed request posted to the inbox of an actor, the body is kept as is to check its digest.
+type InboxRequest struct {
+	AccountID int64
+	Request   *http.Request
+	Body      []byte
+}
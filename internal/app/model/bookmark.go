@@ -0,0 +1,119 @@
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+type Bookmark struct {
+	Folder    string
+	CreatedAt time.Time
+
+	AccountID int64
+
+	PostID int64
+	Post   Post
+}
+
+type BookmarkFolder struct {
+	Name  string
+	Count int64
+}
+
+// BookmarkCursor points at the last bookmark of a page, bookmarks are ordered from the
+// newest to the oldest with the post id breaking ties.
+type BookmarkCursor struct {
+	CreatedAt time.Time
+	PostID    int64
+}
+
+func (c *BookmarkCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.CreatedAt.UnixNano(), c.PostID)))
+}
+
+func DecodeBookmarkCursor(s string) (*BookmarkCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var nsec, postID int64
+	_, err = fmt.Sscanf(string(b), "%d:%d", &nsec, &postID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BookmarkCursor{CreatedAt: time.Unix(0, nsec).UTC(), PostID: postID}, nil
+}
+
+type BookmarkCreateRequest struct {
+	AccountID int64  `json:"-"`
+	PostID    int64  `json:"-"`
+	Folder    string `json:"folder" validate:"max=255"`
+}
+
+type BookmarkListRequest struct {
+	AccountID int64
+	Limit     int
+	Cursor    *BookmarkCursor
+	Folder    *string
+}
+
+type BookmarkFolderListRequest struct {
+	AccountID int64
+}
+
+type BookmarkDeleteRequest struct {
+	AccountID int64
+	PostID    int64
+}
+
+type BookmarkResponse struct {
+	Folder    string        `json:"folder"`
+	CreatedAt time.Time     `json:"created_at"`
+	Post      *PostResponse `json:"post"`
+}
+
+func NewBookmarkResponse(payload *Bookmark) *BookmarkResponse {
+	return &BookmarkResponse{
+		Folder:    payload.Folder,
+		CreatedAt: payload.CreatedAt,
+		Post:      NewPostResponse(&payload.Post),
+	}
+}
+
+type BookmarkListResponse struct {
+	Bookmarks  []*BookmarkResponse `json:"bookmarks"`
+	NextCursor *string             `json:"next_cursor"`
+}
+
+// NewBookmarkListResponse expects one bookmark more than the page size when a next page exists.
+func NewBookmarkListResponse(payloads []*Bookmark, limit int) *BookmarkListResponse {
+	res := &BookmarkListResponse{Bookmarks: []*BookmarkResponse{}}
+	if len(payloads) > limit {
+		payloads = payloads[:limit]
+		if limit > 0 {
+			last := payloads[limit-1]
+			cursor := (&BookmarkCursor{CreatedAt: last.CreatedAt, PostID: last.PostID}).Encode()
+			res.NextCursor = &cursor
+		}
+	}
+	for _, payload := range payloads {
+		res.Bookmarks = append(res.Bookmarks, NewBookmarkResponse(payload))
+	}
+	return res
+}
+
+type BookmarkFolderResponse struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+func NewBookmarkFolderListResponse(payloads []*BookmarkFolder) []*BookmarkFolderResponse {
+	res := make([]*BookmarkFolderResponse, len(payloads))
+	for i, payload := range payloads {
+		res[i] = &BookmarkFolderResponse{Name: payload.Name, Count: payload.Count}
+	}
+	return res
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Category struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+
+	ParentID sql.NullInt64
+}
+
+type CategoryCreateRequest struct {
+	Name     string `json:"name" validate:"required,max=255"`
+	ParentID *int64 `json:"parent_id"`
+}
+
+type CategoryListRequest struct {
+	Limit    int
+	Offset   int
+	Name     string
+	ParentID *int64
+}
+
+type CategoryGetRequest struct {
+	ID int64
+}
+
+type CategoryUpdateRequest struct {
+	ID       int64  `json:"-"`
+	Name     string `json:"name" validate:"required,max=255"`
+	ParentID *int64 `json:"parent_id"`
+}
+
+type CategoryDeleteRequest struct {
+	ID int64
+}
+
+type CategoryPostListRequest struct {
+	ID     int64
+	Limit  int
+	Offset int
+}
+
+type CategoryResponse struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+
+	ParentID *int64 `json:"parent_id"`
+}
+
+func NewCategoryResponse(payload *Category) *CategoryResponse {
+	res := &CategoryResponse{
+		ID:        payload.ID,
+		Name:      payload.Name,
+		CreatedAt: payload.CreatedAt,
+	}
+	if payload.UpdatedAt.Valid {
+		res.UpdatedAt = &payload.UpdatedAt.Time
+	}
+	if payload.ParentID.Valid {
+		res.ParentID = &payload.ParentID.Int64
+	}
+	return res
+}
+
+func NewCategoryListResponse(payloads []*Category) []*CategoryResponse {
+	res := make([]*CategoryResponse, len(payloads))
+	for i, payload := range payloads {
+		res[i] = NewCategoryResponse(payload)
+	}
+	return res
+}
+
+type BreadcrumbResponse struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func NewBreadcrumbListResponse(payloads []Category) []*BreadcrumbResponse {
+	res := make([]*BreadcrumbResponse, len(payloads))
+	for i, payload := range payloads {
+		res[i] = &BreadcrumbResponse{ID: payload.ID, Name: payload.Name}
+	}
+	return res
+}
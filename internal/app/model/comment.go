@@ -0,0 +1,104 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Comment struct {
+	ID        int64
+	Body      string
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+
+	PostID int64
+
+	AccountID int64
+	Account   Account
+
+	ParentID sql.NullInt64
+}
+
+type CommentCreateRequest struct {
+	PostID   int64  `json:"-"`
+	ParentID *int64 `json:"parent_id"`
+	Body     string `json:"body" validate:"required"`
+}
+
+type CommentListRequest struct {
+	PostID int64
+	Limit  int
+	Offset int
+	Tree   bool
+}
+
+type CommentUpdateRequest struct {
+	ID     int64  `json:"-"`
+	PostID int64  `json:"-"`
+	Body   string `json:"body" validate:"required"`
+}
+
+type CommentDeleteRequest struct {
+	ID     int64
+	PostID int64
+}
+
+type CommentResponse struct {
+	ID        int64      `json:"id"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+
+	PostID int64 `json:"post_id"`
+
+	AccountID int64            `json:"account_id"`
+	Account   *AccountResponse `json:"account"`
+
+	ParentID *int64             `json:"parent_id"`
+	Replies  []*CommentResponse `json:"replies,omitempty"`
+}
+
+func NewCommentResponse(payload *Comment) *CommentResponse {
+	res := &CommentResponse{
+		ID:        payload.ID,
+		Body:      payload.Body,
+		CreatedAt: payload.CreatedAt,
+		PostID:    payload.PostID,
+		AccountID: payload.AccountID,
+		Account:   NewAccountResponse(&payload.Account),
+	}
+	if payload.UpdatedAt.Valid {
+		res.UpdatedAt = &payload.UpdatedAt.Time
+	}
+	if payload.ParentID.Valid {
+		res.ParentID = &payload.ParentID.Int64
+	}
+	return res
+}
+
+func NewCommentListResponse(payloads []*Comment) []*CommentResponse {
+	res := make([]*CommentResponse, len(payloads))
+	for i, payload := range payloads {
+		res[i] = NewCommentResponse(payload)
+	}
+	return res
+}
+
+// NewCommentTreeResponse nests comments under their parents, payloads must be ordered so
+// that parents come before their replies. Comments whose parent is missing become roots.
+func NewCommentTreeResponse(payloads []*Comment) []*CommentResponse {
+	res := []*CommentResponse{}
+	nodes := make(map[int64]*CommentResponse, len(payloads))
+	for _, payload := range payloads {
+		node := NewCommentResponse(payload)
+		nodes[node.ID] = node
+
+		parent, found := nodes[payload.ParentID.Int64]
+		if payload.ParentID.Valid && found {
+			parent.Replies = append(parent.Replies, node)
+		} else {
+			res = append(res, node)
+		}
+	}
+	return res
+}
@@ -0,0 +1,14 @@
+package model
+
+type FeedRequest struct {
+	AccountID int64
+	Content   string
+	Path      string
+}
+
+type FeedListRequest struct {
+	Limit   int
+	Offset  int
+	Content string
+	Path    string
+}
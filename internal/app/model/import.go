@@ -0,0 +1,110 @@
+package model
+
+import (
+	"database/sql"
+	"io"
+	"time"
+)
+
+// Import is content brought in from another blog, it is written in a single transaction. Accounts and
+// categories which already exist are matched instead of created, their ID is set once written.
+type Import struct {
+	Accounts   []*ImportAccount
+	Categories []*ImportCategory
+	Posts      []*ImportPost
+}
+
+// ImportAccount is matched by email, Password is the hash used when the account has to be created.
+type ImportAccount struct {
+	ID       int64
+	Name     string
+	Email    string
+	Password string
+}
+
+// ImportCategory is matched by name below its parent, parents come before their children in Import.
+type ImportCategory struct {
+	ID     int64
+	Name   string
+	Parent *ImportCategory
+}
+
+// ImportPost is matched by Slug when it has one, matching posts are updated instead of created. Posts
+// without a slug are only created, unless the account already has a post with the same title and date.
+type ImportPost struct {
+	ID          int64
+	Slug        string
+	Title       string
+	Body        string
+	Status      string
+	Tags        []string
+	CreatedAt   time.Time
+	PublishedAt sql.NullTime
+
+	Account  *ImportAccount
+	Category *ImportCategory
+}
+
+type ImportResult struct {
+	AccountsCreated   int
+	AccountsMatched   int
+	CategoriesCreated int
+	CategoriesMatched int
+	PostsCreated      int
+	PostsUpdated      int
+	PostsExisting     int
+}
+
+type WordPressImportRequest struct {
+	File   io.Reader
+	DryRun bool
+}
+
+// MarkdownFile is a Markdown document with front matter, Name is its file name.
+type MarkdownFile struct {
+	Name string
+	Data []byte
+}
+
+type MarkdownImportRequest struct {
+	Files  []*MarkdownFile
+	DryRun bool
+}
+
+type MarkdownExportRequest struct {
+	Format string
+}
+
+// ImportResponse summarizes an import, with dry run nothing was written and the counts tell what would be.
+type ImportResponse struct {
+	DryRun            bool     `json:"dry_run"`
+	AccountsCreated   int      `json:"accounts_created"`
+	AccountsMatched   int      `json:"accounts_matched"`
+	CategoriesCreated int      `json:"categories_created"`
+	CategoriesMatched int      `json:"categories_matched"`
+	Tags              int      `json:"tags"`
+	PostsCreated      int      `json:"posts_created"`
+	PostsUpdated      int      `json:"posts_updated"`
+	PostsExisting     int      `json:"posts_existing"`
+	PostsSkipped      int      `json:"posts_skipped"`
+	Warnings          []string `json:"warnings"`
+}
+
+func NewImportResponse(payload *ImportResult, dryRun bool, tags int, skipped int, warnings []string) *ImportResponse {
+	if warnings == nil {
+		warnings = []string{}
+	}
+	return &ImportResponse{
+		DryRun:            dryRun,
+		AccountsCreated:   payload.AccountsCreated,
+		AccountsMatched:   payload.AccountsMatched,
+		CategoriesCreated: payload.CategoriesCreated,
+		CategoriesMatched: payload.CategoriesMatched,
+		Tags:              tags,
+		PostsCreated:      payload.PostsCreated,
+		PostsUpdated:      payload.PostsUpdated,
+		PostsExisting:     payload.PostsExisting,
+		PostsSkipped:      skipped,
+		Warnings:          warnings,
+	}
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/constant"
+)
+
+type Media struct {
+	ID          int64
+	Key         string
+	Filename    string
+	ContentType string
+	Size        int64
+	Width       sql.NullInt64
+	Height      sql.NullInt64
+	AltText     string
+	Caption     string
+	CreatedAt   time.Time
+	UpdatedAt   sql.NullTime
+
+	AccountID int64
+
+	Variants []MediaVariant
+	Usages   []MediaUsage
+
+	URL string
+}
+
+type MediaVariant struct {
+	Name        string
+	Key         string
+	ContentType string
+	Size        int64
+	Width       int64
+	Height      int64
+
+	URL string
+}
+
+type MediaUsage struct {
+	PostID int64
+	Title  string
+	Kind   string
+}
+
+type MediaCreateRequest struct {
+	Filename string
+	Size     int64
+	File     io.Reader
+
+	AltText string `validate:"max=1000"`
+	Caption string `validate:"max=1000"`
+}
+
+type MediaListRequest struct {
+	Limit  int
+	Offset int
+	Query  string
+}
+
+type MediaGetRequest struct {
+	ID int64
+}
+
+type MediaUpdateRequest struct {
+	ID      int64  `json:"-"`
+	AltText string `json:"alt_text" validate:"max=1000"`
+	Caption string `json:"caption" validate:"max=1000"`
+}
+
+type MediaDeleteRequest struct {
+	ID    int64
+	Force bool
+}
+
+type MediaResponse struct {
+	ID          int64      `json:"id"`
+	URL         string     `json:"url"`
+	Filename    string     `json:"filename"`
+	ContentType string     `json:"content_type"`
+	Size        int64      `json:"size"`
+	Width       *int64     `json:"width"`
+	Height      *int64     `json:"height"`
+	AltText     string     `json:"alt_text"`
+	Caption     string     `json:"caption"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+
+	AccountID int64 `json:"account_id"`
+
+	Variants []*MediaVariantResponse `json:"variants"`
+	Srcset   string                  `json:"srcset"`
+
+	Usages []*MediaUsageResponse `json:"usages"`
+}
+
+func NewMediaResponse(payload *Media) *MediaResponse {
+	res := &MediaResponse{
+		ID:          payload.ID,
+		URL:         payload.URL,
+		Filename:    payload.Filename,
+		ContentType: payload.ContentType,
+		Size:        payload.Size,
+		AltText:     payload.AltText,
+		Caption:     payload.Caption,
+		CreatedAt:   payload.CreatedAt,
+		AccountID:   payload.AccountID,
+		Variants:    make([]*MediaVariantResponse, len(payload.Variants)),
+		Usages:      make([]*MediaUsageResponse, len(payload.Usages)),
+	}
+	if payload.UpdatedAt.Valid {
+		res.UpdatedAt = &payload.UpdatedAt.Time
+	}
+	if payload.Width.Valid {
+		res.Width = &payload.Width.Int64
+	}
+	if payload.Height.Valid {
+		res.Height = &payload.Height.Int64
+	}
+
+	// the srcset offers every resized variant plus the original, thumbnails are cropped so they are left out
+	var srcset []string
+	for i := range payload.Variants {
+		variant := &payload.Variants[i]
+		res.Variants[i] = NewMediaVariantResponse(variant)
+		if variant.Name != constant.MEDIA_VARIANT_THUMBNAIL {
+			srcset = append(srcset, fmt.Sprintf("%s %dw", variant.URL, variant.Width))
+		}
+	}
+	if len(srcset) > 0 && payload.Width.Valid {
+		srcset = append(srcset, fmt.Sprintf("%s %dw", payload.URL, payload.Width.Int64))
+	}
+	res.Srcset = strings.Join(srcset, ", ")
+
+	for i := range payload.Usages {
+		res.Usages[i] = NewMediaUsageResponse(&payload.Usages[i])
+	}
+	return res
+}
+
+func NewMediaListResponse(payloads []*Media) []*MediaResponse {
+	res := make([]*MediaResponse, len(payloads))
+	for i, payload := range payloads {
+		res[i] = NewMediaResponse(payload)
+	}
+	return res
+}
+
+type MediaVariantResponse struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Width       int64  `json:"width"`
+	Height      int64  `json:"height"`
+}
+
+func NewMediaVariantResponse(payload *MediaVariant) *MediaVariantResponse {
+	return &MediaVariantResponse{
+		Name:        payload.Name,
+		URL:         payload.URL,
+		ContentType: payload.ContentType,
+		Size:        payload.Size,
+		Width:       payload.Width,
+		Height:      payload.Height,
+	}
+}
+
+type MediaUsageResponse struct {
+	PostID int64  `json:"post_id"`
+	Title  string `json:"title"`
+	Kind   string `json:"kind"`
+}
+
+func NewMediaUsageResponse(payload *MediaUsage) *MediaUsageResponse {
+	return &MediaUsageResponse{
+		PostID: payload.PostID,
+		Title:  payload.Title,
+		Kind:   payload.Kind,
+	}
+}
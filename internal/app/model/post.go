@@ -12,33 +12,81 @@ type Post struct {
 	CreatedAt time.Time
 	UpdatedAt sql.NullTime
 
+	Status      string
+	PublishedAt sql.NullTime
+	Version     int64
+
 	AccountID int64
 	Account   Account
+
+	Authors []PostAuthor
+
+	Tags []string
+
+	CategoryID  sql.NullInt64
+	Breadcrumbs []Category
+
+	CoverMediaID sql.NullInt64
+
+	Series *Series
+
+	CommentCount int64
+
+	Reactions []Reaction
 }
 
 type PostCreateRequest struct {
-	Title string `json:"title" validate:"required"`
-	Body  string `json:"body" validate:"required"`
+	Title string   `json:"title" validate:"required"`
+	Body  string   `json:"body" validate:"required"`
+	Tags  []string `json:"tags" validate:"dive,max=255"`
+
+	CategoryID   *int64 `json:"category_id"`
+	CoverMediaID *int64 `json:"cover_media_id"`
 }
 
 type PostListRequest struct {
+	Limit     int
+	Offset    int
+	Title     string
+	Tags      []string
+	TagMatch  string
+	Status    string
+	AccountID int64
+}
+
+type PostTrendingListRequest struct {
+	Limit  int
+	Offset int
+	Window string
+}
+
+type PostSearchRequest struct {
 	Limit  int
 	Offset int
-	Title  string
+	Query  string `validate:"required"`
 }
 
 type PostGetRequest struct {
 	ID int64
+
+	RemoteAddr string
+	UserAgent  string
 }
 
 type PostUpdateRequest struct {
-	ID    int64  `json:"-"`
-	Title string `json:"title" validate:"required"`
-	Body  string `json:"body" validate:"required"`
+	ID      int64    `json:"-"`
+	Version int64    `json:"-"`
+	Title   string   `json:"title" validate:"required"`
+	Body    string   `json:"body" validate:"required"`
+	Tags    []string `json:"tags" validate:"dive,max=255"`
+
+	CategoryID   *int64 `json:"category_id"`
+	CoverMediaID *int64 `json:"cover_media_id"`
 }
 
 type PostDeleteRequest struct {
-	ID int64
+	ID      int64
+	Version int64
 }
 
 type PostResponse struct {
@@ -48,22 +96,85 @@ type PostResponse struct {
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at"`
 
+	Status      string     `json:"status"`
+	PublishedAt *time.Time `json:"published_at"`
+	Version     int64      `json:"-"`
+
 	AccountID int64            `json:"account_id"`
 	Account   *AccountResponse `json:"account"`
+
+	Authors []*PostAuthorResponse `json:"authors"`
+
+	Tags []string `json:"tags"`
+
+	CategoryID  *int64                `json:"category_id"`
+	Breadcrumbs []*BreadcrumbResponse `json:"breadcrumbs"`
+
+	CoverMediaID *int64 `json:"cover_media_id"`
+
+	Series *SeriesNavigationResponse `json:"series,omitempty"`
+
+	CommentCount int64 `json:"comment_count"`
+
+	Reactions []*ReactionResponse `json:"reactions"`
+
+	Meta *PostMetaResponse `json:"meta,omitempty"`
+}
+
+// PostMetaResponse is the Open Graph and Twitter card metadata of a post, it is what link previews of the
+// post show.
+type PostMetaResponse struct {
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	URL           string     `json:"url"`
+	Type          string     `json:"type"`
+	SiteName      string     `json:"site_name"`
+	Image         string     `json:"image,omitempty"`
+	ImageAlt      string     `json:"image_alt,omitempty"`
+	ImageWidth    int64      `json:"image_width,omitempty"`
+	ImageHeight   int64      `json:"image_height,omitempty"`
+	Authors       []string   `json:"authors"`
+	Tags          []string   `json:"tags"`
+	PublishedTime *time.Time `json:"published_time,omitempty"`
+	ModifiedTime  *time.Time `json:"modified_time,omitempty"`
+	TwitterCard   string     `json:"twitter_card"`
+	OEmbedURL     string     `json:"oembed_url"`
 }
 
 func NewPostResponse(payload *Post) *PostResponse {
 	res := &PostResponse{
-		ID:        payload.ID,
-		Title:     payload.Title,
-		Body:      payload.Body,
-		CreatedAt: payload.CreatedAt,
-		AccountID: payload.AccountID,
-		Account:   NewAccountResponse(&payload.Account),
+		ID:           payload.ID,
+		Title:        payload.Title,
+		Body:         payload.Body,
+		CreatedAt:    payload.CreatedAt,
+		Status:       payload.Status,
+		Version:      payload.Version,
+		AccountID:    payload.AccountID,
+		Account:      NewAccountResponse(&payload.Account),
+		Authors:      NewPostAuthorListResponse(payload.Authors),
+		Tags:         payload.Tags,
+		CommentCount: payload.CommentCount,
+		Reactions:    NewReactionListResponse(payload.Reactions),
+	}
+	if res.Tags == nil {
+		res.Tags = []string{}
 	}
 	if payload.UpdatedAt.Valid {
 		res.UpdatedAt = &payload.UpdatedAt.Time
 	}
+	if payload.PublishedAt.Valid {
+		res.PublishedAt = &payload.PublishedAt.Time
+	}
+	if payload.CategoryID.Valid {
+		res.CategoryID = &payload.CategoryID.Int64
+	}
+	res.Breadcrumbs = NewBreadcrumbListResponse(payload.Breadcrumbs)
+	if payload.CoverMediaID.Valid {
+		res.CoverMediaID = &payload.CoverMediaID.Int64
+	}
+	if payload.Series != nil {
+		res.Series = NewSeriesNavigationResponse(payload.Series, payload.ID)
+	}
 	return res
 }
 
@@ -74,3 +185,34 @@ func NewPostListResponse(payloads []*Post) []*PostResponse {
 	}
 	return res
 }
+
+type PostSearchResult struct {
+	Post           Post
+	Rank           float32
+	TitleHighlight string
+	BodySnippet    string
+}
+
+type PostSearchResponse struct {
+	Post           *PostResponse `json:"post"`
+	Rank           float32       `json:"rank"`
+	TitleHighlight string        `json:"title_highlight"`
+	BodySnippet    string        `json:"body_snippet"`
+}
+
+func NewPostSearchResponse(payload *PostSearchResult) *PostSearchResponse {
+	return &PostSearchResponse{
+		Post:           NewPostResponse(&payload.Post),
+		Rank:           payload.Rank,
+		TitleHighlight: payload.TitleHighlight,
+		BodySnippet:    payload.BodySnippet,
+	}
+}
+
+func NewPostSearchListResponse(payloads []*PostSearchResult) []*PostSearchResponse {
+	res := make([]*PostSearchResponse, len(payloads))
+	for i, payload := range payloads {
+		res[i] = NewPostSearchResponse(payload)
+	}
+	return res
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+type PostAuthor struct {
+	AccountID int64
+	Name      string
+	Role      string
+	CreatedAt time.Time
+}
+
+type PostAuthorListRequest struct {
+	PostID int64
+}
+
+type PostAuthorUpdateRequest struct {
+	PostID    int64  `json:"-"`
+	AccountID int64  `json:"-"`
+	Role      string `json:"role" validate:"required,oneof=owner co-author reviewer"`
+}
+
+type PostAuthorDeleteRequest struct {
+	PostID    int64
+	AccountID int64
+}
+
+type PostAuthorResponse struct {
+	AccountID int64     `json:"account_id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewPostAuthorResponse(payload *PostAuthor) *PostAuthorResponse {
+	return &PostAuthorResponse{
+		AccountID: payload.AccountID,
+		Name:      payload.Name,
+		Role:      payload.Role,
+		CreatedAt: payload.CreatedAt,
+	}
+}
+
+func NewPostAuthorListResponse(payloads []PostAuthor) []*PostAuthorResponse {
+	res := make([]*PostAuthorResponse, len(payloads))
+	for i := range payloads {
+		res[i] = NewPostAuthorResponse(&payloads[i])
+	}
+	return res
+}
@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+type PostReview struct {
+	ID        int64
+	Action    string
+	Comment   string
+	CreatedAt time.Time
+
+	PostID int64
+
+	AccountID int64
+	Account   Account
+}
+
+type PostReviewRequest struct {
+	PostID     int64  `json:"-"`
+	Action     string `json:"-"`
+	ReviewerID *int64 `json:"reviewer_id,omitempty"`
+	Comment    string `json:"comment" validate:"max=5000"`
+}
+
+type PostReviewListRequest struct {
+	PostID int64
+}
+
+type PostReviewResponse struct {
+	ID        int64     `json:"id"`
+	Action    string    `json:"action"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+
+	PostID int64 `json:"post_id"`
+
+	AccountID int64            `json:"account_id"`
+	Account   *AccountResponse `json:"account"`
+}
+
+func NewPostReviewResponse(payload *PostReview) *PostReviewResponse {
+	return &PostReviewResponse{
+		ID:        payload.ID,
+		Action:    payload.Action,
+		Comment:   payload.Comment,
+		CreatedAt: payload.CreatedAt,
+		PostID:    payload.PostID,
+		AccountID: payload.AccountID,
+		Account:   NewAccountResponse(&payload.Account),
+	}
+}
+
+func NewPostReviewListResponse(payloads []*PostReview) []*PostReviewResponse {
+	res := make([]*PostReviewResponse, len(payloads))
+	for i, payload := range payloads {
+		res[i] = NewPostReviewResponse(payload)
+	}
+	return res
+}
@@ -0,0 +1,34 @@
+package model
+
+type Reaction struct {
+	Type        string
+	Count       int64
+	ReactedByMe bool
+}
+
+type ReactionRequest struct {
+	PostID int64
+	Type   string
+}
+
+type ReactionResponse struct {
+	Type        string `json:"type"`
+	Count       int64  `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
+
+func NewReactionResponse(payload *Reaction) *ReactionResponse {
+	return &ReactionResponse{
+		Type:        payload.Type,
+		Count:       payload.Count,
+		ReactedByMe: payload.ReactedByMe,
+	}
+}
+
+func NewReactionListResponse(payloads []Reaction) []*ReactionResponse {
+	res := make([]*ReactionResponse, len(payloads))
+	for i := range payloads {
+		res[i] = NewReactionResponse(&payloads[i])
+	}
+	return res
+}
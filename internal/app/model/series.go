@@ -0,0 +1,141 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Series struct {
+	ID          int64
+	Title       string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   sql.NullTime
+
+	AccountID int64
+
+	Posts []SeriesPost
+}
+
+type SeriesPost struct {
+	PostID   int64
+	Title    string
+	Status   string
+	Position int
+}
+
+type SeriesCreateRequest struct {
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description"`
+}
+
+type SeriesListRequest struct {
+	Limit  int
+	Offset int
+	Title  string
+}
+
+type SeriesGetRequest struct {
+	ID int64
+}
+
+type SeriesUpdateRequest struct {
+	ID          int64  `json:"-"`
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description"`
+}
+
+type SeriesPostUpdateRequest struct {
+	ID      int64   `json:"-"`
+	PostIDs []int64 `json:"post_ids" validate:"dive,gt=0"`
+}
+
+type SeriesDeleteRequest struct {
+	ID int64
+}
+
+type SeriesPostResponse struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	Part  int    `json:"part"`
+}
+
+func NewSeriesPostResponse(payload *SeriesPost) *SeriesPostResponse {
+	return &SeriesPostResponse{
+		ID:    payload.PostID,
+		Title: payload.Title,
+		Part:  payload.Position,
+	}
+}
+
+type SeriesResponse struct {
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+
+	AccountID int64 `json:"account_id"`
+
+	Posts []*SeriesPostResponse `json:"posts"`
+}
+
+func NewSeriesResponse(payload *Series) *SeriesResponse {
+	res := &SeriesResponse{
+		ID:          payload.ID,
+		Title:       payload.Title,
+		Description: payload.Description,
+		CreatedAt:   payload.CreatedAt,
+		AccountID:   payload.AccountID,
+		Posts:       make([]*SeriesPostResponse, len(payload.Posts)),
+	}
+	if payload.UpdatedAt.Valid {
+		res.UpdatedAt = &payload.UpdatedAt.Time
+	}
+	for i := range payload.Posts {
+		res.Posts[i] = NewSeriesPostResponse(&payload.Posts[i])
+	}
+	return res
+}
+
+func NewSeriesListResponse(payloads []*Series) []*SeriesResponse {
+	res := make([]*SeriesResponse, len(payloads))
+	for i, payload := range payloads {
+		res[i] = NewSeriesResponse(payload)
+	}
+	return res
+}
+
+type SeriesNavigationResponse struct {
+	ID       int64               `json:"id"`
+	Title    string              `json:"title"`
+	Part     int                 `json:"part"`
+	Total    int                 `json:"total"`
+	Previous *SeriesPostResponse `json:"previous"`
+	Next     *SeriesPostResponse `json:"next"`
+}
+
+// NewSeriesNavigationResponse describes where the given post sits within the series,
+// it returns nil when the post is not part of the series.
+func NewSeriesNavigationResponse(payload *Series, postID int64) *SeriesNavigationResponse {
+	for i := range payload.Posts {
+		if payload.Posts[i].PostID != postID {
+			continue
+		}
+
+		res := &SeriesNavigationResponse{
+			ID:    payload.ID,
+			Title: payload.Title,
+			Part:  payload.Posts[i].Position,
+			Total: len(payload.Posts),
+		}
+		if i > 0 {
+			res.Previous = NewSeriesPostResponse(&payload.Posts[i-1])
+		}
+		if i < len(payload.Posts)-1 {
+			res.Next = NewSeriesPostResponse(&payload.Posts[i+1])
+		}
+		return res
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package model
+
+// SiteInfo describes the blog to the theme, the links are absolute.
+type SiteInfo struct {
+	Title         string
+	Description   string
+	URL           string
+	FeedURL       string
+	WebmentionURL string
+}
+
+type SitePagination struct {
+	Page    int
+	PrevURL string
+	NextURL string
+}
+
+// SitePage is the data every theme template is executed with, each page only fills the fields it shows.
+type SitePage struct {
+	Site       *SiteInfo
+	Title      string
+	Post       *PostResponse
+	Posts      []*PostResponse
+	Account    *AccountResponse
+	Pagination *SitePagination
+	Status     int
+	Message    string
+}
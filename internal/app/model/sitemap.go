@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+type SitemapEntry struct {
+	PostID       int64
+	LastModified time.Time
+}
+
+// SitemapDocument is a rendered sitemap or sitemap index as it is cached.
+type SitemapDocument struct {
+	Data     []byte
+	Modified time.Time
+}
+
+type SitemapGetRequest struct {
+	Page int
+}
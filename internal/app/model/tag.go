@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+)
+
+type Tag struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+
+	PostCount int64
+}
+
+type TagListRequest struct {
+	Limit  int
+	Offset int
+	Name   string
+}
+
+type TagResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	PostCount int64     `json:"post_count"`
+}
+
+func NewTagResponse(payload *Tag) *TagResponse {
+	return &TagResponse{
+		ID:        payload.ID,
+		Name:      payload.Name,
+		CreatedAt: payload.CreatedAt,
+		PostCount: payload.PostCount,
+	}
+}
+
+func NewTagListResponse(payloads []*Tag) []*TagResponse {
+	res := make([]*TagResponse, len(payloads))
+	for i, payload := range payloads {
+		res[i] = NewTagResponse(payload)
+	}
+	return res
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+)
+
+type PostStat struct {
+	PostID  int64
+	Day     time.Time
+	Views   int64
+	Uniques int64
+}
+
+type PostStatListRequest struct {
+	PostID int64
+	From   time.Time
+	To     time.Time
+}
+
+type PostStatResponse struct {
+	Day     string `json:"day"`
+	Views   int64  `json:"views"`
+	Uniques int64  `json:"uniques"`
+}
+
+func NewPostStatResponse(payload *PostStat) *PostStatResponse {
+	return &PostStatResponse{
+		Day:     payload.Day.Format("2006-01-02"),
+		Views:   payload.Views,
+		Uniques: payload.Uniques,
+	}
+}
+
+func NewPostStatListResponse(payloads []*PostStat) []*PostStatResponse {
+	res := make([]*PostStatResponse, len(payloads))
+	for i, payload := range payloads {
+		res[i] = NewPostStatResponse(payload)
+	}
+	return res
+}
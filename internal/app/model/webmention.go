@@ -0,0 +1,68 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Webmention struct {
+	ID          int64
+	PostID      int64
+	Source      string
+	Target      string
+	Status      string
+	Attempts    int
+	NextCheckAt sql.NullTime
+	CreatedAt   time.Time
+	VerifiedAt  sql.NullTime
+}
+
+// WebmentionDelivery is a mention of a linked page waiting to be sent to its endpoint.
+type WebmentionDelivery struct {
+	ID            int64
+	PostID        int64
+	Source        string
+	Target        string
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+}
+
+type WebmentionCreateRequest struct {
+	Source string
+	Target string
+}
+
+type WebmentionListRequest struct {
+	PostID int64
+	Limit  int
+	Offset int
+}
+
+type WebmentionResponse struct {
+	ID         int64      `json:"id"`
+	Source     string     `json:"source"`
+	CreatedAt  time.Time  `json:"created_at"`
+	VerifiedAt *time.Time `json:"verified_at"`
+}
+
+func NewWebmentionResponse(payload *Webmention) *WebmentionResponse {
+	res := &WebmentionResponse{
+		ID:        payload.ID,
+		Source:    payload.Source,
+		CreatedAt: payload.CreatedAt,
+	}
+	if payload.VerifiedAt.Valid {
+		res.VerifiedAt = &payload.VerifiedAt.Time
+	}
+	return res
+}
+
+func NewWebmentionListResponse(payloads []*Webmention) []*WebmentionResponse {
+	res := make([]*WebmentionResponse, len(payloads))
+	for i, payload := range payloads {
+		res[i] = NewWebmentionResponse(payload)
+	}
+	return res
+}
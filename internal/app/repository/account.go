@@ -9,6 +9,7 @@ import (
 	"github.com/anonychun/go-blog-api/internal/db/postgres"
 	"github.com/anonychun/go-blog-api/internal/db/redis"
 	cache "github.com/go-redis/cache/v8"
+	pgx "github.com/jackc/pgx/v4"
 )
 
 type AccountRepository interface {
@@ -17,7 +18,7 @@ type AccountRepository interface {
 	Get(ctx context.Context, id int64) (*model.Account, error)
 	GetByEmail(ctx context.Context, email string) (*model.Account, error)
 	Update(ctx context.Context, account *model.Account) error
-	Delete(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64, version int64) error
 }
 
 func NewAccountRepository(postgresClient postgres.Client, redisClient redis.Client) AccountRepository {
@@ -56,7 +57,7 @@ func (r *accountRepository) Create(ctx context.Context, account *model.Account)
 func (r *accountRepository) List(ctx context.Context, limit, offset int, name string) ([]*model.Account, error) {
 	query := `
 	SELECT
-		id, name, email, created_at, updated_at
+		id, name, email, role, created_at, updated_at, version
 	FROM
 		account
 	WHERE
@@ -76,7 +77,7 @@ func (r *accountRepository) List(ctx context.Context, limit, offset int, name st
 	var accounts []*model.Account
 	for rows.Next() {
 		account := new(model.Account)
-		err := rows.Scan(&account.ID, &account.Name, &account.Email, &account.CreatedAt, &account.UpdatedAt)
+		err := rows.Scan(&account.ID, &account.Name, &account.Email, &account.Role, &account.CreatedAt, &account.UpdatedAt, &account.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -97,7 +98,7 @@ func (r *accountRepository) Get(ctx context.Context, id int64) (*model.Account,
 
 	query := `
 	SELECT
-		id, name, email, password, created_at, updated_at
+		id, name, email, password, role, created_at, updated_at, version
 	FROM
 		account
 	WHERE
@@ -107,8 +108,10 @@ func (r *accountRepository) Get(ctx context.Context, id int64) (*model.Account,
 		&account.ID,
 		&account.Name, &account.Email,
 		&account.Password,
+		&account.Role,
 		&account.CreatedAt,
-		&account.UpdatedAt)
+		&account.UpdatedAt,
+		&account.Version)
 	if err != nil {
 		return nil, err
 	}
@@ -132,7 +135,7 @@ func (r *accountRepository) GetByEmail(ctx context.Context, email string) (*mode
 
 	query := `
 	SELECT
-		id, name, email, password, created_at, updated_at
+		id, name, email, password, role, created_at, updated_at, version
 	FROM
 		account
 	WHERE
@@ -143,8 +146,10 @@ func (r *accountRepository) GetByEmail(ctx context.Context, email string) (*mode
 		&account.Name,
 		&account.Email,
 		&account.Password,
+		&account.Role,
 		&account.CreatedAt,
-		&account.UpdatedAt)
+		&account.UpdatedAt,
+		&account.Version)
 	if err != nil {
 		return nil, err
 	}
@@ -157,23 +162,28 @@ func (r *accountRepository) GetByEmail(ctx context.Context, email string) (*mode
 	})
 }
 
+// Update saves the account when it is still at the version it was read at, pgx.ErrNoRows is returned
+// when it was changed in the meantime.
 func (r *accountRepository) Update(ctx context.Context, account *model.Account) error {
 	query := `
 	UPDATE
 		account
 	SET
-		name = $1, email = $2, password = $3, updated_at = $4
+		name = $1, email = $2, password = $3, updated_at = $4, version = version + 1
 	WHERE
-		id = $5`
+		id = $5 AND version = $6`
 
-	_, err := r.postgresClient.Conn().Exec(ctx, query,
+	tag, err := r.postgresClient.Conn().Exec(ctx, query,
 		account.Name,
 		account.Email,
 		account.Password,
 		account.UpdatedAt.Time,
-		account.ID)
+		account.ID,
+		account.Version)
 	if err != nil {
 		return err
+	} else if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
 	}
 
 	err = r.redisClient.Cache().Delete(ctx, fmt.Sprintf("account_%d", account.ID))
@@ -186,16 +196,19 @@ func (r *accountRepository) Update(ctx context.Context, account *model.Account)
 	return err
 }
 
-func (r *accountRepository) Delete(ctx context.Context, id int64) error {
+// Delete removes the account if it is still at the given version, pgx.ErrNoRows is returned otherwise.
+func (r *accountRepository) Delete(ctx context.Context, id int64, version int64) error {
 	query := `
 	DELETE FROM
 		account
 	WHERE
-		id = $1`
+		id = $1 AND version = $2`
 
-	_, err := r.postgresClient.Conn().Exec(ctx, query, id)
+	tag, err := r.postgresClient.Conn().Exec(ctx, query, id, version)
 	if err != nil {
 		return err
+	} else if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
 	}
 
 	err = r.redisClient.Cache().Delete(ctx, fmt.Sprintf("account_%d", id))
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+)
+
+type ActivityPubRepository interface {
+	GetKey(ctx context.Context, accountID int64) (*model.ActorKey, error)
+	CreateKey(ctx context.Context, key *model.ActorKey) error
+	SetFollower(ctx context.Context, follower *model.Follower) error
+	DeleteFollower(ctx context.Context, accountID int64, actor string) error
+	CountFollowers(ctx context.Context, accountID int64) (int, error)
+	Enqueue(ctx context.Context, delivery *model.ActivityDelivery) error
+	EnqueueForFollowers(ctx context.Context, accountID int64, activity []byte, createdAt time.Time) error
+	ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.ActivityDelivery, error)
+	RetryDelivery(ctx context.Context, delivery *model.ActivityDelivery) error
+	DeleteDelivery(ctx context.Context, id int64) error
+}
+
+func NewActivityPubRepository(postgresClient postgres.Client) ActivityPubRepository {
+	return &activityPubRepository{postgresClient}
+}
+
+type activityPubRepository struct {
+	postgresClient postgres.Client
+}
+
+func (r *activityPubRepository) GetKey(ctx context.Context, accountID int64) (*model.ActorKey, error) {
+	query := `
+	SELECT
+		account_id, public_key, private_key, created_at
+	FROM
+		actor_key
+	WHERE
+		account_id = $1`
+
+	key := new(model.ActorKey)
+	err := r.postgresClient.Conn().QueryRow(ctx, query, accountID).Scan(
+		&key.AccountID, &key.PublicKey, &key.PrivateKey, &key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// CreateKey stores the key pair of an actor, a key stored concurrently for the same account is kept.
+func (r *activityPubRepository) CreateKey(ctx context.Context, key *model.ActorKey) error {
+	query := `
+	INSERT INTO
+		actor_key (account_id, public_key, private_key, created_at)
+	VALUES
+		($1, $2, $3, $4)
+	ON CONFLICT (account_id) DO NOTHING`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query,
+		key.AccountID, key.PublicKey, key.PrivateKey, key.CreatedAt,
+	)
+	return err
+}
+
+// SetFollower adds a remote actor to the followers of an account, following again only updates the inbox.
+func (r *activityPubRepository) SetFollower(ctx context.Context, follower *model.Follower) error {
+	query := `
+	INSERT INTO
+		follower (account_id, actor, inbox, created_at)
+	VALUES
+		($1, $2, $3, $4)
+	ON CONFLICT (account_id, actor) DO UPDATE SET
+		inbox = EXCLUDED.inbox`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query,
+		follower.AccountID, follower.Actor, follower.Inbox, follower.CreatedAt,
+	)
+	return err
+}
+
+func (r *activityPubRepository) DeleteFollower(ctx context.Context, accountID int64, actor string) error {
+	query := `
+	DELETE FROM
+		follower
+	WHERE
+		account_id = $1 AND actor = $2`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query, accountID, actor)
+	return err
+}
+
+func (r *activityPubRepository) CountFollowers(ctx context.Context, accountID int64) (int, error) {
+	query := `
+	SELECT
+		COUNT(*)
+	FROM
+		follower
+	WHERE
+		account_id = $1`
+
+	var count int
+	err := r.postgresClient.Conn().QueryRow(ctx, query, accountID).Scan(&count)
+	return count, err
+}
+
+func (r *activityPubRepository) Enqueue(ctx context.Context, delivery *model.ActivityDelivery) error {
+	query := `
+	INSERT INTO
+		activity_delivery (account_id, inbox, activity, next_attempt_at, created_at)
+	VALUES
+		($1, $2, $3, $4, $5)
+	RETURNING
+		id`
+
+	return r.postgresClient.Conn().QueryRow(ctx, query,
+		delivery.AccountID, delivery.Inbox, string(delivery.Activity), delivery.NextAttemptAt, delivery.CreatedAt,
+	).Scan(&delivery.ID)
+}
+
+// EnqueueForFollowers queues an activity for every follower of an account, followers sharing an inbox
+// receive it once.
+func (r *activityPubRepository) EnqueueForFollowers(ctx context.Context, accountID int64, activity []byte, createdAt time.Time) error {
+	query := `
+	INSERT INTO
+		activity_delivery (account_id, inbox, activity, next_attempt_at, created_at)
+	SELECT DISTINCT
+		$1::int, inbox, $2, $3::timestamp, $3::timestamp
+	FROM
+		follower
+	WHERE
+		account_id = $1`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query, accountID, string(activity), createdAt)
+	return err
+}
+
+// ClaimDeliveries returns the deliveries that are due and postpones them until leaseUntil, so a
+// delivery is not sent twice when several workers run at once.
+func (r *activityPubRepository) ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.ActivityDelivery, error) {
+	query := `
+	UPDATE
+		activity_delivery
+	SET
+		next_attempt_at = $2
+	WHERE
+		id IN (
+			SELECT
+				id
+			FROM
+				activity_delivery
+			WHERE
+				next_attempt_at <= $1
+			ORDER BY
+				next_attempt_at
+			LIMIT
+				$3
+			FOR UPDATE SKIP LOCKED
+		)
+	RETURNING
+		id, account_id, inbox, activity, attempts, last_error, next_attempt_at, created_at`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*model.ActivityDelivery
+	for rows.Next() {
+		delivery := new(model.ActivityDelivery)
+		var activity string
+		err := rows.Scan(
+			&delivery.ID, &delivery.AccountID, &delivery.Inbox, &activity,
+			&delivery.Attempts, &delivery.LastError, &delivery.NextAttemptAt, &delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		delivery.Activity = []byte(activity)
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (r *activityPubRepository) RetryDelivery(ctx context.Context, delivery *model.ActivityDelivery) error {
+	query := `
+	UPDATE
+		activity_delivery
+	SET
+		attempts = $2, last_error = $3, next_attempt_at = $4
+	WHERE
+		id = $1`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query,
+		delivery.ID, delivery.Attempts, delivery.LastError, delivery.NextAttemptAt,
+	)
+	return err
+}
+
+func (r *activityPubRepository) DeleteDelivery(ctx context.Context, id int64) error {
+	query := `
+	DELETE FROM
+		activity_delivery
+	WHERE
+		id = $1`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query, id)
+	return err
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+)
+
+type BookmarkRepository interface {
+	Create(ctx context.Context, bookmark *model.Bookmark) error
+	List(ctx context.Context, accountID int64, limit int, cursor *model.BookmarkCursor, folder *string) ([]*model.Bookmark, error)
+	ListFolders(ctx context.Context, accountID int64) ([]*model.BookmarkFolder, error)
+	Delete(ctx context.Context, accountID, postID int64) error
+}
+
+func NewBookmarkRepository(postgresClient postgres.Client) BookmarkRepository {
+	return &bookmarkRepository{postgresClient}
+}
+
+type bookmarkRepository struct {
+	postgresClient postgres.Client
+}
+
+// Create bookmarks a post, bookmarking it again only moves it to the requested folder.
+func (r *bookmarkRepository) Create(ctx context.Context, bookmark *model.Bookmark) error {
+	query := `
+	INSERT INTO
+		bookmark (account_id, post_id, folder, created_at)
+	VALUES
+		($1, $2, $3, $4)
+	ON CONFLICT (account_id, post_id) DO UPDATE SET
+		folder = EXCLUDED.folder
+	RETURNING
+		created_at`
+
+	return r.postgresClient.Conn().QueryRow(ctx, query,
+		bookmark.AccountID,
+		bookmark.PostID,
+		bookmark.Folder,
+		bookmark.CreatedAt,
+	).Scan(
+		&bookmark.CreatedAt)
+}
+
+// List returns the bookmarked posts which are published, unpublished ones show up again once they are
+// published.
+func (r *bookmarkRepository) List(ctx context.Context, accountID int64, limit int, cursor *model.BookmarkCursor, folder *string) ([]*model.Bookmark, error) {
+	var cursorCreatedAt *time.Time
+	var cursorPostID *int64
+	if cursor != nil {
+		cursorCreatedAt = &cursor.CreatedAt
+		cursorPostID = &cursor.PostID
+	}
+
+	query := `
+	SELECT` + postColumns + `,
+		bookmark.folder,
+		bookmark.created_at,
+		bookmark.account_id
+	FROM
+		bookmark
+	INNER JOIN
+		post ON bookmark.post_id = post.id
+	INNER JOIN
+		account	ON post.account_id = account.id
+	WHERE
+		bookmark.account_id = $1
+	AND
+		post.status = 'published'
+	AND
+		($2::text IS NULL OR bookmark.folder = $2)
+	AND
+		($3::timestamp IS NULL OR (bookmark.created_at, bookmark.post_id) < ($3, $4::int))
+	ORDER BY
+		bookmark.created_at DESC, bookmark.post_id DESC
+	LIMIT
+		$5`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query,
+		accountID,
+		folder,
+		cursorCreatedAt,
+		cursorPostID,
+		limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookmarks []*model.Bookmark
+	for rows.Next() {
+		bookmark := new(model.Bookmark)
+		err := scanPost(rows, &bookmark.Post,
+			&bookmark.Folder,
+			&bookmark.CreatedAt,
+			&bookmark.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		bookmark.PostID = bookmark.Post.ID
+		bookmarks = append(bookmarks, bookmark)
+	}
+
+	return bookmarks, nil
+}
+
+func (r *bookmarkRepository) ListFolders(ctx context.Context, accountID int64) ([]*model.BookmarkFolder, error) {
+	query := `
+	SELECT
+		folder, COUNT(*)
+	FROM
+		bookmark
+	WHERE
+		account_id = $1
+	GROUP BY
+		folder
+	ORDER BY
+		folder`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var folders []*model.BookmarkFolder
+	for rows.Next() {
+		folder := new(model.BookmarkFolder)
+		err := rows.Scan(&folder.Name, &folder.Count)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, folder)
+	}
+
+	return folders, nil
+}
+
+func (r *bookmarkRepository) Delete(ctx context.Context, accountID, postID int64) error {
+	query := `
+	DELETE FROM
+		bookmark
+	WHERE
+		account_id = $1 AND post_id = $2`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query, accountID, postID)
+	return err
+}
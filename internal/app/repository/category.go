@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	cache "github.com/go-redis/cache/v8"
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *model.Category) error
+	List(ctx context.Context, limit, offset int, name string, parentID *int64) ([]*model.Category, error)
+	ListDescendantIDs(ctx context.Context, id int64) ([]int64, error)
+	Get(ctx context.Context, id int64) (*model.Category, error)
+	CountChildren(ctx context.Context, id int64) (int64, error)
+	Update(ctx context.Context, category *model.Category) error
+	Delete(ctx context.Context, id int64) error
+}
+
+func NewCategoryRepository(postgresClient postgres.Client, redisClient redis.Client) CategoryRepository {
+	return &categoryRepository{postgresClient, redisClient}
+}
+
+type categoryRepository struct {
+	postgresClient postgres.Client
+	redisClient    redis.Client
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *model.Category) error {
+	query := `
+	INSERT INTO
+		category (name, parent_id, created_at)
+	VALUES
+		($1, $2, $3)
+	RETURNING
+		id`
+
+	err := r.postgresClient.Conn().QueryRow(ctx, query,
+		category.Name,
+		category.ParentID,
+		category.CreatedAt,
+	).Scan(
+		&category.ID)
+	if err != nil {
+		return err
+	}
+
+	temp, err := r.Get(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	*category = *temp
+	return nil
+}
+
+func (r *categoryRepository) List(ctx context.Context, limit, offset int, name string, parentID *int64) ([]*model.Category, error) {
+	query := `
+	SELECT
+		id, name, created_at, updated_at, parent_id
+	FROM
+		category
+	WHERE
+		name LIKE $1
+	AND
+		($4::int IS NULL OR parent_id = $4)
+	ORDER BY
+		name
+	LIMIT
+		$2 OFFSET $3`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query,
+		"%"+name+"%",
+		limit,
+		offset,
+		parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*model.Category
+	for rows.Next() {
+		category := new(model.Category)
+		err := rows.Scan(
+			&category.ID,
+			&category.Name,
+			&category.CreatedAt,
+			&category.UpdatedAt,
+			&category.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, nil
+}
+
+// ListDescendantIDs returns the ids of the given category and every category below it.
+func (r *categoryRepository) ListDescendantIDs(ctx context.Context, id int64) ([]int64, error) {
+	query := `
+	WITH RECURSIVE descendant AS (
+		SELECT
+			id
+		FROM
+			category
+		WHERE
+			id = $1
+		UNION ALL
+		SELECT
+			category.id
+		FROM
+			category
+		INNER JOIN
+			descendant ON category.parent_id = descendant.id
+	)
+	SELECT
+		id
+	FROM
+		descendant`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (r *categoryRepository) Get(ctx context.Context, id int64) (*model.Category, error) {
+	category := new(model.Category)
+	err := r.redisClient.Cache().Get(ctx, fmt.Sprintf("category_%d", id), category)
+	if err != nil && err != cache.ErrCacheMiss {
+		return nil, err
+	} else if err == nil {
+		return category, nil
+	}
+
+	query := `
+	SELECT
+		id, name, created_at, updated_at, parent_id
+	FROM
+		category
+	WHERE
+		id = $1`
+
+	err = r.postgresClient.Conn().QueryRow(ctx, query, id).Scan(
+		&category.ID,
+		&category.Name,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+		&category.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return category, r.redisClient.Cache().Set(&cache.Item{
+		Ctx:   ctx,
+		Key:   fmt.Sprintf("category_%d", id),
+		Value: category,
+		TTL:   config.Cfg().RedisTTL,
+	})
+}
+
+func (r *categoryRepository) CountChildren(ctx context.Context, id int64) (int64, error) {
+	query := `
+	SELECT
+		COUNT(*)
+	FROM
+		category
+	WHERE
+		parent_id = $1`
+
+	var count int64
+	err := r.postgresClient.Conn().QueryRow(ctx, query, id).Scan(&count)
+	return count, err
+}
+
+// Update changes a category, the cached posts filed under it or below it are invalidated since they embed
+// its breadcrumbs.
+func (r *categoryRepository) Update(ctx context.Context, category *model.Category) error {
+	query := `
+	UPDATE
+		category
+	SET
+		name = $1, parent_id = $2, updated_at = $3
+	WHERE
+		id = $4`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query,
+		category.Name,
+		category.ParentID,
+		category.UpdatedAt.Time,
+		category.ID)
+	if err != nil {
+		return err
+	}
+
+	err = r.redisClient.Cache().Delete(ctx, fmt.Sprintf("category_%d", category.ID))
+	if err != nil && err != cache.ErrCacheMiss {
+		return err
+	}
+
+	postIDs, err := r.listPostIDs(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, postID := range postIDs {
+		err = r.redisClient.Cache().Delete(ctx, fmt.Sprintf("post_%d", postID))
+		if err != nil && err != cache.ErrCacheMiss {
+			return err
+		}
+	}
+
+	temp, err := r.Get(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	*category = *temp
+	return nil
+}
+
+// Delete removes a category, its posts are left without a category and their cache is invalidated.
+func (r *categoryRepository) Delete(ctx context.Context, id int64) error {
+	// the posts have to be listed while they still refer to the category
+	postIDs, err := r.listPostIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	DELETE FROM
+		category
+	WHERE
+		id = $1`
+
+	_, err = r.postgresClient.Conn().Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	err = r.redisClient.Cache().Delete(ctx, fmt.Sprintf("category_%d", id))
+	if err != nil && err != cache.ErrCacheMiss {
+		return err
+	}
+
+	for _, postID := range postIDs {
+		err = r.redisClient.Cache().Delete(ctx, fmt.Sprintf("post_%d", postID))
+		if err != nil && err != cache.ErrCacheMiss {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listPostIDs returns the ids of the posts filed under the given category or any category below it.
+func (r *categoryRepository) listPostIDs(ctx context.Context, id int64) ([]int64, error) {
+	query := `
+	WITH RECURSIVE descendant AS (
+		SELECT
+			id
+		FROM
+			category
+		WHERE
+			id = $1
+		UNION ALL
+		SELECT
+			category.id
+		FROM
+			category
+		INNER JOIN
+			descendant ON category.parent_id = descendant.id
+	)
+	SELECT
+		post.id
+	FROM
+		post
+	INNER JOIN
+		descendant ON post.category_id = descendant.id`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
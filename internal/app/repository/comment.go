@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	cache "github.com/go-redis/cache/v8"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *model.Comment) error
+	List(ctx context.Context, postID int64, limit, offset int) ([]*model.Comment, error)
+	ListTree(ctx context.Context, postID int64, limit, offset int) ([]*model.Comment, error)
+	Get(ctx context.Context, id int64) (*model.Comment, error)
+	Update(ctx context.Context, comment *model.Comment) error
+	Delete(ctx context.Context, id int64) error
+}
+
+func NewCommentRepository(postgresClient postgres.Client, redisClient redis.Client) CommentRepository {
+	return &commentRepository{postgresClient, redisClient}
+}
+
+type commentRepository struct {
+	postgresClient postgres.Client
+	redisClient    redis.Client
+}
+
+const commentColumns = `
+		comment.id,
+		comment.body,
+		comment.created_at,
+		comment.updated_at,
+		comment.post_id,
+		comment.account_id,
+		comment.parent_id,
+		account.id,
+		account.name,
+		account.email,
+		account.password,
+		account.role,
+		account.created_at,
+		account.updated_at`
+
+func scanComment(row pgx.Row, comment *model.Comment) error {
+	return row.Scan(
+		&comment.ID,
+		&comment.Body,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.PostID,
+		&comment.AccountID,
+		&comment.ParentID,
+		&comment.Account.ID,
+		&comment.Account.Name,
+		&comment.Account.Email,
+		&comment.Account.Password,
+		&comment.Account.Role,
+		&comment.Account.CreatedAt,
+		&comment.Account.UpdatedAt)
+}
+
+func (r *commentRepository) Create(ctx context.Context, comment *model.Comment) error {
+	query := `
+	INSERT INTO
+		comment (body, post_id, account_id, parent_id, created_at)
+	VALUES
+		($1, $2, $3, $4, $5)
+	RETURNING
+		id`
+
+	err := r.postgresClient.Conn().QueryRow(ctx, query,
+		comment.Body,
+		comment.PostID,
+		comment.AccountID,
+		comment.ParentID,
+		comment.CreatedAt,
+	).Scan(
+		&comment.ID)
+	if err != nil {
+		return err
+	}
+
+	err = r.deletePostCache(ctx, comment.PostID)
+	if err != nil {
+		return err
+	}
+
+	temp, err := r.Get(ctx, comment.ID)
+	if err != nil {
+		return err
+	}
+	*comment = *temp
+	return nil
+}
+
+func (r *commentRepository) List(ctx context.Context, postID int64, limit, offset int) ([]*model.Comment, error) {
+	query := `
+	SELECT` + commentColumns + `
+	FROM
+		comment
+	INNER JOIN
+		account ON comment.account_id = account.id
+	WHERE
+		comment.post_id = $1
+	ORDER BY
+		comment.created_at, comment.id
+	LIMIT
+		$2 OFFSET $3`
+
+	return r.list(ctx, query, postID, limit, offset)
+}
+
+// ListTree paginates over top level comments and returns them together with all of their
+// replies, parents are always ordered before their replies.
+func (r *commentRepository) ListTree(ctx context.Context, postID int64, limit, offset int) ([]*model.Comment, error) {
+	query := `
+	WITH RECURSIVE thread AS (
+		SELECT
+			*
+		FROM (
+			SELECT
+				comment.id, comment.created_at, 0 AS depth
+			FROM
+				comment
+			WHERE
+				comment.post_id = $1 AND comment.parent_id IS NULL
+			ORDER BY
+				comment.created_at, comment.id
+			LIMIT
+				$2 OFFSET $3
+		) AS root
+		UNION ALL
+		SELECT
+			comment.id, comment.created_at, thread.depth + 1
+		FROM
+			comment
+		INNER JOIN
+			thread ON comment.parent_id = thread.id
+	)
+	SELECT` + commentColumns + `
+	FROM
+		thread
+	INNER JOIN
+		comment ON comment.id = thread.id
+	INNER JOIN
+		account ON comment.account_id = account.id
+	ORDER BY
+		thread.depth, comment.created_at, comment.id`
+
+	return r.list(ctx, query, postID, limit, offset)
+}
+
+func (r *commentRepository) Get(ctx context.Context, id int64) (*model.Comment, error) {
+	query := `
+	SELECT` + commentColumns + `
+	FROM
+		comment
+	INNER JOIN
+		account ON comment.account_id = account.id
+	WHERE
+		comment.id = $1`
+
+	comment := new(model.Comment)
+	err := scanComment(r.postgresClient.Conn().QueryRow(ctx, query, id), comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func (r *commentRepository) Update(ctx context.Context, comment *model.Comment) error {
+	query := `
+	UPDATE
+		comment
+	SET
+		body = $1, updated_at = $2
+	WHERE
+		id = $3`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query,
+		comment.Body,
+		comment.UpdatedAt.Time,
+		comment.ID)
+	if err != nil {
+		return err
+	}
+
+	temp, err := r.Get(ctx, comment.ID)
+	if err != nil {
+		return err
+	}
+	*comment = *temp
+	return nil
+}
+
+func (r *commentRepository) Delete(ctx context.Context, id int64) error {
+	query := `
+	DELETE FROM
+		comment
+	WHERE
+		id = $1
+	RETURNING
+		post_id`
+
+	var postID int64
+	err := r.postgresClient.Conn().QueryRow(ctx, query, id).Scan(&postID)
+	if err != nil {
+		return err
+	}
+
+	return r.deletePostCache(ctx, postID)
+}
+
+func (r *commentRepository) list(ctx context.Context, query string, args ...interface{}) ([]*model.Comment, error) {
+	rows, err := r.postgresClient.Conn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*model.Comment
+	for rows.Next() {
+		comment := new(model.Comment)
+		err := scanComment(rows, comment)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, nil
+}
+
+// deletePostCache drops the cached post so that its comment count is refreshed.
+func (r *commentRepository) deletePostCache(ctx context.Context, postID int64) error {
+	err := r.redisClient.Cache().Delete(ctx, fmt.Sprintf("post_%d", postID))
+	if err != nil && err != cache.ErrCacheMiss {
+		return err
+	}
+	return nil
+}
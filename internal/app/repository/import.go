@@ -0,0 +1,420 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	cache "github.com/go-redis/cache/v8"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type ImportRepository interface {
+	Import(ctx context.Context, data *model.Import, dryRun bool) (*model.ImportResult, error)
+	ListPosts(ctx context.Context) ([]*model.ImportPost, error)
+}
+
+// outcomes of importing a single post
+const (
+	postCreated = iota
+	postUpdated
+	postExisting
+)
+
+func NewImportRepository(postgresClient postgres.Client, redisClient redis.Client) ImportRepository {
+	return &importRepository{postgresClient, redisClient, &postRepository{postgresClient, redisClient}}
+}
+
+type importRepository struct {
+	postgresClient postgres.Client
+	redisClient    redis.Client
+	postRepository *postRepository
+}
+
+// Import writes the accounts, categories and posts in a single transaction. With dryRun everything is
+// written the same way and the transaction is rolled back, so the result tells exactly what would happen.
+// Importing twice does not duplicate posts, see model.ImportPost for how they are matched.
+func (r *importRepository) Import(ctx context.Context, data *model.Import, dryRun bool) (*model.ImportResult, error) {
+	tx, err := r.postgresClient.Conn().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	res := &model.ImportResult{}
+	for _, account := range data.Accounts {
+		created, err := r.importAccount(ctx, tx, account)
+		if err != nil {
+			return nil, err
+		} else if created {
+			res.AccountsCreated++
+		} else {
+			res.AccountsMatched++
+		}
+	}
+
+	for _, category := range data.Categories {
+		created, err := r.importCategory(ctx, tx, category)
+		if err != nil {
+			return nil, err
+		} else if created {
+			res.CategoriesCreated++
+		} else {
+			res.CategoriesMatched++
+		}
+	}
+
+	var updated []int64
+	for _, post := range data.Posts {
+		outcome, err := r.importPost(ctx, tx, post)
+		if err != nil {
+			return nil, err
+		}
+
+		switch outcome {
+		case postCreated:
+			res.PostsCreated++
+		case postUpdated:
+			res.PostsUpdated++
+			updated = append(updated, post.ID)
+		default:
+			res.PostsExisting++
+		}
+	}
+
+	if dryRun {
+		return res, nil
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range updated {
+		err = r.redisClient.Cache().Delete(ctx, fmt.Sprintf("post_%d", id))
+		if err != nil && err != cache.ErrCacheMiss {
+			return nil, err
+		}
+	}
+	return res, invalidateSitemaps(ctx, r.redisClient)
+}
+
+func (r *importRepository) importAccount(ctx context.Context, tx pgx.Tx, account *model.ImportAccount) (bool, error) {
+	query := `
+	SELECT
+		id
+	FROM
+		account
+	WHERE
+		LOWER(email) = LOWER($1)
+	ORDER BY
+		id
+	LIMIT
+		1`
+
+	err := tx.QueryRow(ctx, query, account.Email).Scan(&account.ID)
+	if err != pgx.ErrNoRows {
+		return false, err
+	}
+
+	query = `
+	INSERT INTO
+		account (name, email, password)
+	VALUES
+		($1, $2, $3)
+	RETURNING
+		id`
+
+	err = tx.QueryRow(ctx, query,
+		account.Name,
+		account.Email,
+		account.Password,
+	).Scan(
+		&account.ID)
+	return true, err
+}
+
+func (r *importRepository) importCategory(ctx context.Context, tx pgx.Tx, category *model.ImportCategory) (bool, error) {
+	var parentID *int64
+	if category.Parent != nil {
+		parentID = &category.Parent.ID
+	}
+
+	query := `
+	SELECT
+		id
+	FROM
+		category
+	WHERE
+		name = $1 AND parent_id IS NOT DISTINCT FROM $2
+	ORDER BY
+		id
+	LIMIT
+		1`
+
+	err := tx.QueryRow(ctx, query, category.Name, parentID).Scan(&category.ID)
+	if err != pgx.ErrNoRows {
+		return false, err
+	}
+
+	query = `
+	INSERT INTO
+		category (name, parent_id)
+	VALUES
+		($1, $2)
+	RETURNING
+		id`
+
+	err = tx.QueryRow(ctx, query,
+		category.Name,
+		parentID,
+	).Scan(
+		&category.ID)
+	return true, err
+}
+
+func (r *importRepository) importPost(ctx context.Context, tx pgx.Tx, post *model.ImportPost) (int, error) {
+	if post.Slug != "" {
+		current, err := r.matchPost(ctx, tx, post)
+		if err == nil {
+			post.ID = current.ID
+			return r.updatePost(ctx, tx, current, post)
+		} else if err != pgx.ErrNoRows {
+			return 0, err
+		}
+	} else {
+		query := `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					post
+				WHERE
+					account_id = $1 AND title = $2 AND created_at = $3
+			)`
+
+		var exists bool
+		err := tx.QueryRow(ctx, query,
+			post.Account.ID,
+			post.Title,
+			post.CreatedAt,
+		).Scan(
+			&exists)
+		if err != nil || exists {
+			return postExisting, err
+		}
+	}
+
+	var categoryID *int64
+	if post.Category != nil {
+		categoryID = &post.Category.ID
+	}
+
+	query := `
+	INSERT INTO
+		post (title, body, status, published_at, account_id, category_id, created_at, slug, search_language, published_body)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9::text::regconfig, CASE WHEN $3 = 'published' THEN $2 ELSE '' END)
+	RETURNING
+		id`
+
+	err := tx.QueryRow(ctx, query,
+		post.Title,
+		post.Body,
+		post.Status,
+		post.PublishedAt,
+		post.Account.ID,
+		categoryID,
+		post.CreatedAt,
+		post.Slug,
+		config.Cfg().SearchLanguage,
+	).Scan(
+		&post.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.postRepository.setTags(ctx, tx, post.ID, post.Tags)
+	if err != nil {
+		return 0, err
+	}
+
+	query = `
+	INSERT INTO
+		post_author (post_id, account_id, role, created_at)
+	VALUES
+		($1, $2, $3, $4)`
+
+	_, err = tx.Exec(ctx, query,
+		post.ID,
+		post.Account.ID,
+		constant.POST_AUTHOR_OWNER,
+		post.CreatedAt)
+	return postCreated, err
+}
+
+// matchPost finds the post with the slug of the imported post. Posts exported before they had a slug are
+// matched by the title among the posts of the account which have no slug yet.
+func (r *importRepository) matchPost(ctx context.Context, tx pgx.Tx, post *model.ImportPost) (*model.ImportPost, error) {
+	query := `
+	SELECT
+		post.id, post.title, post.body, post.status, post.published_at, post.created_at, COALESCE(post.slug, ''),
+		ARRAY(
+			SELECT
+				tag.name
+			FROM
+				post_tag
+			INNER JOIN
+				tag ON tag.id = post_tag.tag_id
+			WHERE
+				post_tag.post_id = post.id
+			ORDER BY
+				tag.name
+		)
+	FROM
+		post
+	WHERE
+		post.slug = $1 OR (post.slug IS NULL AND post.account_id = $2 AND post.title = $3)
+	ORDER BY
+		post.slug IS NULL, post.id
+	LIMIT
+		1`
+
+	current := &model.ImportPost{}
+	err := tx.QueryRow(ctx, query,
+		post.Slug,
+		post.Account.ID,
+		post.Title,
+	).Scan(
+		&current.ID,
+		&current.Title,
+		&current.Body,
+		&current.Status,
+		&current.PublishedAt,
+		&current.CreatedAt,
+		&current.Slug,
+		&current.Tags)
+	if err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// updatePost brings a matched post up to date with the imported one, posts which already are stay
+// untouched so their updated_at keeps telling when they really changed. A post which stays published keeps
+// when it was published, an imported draft leaves an unpublished post where it is in the review workflow, and
+// the authors of a post are left as they are.
+func (r *importRepository) updatePost(ctx context.Context, tx pgx.Tx, current, post *model.ImportPost) (int, error) {
+	if current.PublishedAt.Valid && post.PublishedAt.Valid {
+		post.PublishedAt = current.PublishedAt
+	}
+	if post.Status == constant.POST_STATUS_DRAFT && current.Status != constant.POST_STATUS_PUBLISHED {
+		post.Status = current.Status
+		post.PublishedAt = current.PublishedAt
+	}
+
+	tags := append([]string(nil), post.Tags...)
+	sort.Strings(tags)
+
+	if current.Slug == post.Slug &&
+		current.Title == post.Title &&
+		current.Body == post.Body &&
+		current.Status == post.Status &&
+		current.PublishedAt.Valid == post.PublishedAt.Valid &&
+		current.PublishedAt.Time.Equal(post.PublishedAt.Time) &&
+		current.CreatedAt.Equal(post.CreatedAt) &&
+		fmt.Sprint(current.Tags) == fmt.Sprint(tags) {
+		return postExisting, nil
+	}
+
+	query := `
+	UPDATE
+		post
+	SET
+		title = $1, body = $2, status = $3, published_at = $4, created_at = $5, slug = $6, updated_at = $7, search_language = $8::text::regconfig,
+		published_body = CASE WHEN $3 = 'published' THEN $2 ELSE published_body END, version = version + 1
+	WHERE
+		id = $9`
+
+	_, err := tx.Exec(ctx, query,
+		post.Title,
+		post.Body,
+		post.Status,
+		post.PublishedAt,
+		post.CreatedAt,
+		post.Slug,
+		time.Now(),
+		config.Cfg().SearchLanguage,
+		post.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.postRepository.setTags(ctx, tx, post.ID, post.Tags)
+	if err != nil {
+		return 0, err
+	}
+	return postUpdated, nil
+}
+
+// ListPosts lists every post the way it is imported, oldest first.
+func (r *importRepository) ListPosts(ctx context.Context) ([]*model.ImportPost, error) {
+	query := `
+	SELECT
+		post.id, post.title, post.body, post.status, post.published_at, post.created_at, COALESCE(post.slug, ''),
+		ARRAY(
+			SELECT
+				tag.name
+			FROM
+				post_tag
+			INNER JOIN
+				tag ON tag.id = post_tag.tag_id
+			WHERE
+				post_tag.post_id = post.id
+			ORDER BY
+				tag.name
+		),
+		account.id, account.name, account.email
+	FROM
+		post
+	INNER JOIN
+		account ON post.account_id = account.id
+	ORDER BY
+		post.created_at, post.id`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := make([]*model.ImportPost, 0)
+	for rows.Next() {
+		post := &model.ImportPost{Account: &model.ImportAccount{}}
+		err = rows.Scan(
+			&post.ID,
+			&post.Title,
+			&post.Body,
+			&post.Status,
+			&post.PublishedAt,
+			&post.CreatedAt,
+			&post.Slug,
+			&post.Tags,
+			&post.Account.ID,
+			&post.Account.Name,
+			&post.Account.Email)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+	return posts, nil
+}
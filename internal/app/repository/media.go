@@ -0,0 +1,390 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	cache "github.com/go-redis/cache/v8"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type MediaRepository interface {
+	Create(ctx context.Context, media *model.Media) error
+	List(ctx context.Context, accountID int64, limit, offset int, search string) ([]*model.Media, error)
+	ListUnreferenced(ctx context.Context, createdBefore time.Time, limit int) ([]*model.Media, error)
+	Get(ctx context.Context, id int64) (*model.Media, error)
+	ListByIDs(ctx context.Context, ids []int64) ([]*model.Media, error)
+	Update(ctx context.Context, media *model.Media) error
+	Delete(ctx context.Context, id int64) error
+	DeleteUnreferenced(ctx context.Context, id int64) (bool, error)
+}
+
+func NewMediaRepository(postgresClient postgres.Client, redisClient redis.Client) MediaRepository {
+	return &mediaRepository{postgresClient, redisClient}
+}
+
+type mediaRepository struct {
+	postgresClient postgres.Client
+	redisClient    redis.Client
+}
+
+const mediaColumns = `
+		media.id,
+		media.key,
+		media.filename,
+		media.content_type,
+		media.size,
+		media.width,
+		media.height,
+		media.alt_text,
+		media.caption,
+		media.created_at,
+		media.updated_at,
+		media.account_id,
+		(
+			SELECT
+				COALESCE(JSON_AGG(JSON_BUILD_OBJECT(
+					'Name', media_variant.name,
+					'Key', media_variant.key,
+					'ContentType', media_variant.content_type,
+					'Size', media_variant.size,
+					'Width', media_variant.width,
+					'Height', media_variant.height
+				) ORDER BY media_variant.width), '[]')
+			FROM
+				media_variant
+			WHERE
+				media_variant.media_id = media.id
+		),
+		(
+			SELECT
+				COALESCE(JSON_AGG(JSON_BUILD_OBJECT(
+					'PostID', media_usage.post_id,
+					'Title', media_usage.title,
+					'Kind', media_usage.kind
+				) ORDER BY media_usage.post_id, media_usage.kind), '[]')
+			FROM (
+				SELECT
+					post.id AS post_id, post.title, 'cover' AS kind
+				FROM
+					post
+				WHERE
+					post.cover_media_id = media.id
+				UNION ALL
+				SELECT
+					post.id, post.title, 'embed'
+				FROM
+					post_media
+				INNER JOIN
+					post ON post.id = post_media.post_id
+				WHERE
+					post_media.media_id = media.id
+			) AS media_usage
+		)`
+
+// mediaUnreferenced matches media that no post uses as a cover or embeds in its body.
+const mediaUnreferenced = `
+		NOT EXISTS (SELECT 1 FROM post WHERE post.cover_media_id = media.id)
+	AND
+		NOT EXISTS (SELECT 1 FROM post_media WHERE post_media.media_id = media.id)`
+
+// scanMedia scans the columns selected by mediaColumns into media.
+func scanMedia(row pgx.Row, media *model.Media) error {
+	return row.Scan(
+		&media.ID,
+		&media.Key,
+		&media.Filename,
+		&media.ContentType,
+		&media.Size,
+		&media.Width,
+		&media.Height,
+		&media.AltText,
+		&media.Caption,
+		&media.CreatedAt,
+		&media.UpdatedAt,
+		&media.AccountID,
+		&media.Variants,
+		&media.Usages)
+}
+
+func (r *mediaRepository) Create(ctx context.Context, media *model.Media) error {
+	tx, err := r.postgresClient.Conn().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+	INSERT INTO
+		media (key, filename, content_type, size, width, height, alt_text, caption, account_id, created_at)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING
+		id`
+
+	err = tx.QueryRow(ctx, query,
+		media.Key,
+		media.Filename,
+		media.ContentType,
+		media.Size,
+		media.Width,
+		media.Height,
+		media.AltText,
+		media.Caption,
+		media.AccountID,
+		media.CreatedAt,
+	).Scan(
+		&media.ID)
+	if err != nil {
+		return err
+	}
+
+	query = `
+	INSERT INTO
+		media_variant (media_id, name, key, content_type, size, width, height)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7)`
+
+	for _, variant := range media.Variants {
+		_, err = tx.Exec(ctx, query,
+			media.ID,
+			variant.Name,
+			variant.Key,
+			variant.ContentType,
+			variant.Size,
+			variant.Width,
+			variant.Height)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// List lists the media of an account, newest first, optionally matching the search against the
+// filename, alt text and caption.
+func (r *mediaRepository) List(ctx context.Context, accountID int64, limit, offset int, search string) ([]*model.Media, error) {
+	query := `
+	SELECT` + mediaColumns + `
+	FROM
+		media
+	WHERE
+		media.account_id = $1
+	AND
+		(media.filename ILIKE $2 OR media.alt_text ILIKE $2 OR media.caption ILIKE $2)
+	ORDER BY
+		media.created_at DESC, media.id DESC
+	LIMIT
+		$3 OFFSET $4`
+
+	return r.query(ctx, query,
+		accountID,
+		"%"+search+"%",
+		limit,
+		offset)
+}
+
+// ListUnreferenced lists media created before the given time that no post uses anymore.
+func (r *mediaRepository) ListUnreferenced(ctx context.Context, createdBefore time.Time, limit int) ([]*model.Media, error) {
+	query := `
+	SELECT` + mediaColumns + `
+	FROM
+		media
+	WHERE
+		media.created_at < $1
+	AND` + mediaUnreferenced + `
+	ORDER BY
+		media.created_at
+	LIMIT
+		$2`
+
+	return r.query(ctx, query, createdBefore, limit)
+}
+
+func (r *mediaRepository) query(ctx context.Context, query string, args ...interface{}) ([]*model.Media, error) {
+	rows, err := r.postgresClient.Conn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mediaList []*model.Media
+	for rows.Next() {
+		media := new(model.Media)
+		err := scanMedia(rows, media)
+		if err != nil {
+			return nil, err
+		}
+		mediaList = append(mediaList, media)
+	}
+
+	return mediaList, nil
+}
+
+func (r *mediaRepository) Get(ctx context.Context, id int64) (*model.Media, error) {
+	query := `
+	SELECT` + mediaColumns + `
+	FROM
+		media
+	WHERE
+		media.id = $1`
+
+	media := new(model.Media)
+	err := scanMedia(r.postgresClient.Conn().QueryRow(ctx, query, id), media)
+	if err != nil {
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// ListByIDs returns the media with the given ids in a single query, without their variants and usages.
+func (r *mediaRepository) ListByIDs(ctx context.Context, ids []int64) ([]*model.Media, error) {
+	query := `
+	SELECT
+		id, key, filename, content_type, size, width, height, alt_text, caption, created_at, updated_at, account_id
+	FROM
+		media
+	WHERE
+		id = ANY($1)`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mediaList []*model.Media
+	for rows.Next() {
+		media := new(model.Media)
+		err := rows.Scan(
+			&media.ID,
+			&media.Key,
+			&media.Filename,
+			&media.ContentType,
+			&media.Size,
+			&media.Width,
+			&media.Height,
+			&media.AltText,
+			&media.Caption,
+			&media.CreatedAt,
+			&media.UpdatedAt,
+			&media.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		mediaList = append(mediaList, media)
+	}
+
+	return mediaList, nil
+}
+
+func (r *mediaRepository) Update(ctx context.Context, media *model.Media) error {
+	query := `
+	UPDATE
+		media
+	SET
+		alt_text = $1, caption = $2, updated_at = $3
+	WHERE
+		id = $4`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query,
+		media.AltText,
+		media.Caption,
+		media.UpdatedAt.Time,
+		media.ID)
+	if err != nil {
+		return err
+	}
+
+	temp, err := r.Get(ctx, media.ID)
+	if err != nil {
+		return err
+	}
+	*media = *temp
+	return nil
+}
+
+// Delete removes the media and detaches it from every post using it as a cover.
+func (r *mediaRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := r.postgresClient.Conn().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+	UPDATE
+		post
+	SET
+		cover_media_id = NULL
+	WHERE
+		cover_media_id = $1
+	RETURNING
+		id`
+
+	rows, err := tx.Query(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	var postIDs []int64
+	for rows.Next() {
+		var postID int64
+		err := rows.Scan(&postID)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		postIDs = append(postIDs, postID)
+	}
+	rows.Close()
+
+	query = `
+	DELETE FROM
+		media
+	WHERE
+		id = $1`
+
+	_, err = tx.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, postID := range postIDs {
+		err = r.redisClient.Cache().Delete(ctx, fmt.Sprintf("post_%d", postID))
+		if err != nil && err != cache.ErrCacheMiss {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteUnreferenced removes the media only if it is still unused at the time of deletion, so a post
+// picking it up in the meantime keeps it alive.
+func (r *mediaRepository) DeleteUnreferenced(ctx context.Context, id int64) (bool, error) {
+	query := `
+	DELETE FROM
+		media
+	WHERE
+		media.id = $1
+	AND` + mediaUnreferenced
+
+	tag, err := r.postgresClient.Conn().Exec(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
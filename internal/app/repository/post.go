@@ -3,20 +3,27 @@ package repository
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	"github.com/anonychun/go-blog-api/internal/app/model"
 	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
 	"github.com/anonychun/go-blog-api/internal/db/postgres"
 	"github.com/anonychun/go-blog-api/internal/db/redis"
 	cache "github.com/go-redis/cache/v8"
+	pgx "github.com/jackc/pgx/v4"
 )
 
 type PostRepository interface {
 	Create(ctx context.Context, post *model.Post) error
-	List(ctx context.Context, limit, offset int, title string) ([]*model.Post, error)
+	List(ctx context.Context, limit, offset int, title string, tags []string, matchAllTags bool, status string, authorID int64) ([]*model.Post, error)
+	ListByCategory(ctx context.Context, categoryID int64, limit, offset int) ([]*model.Post, error)
+	ListPublished(ctx context.Context, authorID int64, limit int) ([]*model.Post, error)
+	Search(ctx context.Context, query string, limit, offset int) ([]*model.PostSearchResult, error)
 	Get(ctx context.Context, id int64) (*model.Post, error)
+	GetPublishedBody(ctx context.Context, id int64) (string, error)
 	Update(ctx context.Context, post *model.Post) error
-	Delete(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64, version int64) error
 }
 
 func NewPostRepository(postgresClient postgres.Client, redisClient redis.Client) PostRepository {
@@ -28,59 +35,323 @@ type postRepository struct {
 	redisClient    redis.Client
 }
 
+const postColumns = `
+		post.id,
+		post.title,
+		post.body,
+		post.created_at,
+		post.updated_at,
+		post.status,
+		post.published_at,
+		post.version,
+		post.account_id,
+		account.id,
+		account.name,
+		account.email,
+		account.password,
+		account.role,
+		account.created_at,
+		account.updated_at,
+		ARRAY(
+			SELECT
+				tag.name
+			FROM
+				tag
+			INNER JOIN
+				post_tag ON tag.id = post_tag.tag_id
+			WHERE
+				post_tag.post_id = post.id
+			ORDER BY
+				tag.name
+		),
+		post.category_id,
+		post.cover_media_id,
+		(
+			WITH RECURSIVE ancestor AS (
+				SELECT
+					category.id, category.name, category.parent_id, 0 AS depth
+				FROM
+					category
+				WHERE
+					category.id = post.category_id
+				UNION ALL
+				SELECT
+					category.id, category.name, category.parent_id, ancestor.depth + 1
+				FROM
+					category
+				INNER JOIN
+					ancestor ON category.id = ancestor.parent_id
+			)
+			SELECT
+				COALESCE(JSON_AGG(JSON_BUILD_OBJECT('id', id, 'name', name) ORDER BY depth DESC), '[]')
+			FROM
+				ancestor
+		),
+		(
+			SELECT
+				COUNT(*)
+			FROM
+				comment
+			WHERE
+				comment.post_id = post.id
+		),
+		(
+			SELECT
+				COALESCE(JSON_AGG(JSON_BUILD_OBJECT(
+					'AccountID', author.id,
+					'Name', author.name,
+					'Role', post_author.role,
+					'CreatedAt', post_author.created_at AT TIME ZONE 'UTC'
+				) ORDER BY
+					CASE post_author.role WHEN 'owner' THEN 0 WHEN 'co-author' THEN 1 ELSE 2 END,
+					post_author.created_at
+				), '[]')
+			FROM
+				post_author
+			INNER JOIN
+				account AS author ON author.id = post_author.account_id
+			WHERE
+				post_author.post_id = post.id
+		)`
+
+// scanPost scans the columns selected by postColumns into post, followed by any extra destinations.
+func scanPost(row pgx.Row, post *model.Post, dest ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&post.ID,
+		&post.Title,
+		&post.Body,
+		&post.CreatedAt,
+		&post.UpdatedAt,
+		&post.Status,
+		&post.PublishedAt,
+		&post.Version,
+		&post.AccountID,
+		&post.Account.ID,
+		&post.Account.Name,
+		&post.Account.Email,
+		&post.Account.Password,
+		&post.Account.Role,
+		&post.Account.CreatedAt,
+		&post.Account.UpdatedAt,
+		&post.Tags,
+		&post.CategoryID,
+		&post.CoverMediaID,
+		&post.Breadcrumbs,
+		&post.CommentCount,
+		&post.Authors,
+	}, dest...)...)
+}
+
 func (r *postRepository) Create(ctx context.Context, post *model.Post) error {
+	tx, err := r.postgresClient.Conn().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 	INSERT INTO
-		post (title, body, account_id, created_at)
+		post (title, body, status, account_id, category_id, cover_media_id, created_at, search_language)
 	VALUES
-		($1, $2, $3, $4)
+		($1, $2, $3, $4, $5, $6, $7, $8::text::regconfig)
 	RETURNING
 		id`
 
-	err := r.postgresClient.Conn().QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		post.Title,
 		post.Body,
+		post.Status,
 		post.AccountID,
+		post.CategoryID,
+		post.CoverMediaID,
 		post.CreatedAt,
+		config.Cfg().SearchLanguage,
 	).Scan(
 		&post.ID)
 	if err != nil {
 		return err
 	}
 
+	err = r.setTags(ctx, tx, post.ID, post.Tags)
+	if err != nil {
+		return err
+	}
+
+	err = r.setMedia(ctx, tx, post.ID, post.Body)
+	if err != nil {
+		return err
+	}
+
+	query = `
+	INSERT INTO
+		post_author (post_id, account_id, role, created_at)
+	VALUES
+		($1, $2, $3, $4)`
+
+	_, err = tx.Exec(ctx, query,
+		post.ID,
+		post.AccountID,
+		constant.POST_AUTHOR_OWNER,
+		post.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
 	temp, err := r.Get(ctx, post.ID)
 	*post = *temp
 	return nil
 }
 
-func (r *postRepository) List(ctx context.Context, limit, offset int, title string) ([]*model.Post, error) {
+// List lists posts in the given status, restricted to posts the author works on unless authorID is zero.
+// Published posts are listed by publish date like ListPublished, others by creation date.
+func (r *postRepository) List(ctx context.Context, limit, offset int, title string, tags []string, matchAllTags bool, status string, authorID int64) ([]*model.Post, error) {
 	query := `
-	SELECT
-		post.id,
-		post.title,
-		post.body,
-		post.created_at,
-		post.updated_at,
-		post.account_id,
-		account.id,
-		account.name,
-		account.email,
-		account.password,
-		account.created_at,
-		account.updated_at
+	SELECT` + postColumns + `
 	FROM
 		post
 	INNER JOIN
 		account	ON post.account_id = account.id
 	WHERE
 		post.title LIKE $1
+	AND
+		(COALESCE(CARDINALITY($4::text[]), 0) = 0 OR post.id IN (
+			SELECT
+				post_tag.post_id
+			FROM
+				post_tag
+			INNER JOIN
+				tag ON tag.id = post_tag.tag_id
+			WHERE
+				tag.name = ANY($4::text[])
+			GROUP BY
+				post_tag.post_id
+			HAVING
+				NOT $5::boolean OR COUNT(*) = CARDINALITY($4::text[])
+		))
+	AND
+		post.status = $6
+	AND
+		($7::bigint = 0 OR EXISTS (
+			SELECT
+				1
+			FROM
+				post_author
+			WHERE
+				post_author.post_id = post.id AND post_author.account_id = $7
+		))
+	ORDER BY
+		CASE WHEN post.status = 'published' THEN post.published_at END DESC NULLS LAST, post.created_at DESC, post.id DESC
 	LIMIT
 		$2 OFFSET $3`
 
 	rows, err := r.postgresClient.Conn().Query(ctx, query,
 		"%"+title+"%",
 		limit,
-		offset)
+		offset,
+		tags,
+		matchAllTags,
+		status,
+		authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []*model.Post
+	for rows.Next() {
+		post := new(model.Post)
+		err := scanPost(rows, post)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
+
+// ListByCategory lists posts in the given category and all of its descendants.
+func (r *postRepository) ListByCategory(ctx context.Context, categoryID int64, limit, offset int) ([]*model.Post, error) {
+	query := `
+	WITH RECURSIVE descendant AS (
+		SELECT
+			id
+		FROM
+			category
+		WHERE
+			id = $1
+		UNION ALL
+		SELECT
+			category.id
+		FROM
+			category
+		INNER JOIN
+			descendant ON category.parent_id = descendant.id
+	)
+	SELECT` + postColumns + `
+	FROM
+		post
+	INNER JOIN
+		account	ON post.account_id = account.id
+	WHERE
+		post.category_id IN (SELECT id FROM descendant)
+	AND
+		post.status = 'published'
+	ORDER BY
+		post.created_at DESC
+	LIMIT
+		$2 OFFSET $3`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, categoryID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []*model.Post
+	for rows.Next() {
+		post := new(model.Post)
+		err := scanPost(rows, post)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
+
+// ListPublished lists the most recently published posts, restricted to posts written by the author unless
+// authorID is zero.
+func (r *postRepository) ListPublished(ctx context.Context, authorID int64, limit int) ([]*model.Post, error) {
+	query := `
+	SELECT` + postColumns + `
+	FROM
+		post
+	INNER JOIN
+		account	ON post.account_id = account.id
+	WHERE
+		post.status = 'published'
+	AND
+		($1::bigint = 0 OR EXISTS (
+			SELECT
+				1
+			FROM
+				post_author
+			WHERE
+				post_author.post_id = post.id AND post_author.account_id = $1 AND post_author.role <> 'reviewer'
+		))
+	ORDER BY
+		post.published_at DESC
+	LIMIT
+		$2`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, authorID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -89,19 +360,7 @@ func (r *postRepository) List(ctx context.Context, limit, offset int, title stri
 	var posts []*model.Post
 	for rows.Next() {
 		post := new(model.Post)
-		err := rows.Scan(
-			&post.ID,
-			&post.Title,
-			&post.Body,
-			&post.CreatedAt,
-			&post.UpdatedAt,
-			&post.AccountID,
-			&post.Account.ID,
-			&post.Account.Name,
-			&post.Account.Email,
-			&post.Account.Password,
-			&post.Account.CreatedAt,
-			&post.Account.UpdatedAt)
+		err := scanPost(rows, post)
 		if err != nil {
 			return nil, err
 		}
@@ -111,6 +370,54 @@ func (r *postRepository) List(ctx context.Context, limit, offset int, title stri
 	return posts, nil
 }
 
+// Search matches posts against a web search style query, ranking title matches above body matches.
+func (r *postRepository) Search(ctx context.Context, search string, limit, offset int) ([]*model.PostSearchResult, error) {
+	query := `
+	SELECT` + postColumns + `,
+		TS_RANK(post.search_vector, search_query),
+		TS_HEADLINE(post.search_language, post.title, search_query, 'HighlightAll=true'),
+		TS_HEADLINE(post.search_language, post.body, search_query, 'MaxFragments=2, MaxWords=35, MinWords=15')
+	FROM
+		post
+	INNER JOIN
+		account	ON post.account_id = account.id
+	CROSS JOIN
+		WEBSEARCH_TO_TSQUERY($1::text::regconfig, $2) AS search_query
+	WHERE
+		post.search_vector @@ search_query
+	AND
+		post.status = 'published'
+	ORDER BY
+		TS_RANK(post.search_vector, search_query) DESC, post.created_at DESC
+	LIMIT
+		$3 OFFSET $4`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query,
+		config.Cfg().SearchLanguage,
+		search,
+		limit,
+		offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*model.PostSearchResult
+	for rows.Next() {
+		result := new(model.PostSearchResult)
+		err := scanPost(rows, &result.Post,
+			&result.Rank,
+			&result.TitleHighlight,
+			&result.BodySnippet)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
 func (r *postRepository) Get(ctx context.Context, id int64) (*model.Post, error) {
 	post := new(model.Post)
 	err := r.redisClient.Cache().Get(ctx, fmt.Sprintf("post_%d", id), post)
@@ -121,19 +428,7 @@ func (r *postRepository) Get(ctx context.Context, id int64) (*model.Post, error)
 	}
 
 	query := `
-	SELECT
-		post.id,
-		post.title,
-		post.body,
-		post.created_at,
-		post.updated_at,
-		post.account_id,
-		account.id,
-		account.name,
-		account.email,
-		account.password,
-		account.created_at,
-		account.updated_at
+	SELECT` + postColumns + `
 	FROM
 		post
 	INNER JOIN
@@ -143,19 +438,7 @@ func (r *postRepository) Get(ctx context.Context, id int64) (*model.Post, error)
 	WHERE
 		post.id = $1`
 
-	err = r.postgresClient.Conn().QueryRow(ctx, query, id).Scan(
-		&post.ID,
-		&post.Title,
-		&post.Body,
-		&post.CreatedAt,
-		&post.UpdatedAt,
-		&post.AccountID,
-		&post.Account.ID,
-		&post.Account.Name,
-		&post.Account.Email,
-		&post.Account.Password,
-		&post.Account.CreatedAt,
-		&post.Account.UpdatedAt)
+	err = scanPost(r.postgresClient.Conn().QueryRow(ctx, query, id), post)
 	if err != nil {
 		return nil, err
 	}
@@ -168,20 +451,67 @@ func (r *postRepository) Get(ctx context.Context, id int64) (*model.Post, error)
 	})
 }
 
+// GetPublishedBody returns the body the post had when it was last published, it is empty for posts which
+// never were.
+func (r *postRepository) GetPublishedBody(ctx context.Context, id int64) (string, error) {
+	query := `
+	SELECT
+		published_body
+	FROM
+		post
+	WHERE
+		id = $1`
+
+	var body string
+	err := r.postgresClient.Conn().QueryRow(ctx, query, id).Scan(&body)
+	return body, err
+}
+
+// Update saves the post when it is still at the version it was read at, pgx.ErrNoRows is returned when
+// it was changed in the meantime.
 func (r *postRepository) Update(ctx context.Context, post *model.Post) error {
+	tx, err := r.postgresClient.Conn().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 	UPDATE
 		post
 	SET
-		title = $1, body = $2, updated_at = $3
+		title = $1, body = $2, status = $3, category_id = $4, cover_media_id = $5, updated_at = $6, search_language = $7::text::regconfig,
+		version = version + 1
 	WHERE
-		id = $4`
+		id = $8 AND version = $9`
 
-	_, err := r.postgresClient.Conn().Exec(ctx, query,
+	tag, err := tx.Exec(ctx, query,
 		post.Title,
 		post.Body,
+		post.Status,
+		post.CategoryID,
+		post.CoverMediaID,
 		post.UpdatedAt.Time,
-		post.ID)
+		config.Cfg().SearchLanguage,
+		post.ID,
+		post.Version)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	err = r.setTags(ctx, tx, post.ID, post.Tags)
+	if err != nil {
+		return err
+	}
+
+	err = r.setMedia(ctx, tx, post.ID, post.Body)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
 		return err
 	}
@@ -191,21 +521,29 @@ func (r *postRepository) Update(ctx context.Context, post *model.Post) error {
 		return err
 	}
 
+	err = invalidateSitemaps(ctx, r.redisClient)
+	if err != nil {
+		return err
+	}
+
 	temp, err := r.Get(ctx, post.ID)
 	*post = *temp
 	return err
 }
 
-func (r *postRepository) Delete(ctx context.Context, id int64) error {
+// Delete removes the post if it is still at the given version, pgx.ErrNoRows is returned otherwise.
+func (r *postRepository) Delete(ctx context.Context, id int64, version int64) error {
 	query := `
 	DELETE FROM
 		post
 	WHERE
-		id = $1`
+		id = $1 AND version = $2`
 
-	_, err := r.postgresClient.Conn().Exec(ctx, query, id)
+	tag, err := r.postgresClient.Conn().Exec(ctx, query, id, version)
 	if err != nil {
 		return err
+	} else if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
 	}
 
 	err = r.redisClient.Cache().Delete(ctx, fmt.Sprintf("post_%d", id))
@@ -213,5 +551,95 @@ func (r *postRepository) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 
+	err = invalidateSitemaps(ctx, r.redisClient)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// setTags replaces the tags attached to a post, creating tags that do not exist yet.
+func (r *postRepository) setTags(ctx context.Context, tx pgx.Tx, postID int64, tags []string) error {
+	query := `
+	DELETE FROM
+		post_tag
+	WHERE
+		post_id = $1`
+
+	_, err := tx.Exec(ctx, query, postID)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	query = `
+	INSERT INTO
+		tag (name)
+	SELECT
+		UNNEST($1::text[])
+	ON CONFLICT (name) DO NOTHING`
+
+	_, err = tx.Exec(ctx, query, tags)
+	if err != nil {
+		return err
+	}
+
+	query = `
+	INSERT INTO
+		post_tag (post_id, tag_id)
+	SELECT
+		$1, id
+	FROM
+		tag
+	WHERE
+		name = ANY($2::text[])`
+
+	_, err = tx.Exec(ctx, query, postID, tags)
+	return err
+}
+
+// mediaKeyPattern matches the keys of media files and their variants, see newMediaKey in the service.
+var mediaKeyPattern = regexp.MustCompile(`\d+/[0-9a-f]{32}(_[a-z0-9]+)?\.[a-z0-9]+`)
+
+// setMedia records which media files are embedded in the body of a post, either directly or through one of their variants.
+func (r *postRepository) setMedia(ctx context.Context, tx pgx.Tx, postID int64, body string) error {
+	query := `
+	DELETE FROM
+		post_media
+	WHERE
+		post_id = $1`
+
+	_, err := tx.Exec(ctx, query, postID)
+	if err != nil {
+		return err
+	}
+
+	keys := mediaKeyPattern.FindAllString(body, -1)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	query = `
+	INSERT INTO
+		post_media (post_id, media_id)
+	SELECT
+		$1, id
+	FROM
+		media
+	WHERE
+		key = ANY($2)
+	UNION
+	SELECT
+		$1, media_id
+	FROM
+		media_variant
+	WHERE
+		key = ANY($2)`
+
+	_, err = tx.Exec(ctx, query, postID, keys)
+	return err
+}
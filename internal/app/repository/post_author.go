@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	cache "github.com/go-redis/cache/v8"
+)
+
+type PostAuthorRepository interface {
+	Set(ctx context.Context, postID, accountID int64, role string, createdAt time.Time) error
+	Delete(ctx context.Context, postID, accountID int64) error
+}
+
+func NewPostAuthorRepository(postgresClient postgres.Client, redisClient redis.Client) PostAuthorRepository {
+	return &postAuthorRepository{postgresClient, redisClient}
+}
+
+type postAuthorRepository struct {
+	postgresClient postgres.Client
+	redisClient    redis.Client
+}
+
+// Set adds an author to a post, an existing author only has their role changed.
+func (r *postAuthorRepository) Set(ctx context.Context, postID, accountID int64, role string, createdAt time.Time) error {
+	query := `
+	INSERT INTO
+		post_author (post_id, account_id, role, created_at)
+	VALUES
+		($1, $2, $3, $4)
+	ON CONFLICT (post_id, account_id) DO UPDATE SET
+		role = EXCLUDED.role`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query, postID, accountID, role, createdAt)
+	if err != nil {
+		return err
+	}
+
+	return r.invalidate(ctx, postID)
+}
+
+func (r *postAuthorRepository) Delete(ctx context.Context, postID, accountID int64) error {
+	query := `
+	DELETE FROM
+		post_author
+	WHERE
+		post_id = $1 AND account_id = $2`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query, postID, accountID)
+	if err != nil {
+		return err
+	}
+
+	return r.invalidate(ctx, postID)
+}
+
+// invalidate drops the cached post since it embeds the author list.
+func (r *postAuthorRepository) invalidate(ctx context.Context, postID int64) error {
+	err := r.redisClient.Cache().Delete(ctx, fmt.Sprintf("post_%d", postID))
+	if err != nil && err != cache.ErrCacheMiss {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	cache "github.com/go-redis/cache/v8"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type PostReviewRepository interface {
+	Create(ctx context.Context, review *model.PostReview, from []string, to string) error
+	List(ctx context.Context, postID int64) ([]*model.PostReview, error)
+}
+
+func NewPostReviewRepository(postgresClient postgres.Client, redisClient redis.Client) PostReviewRepository {
+	return &postReviewRepository{postgresClient, redisClient}
+}
+
+type postReviewRepository struct {
+	postgresClient postgres.Client
+	redisClient    redis.Client
+}
+
+// Create records a review action and moves the post from one of the from statuses to the to status in
+// the same transaction, the publish date is set the first time a post is published and the published body
+// is kept on every publish. pgx.ErrNoRows is returned when the post is no longer in one of the from statuses.
+func (r *postReviewRepository) Create(ctx context.Context, review *model.PostReview, from []string, to string) error {
+	tx, err := r.postgresClient.Conn().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+	INSERT INTO
+		post_review (action, comment, post_id, account_id, created_at)
+	VALUES
+		($1, $2, $3, $4, $5)
+	RETURNING
+		id`
+
+	err = tx.QueryRow(ctx, query,
+		review.Action,
+		review.Comment,
+		review.PostID,
+		review.AccountID,
+		review.CreatedAt,
+	).Scan(
+		&review.ID)
+	if err != nil {
+		return err
+	}
+
+	query = `
+	UPDATE
+		post
+	SET
+		status = $1,
+		version = version + 1,
+		published_at = CASE WHEN $1 = $2 THEN COALESCE(published_at, $3) ELSE published_at END,
+		published_body = CASE WHEN $1 = $2 THEN body ELSE published_body END
+	WHERE
+		id = $4 AND status = ANY($5)`
+
+	tag, err := tx.Exec(ctx, query,
+		to,
+		constant.POST_STATUS_PUBLISHED,
+		review.CreatedAt,
+		review.PostID,
+		from)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = r.redisClient.Cache().Delete(ctx, fmt.Sprintf("post_%d", review.PostID))
+	if err != nil && err != cache.ErrCacheMiss {
+		return err
+	}
+
+	err = invalidateSitemaps(ctx, r.redisClient)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *postReviewRepository) List(ctx context.Context, postID int64) ([]*model.PostReview, error) {
+	query := `
+	SELECT
+		post_review.id,
+		post_review.action,
+		post_review.comment,
+		post_review.created_at,
+		post_review.post_id,
+		post_review.account_id,
+		account.id,
+		account.name,
+		account.email,
+		account.password,
+		account.role,
+		account.created_at,
+		account.updated_at
+	FROM
+		post_review
+	INNER JOIN
+		account ON post_review.account_id = account.id
+	WHERE
+		post_review.post_id = $1
+	ORDER BY
+		post_review.created_at, post_review.id`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []*model.PostReview
+	for rows.Next() {
+		review := new(model.PostReview)
+		err := rows.Scan(
+			&review.ID,
+			&review.Action,
+			&review.Comment,
+			&review.CreatedAt,
+			&review.PostID,
+			&review.AccountID,
+			&review.Account.ID,
+			&review.Account.Name,
+			&review.Account.Email,
+			&review.Account.Password,
+			&review.Account.Role,
+			&review.Account.CreatedAt,
+			&review.Account.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, nil
+}
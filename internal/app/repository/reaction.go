@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	redisclient "github.com/go-redis/redis/v8"
+)
+
+type ReactionRepository interface {
+	Add(ctx context.Context, postID, accountID int64, reactionType string) error
+	Remove(ctx context.Context, postID, accountID int64, reactionType string) error
+	Count(ctx context.Context, postID int64) (map[string]int64, error)
+	ListTypesByAccount(ctx context.Context, postID, accountID int64) ([]string, error)
+	Reconcile(ctx context.Context) error
+}
+
+func NewReactionRepository(postgresClient postgres.Client, redisClient redis.Client) ReactionRepository {
+	return &reactionRepository{postgresClient, redisClient}
+}
+
+type reactionRepository struct {
+	postgresClient postgres.Client
+	redisClient    redis.Client
+}
+
+// incrementIfExists only touches counters that were already loaded from postgres,
+// a partially filled counter would otherwise be mistaken for the full aggregate.
+var incrementIfExists = redisclient.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("HINCRBY", KEYS[1], ARGV[1], ARGV[2])
+end
+return nil`)
+
+const reactionKeyPrefix = "post_reactions_"
+
+func reactionKey(postID int64) string {
+	return fmt.Sprintf("%s%d", reactionKeyPrefix, postID)
+}
+
+func (r *reactionRepository) Add(ctx context.Context, postID, accountID int64, reactionType string) error {
+	query := `
+	INSERT INTO
+		reaction (post_id, account_id, type)
+	VALUES
+		($1, $2, $3)
+	ON CONFLICT DO NOTHING`
+
+	tag, err := r.postgresClient.Conn().Exec(ctx, query, postID, accountID, reactionType)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	return r.increment(ctx, postID, reactionType, 1)
+}
+
+func (r *reactionRepository) Remove(ctx context.Context, postID, accountID int64, reactionType string) error {
+	query := `
+	DELETE FROM
+		reaction
+	WHERE
+		post_id = $1 AND account_id = $2 AND type = $3`
+
+	tag, err := r.postgresClient.Conn().Exec(ctx, query, postID, accountID, reactionType)
+	if err != nil {
+		return err
+	} else if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	return r.increment(ctx, postID, reactionType, -1)
+}
+
+// Count returns the number of reactions per type, served from the redis counters and
+// loaded from postgres when they are missing.
+func (r *reactionRepository) Count(ctx context.Context, postID int64) (map[string]int64, error) {
+	values, err := r.redisClient.Conn().HGetAll(ctx, reactionKey(postID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) > 0 {
+		counts := make(map[string]int64, len(values))
+		for reactionType, value := range values {
+			counts[reactionType], err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return counts, nil
+	}
+
+	counts, err := r.countFromPostgres(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, r.store(ctx, postID, counts)
+}
+
+func (r *reactionRepository) ListTypesByAccount(ctx context.Context, postID, accountID int64) ([]string, error) {
+	query := `
+	SELECT
+		type
+	FROM
+		reaction
+	WHERE
+		post_id = $1 AND account_id = $2`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, postID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reactionTypes []string
+	for rows.Next() {
+		var reactionType string
+		err := rows.Scan(&reactionType)
+		if err != nil {
+			return nil, err
+		}
+		reactionTypes = append(reactionTypes, reactionType)
+	}
+
+	return reactionTypes, nil
+}
+
+// Reconcile overwrites every cached counter with the aggregate stored in postgres.
+func (r *reactionRepository) Reconcile(ctx context.Context) error {
+	iter := r.redisClient.Conn().Scan(ctx, 0, reactionKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		postID, err := strconv.ParseInt(strings.TrimPrefix(iter.Val(), reactionKeyPrefix), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		counts, err := r.countFromPostgres(ctx, postID)
+		if err != nil {
+			return err
+		}
+
+		err = r.store(ctx, postID, counts)
+		if err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
+
+func (r *reactionRepository) increment(ctx context.Context, postID int64, reactionType string, n int64) error {
+	err := incrementIfExists.Run(ctx, r.redisClient.Conn(), []string{reactionKey(postID)}, reactionType, n).Err()
+	if err != nil && err != redisclient.Nil {
+		return err
+	}
+	return nil
+}
+
+func (r *reactionRepository) countFromPostgres(ctx context.Context, postID int64) (map[string]int64, error) {
+	query := `
+	SELECT
+		type, COUNT(*)
+	FROM
+		reaction
+	WHERE
+		post_id = $1
+	GROUP BY
+		type`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64, len(constant.REACTION_TYPES))
+	for _, reactionType := range constant.REACTION_TYPES {
+		counts[reactionType] = 0
+	}
+
+	for rows.Next() {
+		var reactionType string
+		var count int64
+		err := rows.Scan(&reactionType, &count)
+		if err != nil {
+			return nil, err
+		}
+		counts[reactionType] = count
+	}
+
+	return counts, nil
+}
+
+func (r *reactionRepository) store(ctx context.Context, postID int64, counts map[string]int64) error {
+	values := make(map[string]interface{}, len(counts))
+	for reactionType, count := range counts {
+		values[reactionType] = count
+	}
+
+	pipe := r.redisClient.Conn().TxPipeline()
+	pipe.HSet(ctx, reactionKey(postID), values)
+	pipe.Expire(ctx, reactionKey(postID), config.Cfg().RedisTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
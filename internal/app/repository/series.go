@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+)
+
+type SeriesRepository interface {
+	Create(ctx context.Context, series *model.Series) error
+	List(ctx context.Context, limit, offset int, title string) ([]*model.Series, error)
+	Get(ctx context.Context, id int64) (*model.Series, error)
+	GetByPostID(ctx context.Context, postID int64) (*model.Series, error)
+	Update(ctx context.Context, series *model.Series) error
+	UpdatePosts(ctx context.Context, id int64, postIDs []int64) error
+	Delete(ctx context.Context, id int64) error
+}
+
+func NewSeriesRepository(postgresClient postgres.Client) SeriesRepository {
+	return &seriesRepository{postgresClient}
+}
+
+type seriesRepository struct {
+	postgresClient postgres.Client
+}
+
+func (r *seriesRepository) Create(ctx context.Context, series *model.Series) error {
+	query := `
+	INSERT INTO
+		series (title, description, account_id, created_at)
+	VALUES
+		($1, $2, $3, $4)
+	RETURNING
+		id`
+
+	err := r.postgresClient.Conn().QueryRow(ctx, query,
+		series.Title,
+		series.Description,
+		series.AccountID,
+		series.CreatedAt,
+	).Scan(
+		&series.ID)
+	if err != nil {
+		return err
+	}
+
+	temp, err := r.Get(ctx, series.ID)
+	if err != nil {
+		return err
+	}
+	*series = *temp
+	return nil
+}
+
+func (r *seriesRepository) List(ctx context.Context, limit, offset int, title string) ([]*model.Series, error) {
+	query := `
+	SELECT
+		id, title, description, created_at, updated_at, account_id
+	FROM
+		series
+	WHERE
+		title LIKE $1
+	LIMIT
+		$2 OFFSET $3`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query,
+		"%"+title+"%",
+		limit,
+		offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seriesList []*model.Series
+	for rows.Next() {
+		series := new(model.Series)
+		err := rows.Scan(
+			&series.ID,
+			&series.Title,
+			&series.Description,
+			&series.CreatedAt,
+			&series.UpdatedAt,
+			&series.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		seriesList = append(seriesList, series)
+	}
+	rows.Close()
+
+	for _, series := range seriesList {
+		series.Posts, err = r.listPosts(ctx, series.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return seriesList, nil
+}
+
+func (r *seriesRepository) Get(ctx context.Context, id int64) (*model.Series, error) {
+	query := `
+	SELECT
+		id, title, description, created_at, updated_at, account_id
+	FROM
+		series
+	WHERE
+		id = $1`
+
+	series := new(model.Series)
+	err := r.postgresClient.Conn().QueryRow(ctx, query, id).Scan(
+		&series.ID,
+		&series.Title,
+		&series.Description,
+		&series.CreatedAt,
+		&series.UpdatedAt,
+		&series.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	series.Posts, err = r.listPosts(ctx, series.ID)
+	return series, err
+}
+
+func (r *seriesRepository) GetByPostID(ctx context.Context, postID int64) (*model.Series, error) {
+	query := `
+	SELECT
+		series_id
+	FROM
+		series_post
+	WHERE
+		post_id = $1`
+
+	var id int64
+	err := r.postgresClient.Conn().QueryRow(ctx, query, postID).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Get(ctx, id)
+}
+
+func (r *seriesRepository) Update(ctx context.Context, series *model.Series) error {
+	query := `
+	UPDATE
+		series
+	SET
+		title = $1, description = $2, updated_at = $3
+	WHERE
+		id = $4`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query,
+		series.Title,
+		series.Description,
+		series.UpdatedAt.Time,
+		series.ID)
+	if err != nil {
+		return err
+	}
+
+	temp, err := r.Get(ctx, series.ID)
+	if err != nil {
+		return err
+	}
+	*series = *temp
+	return nil
+}
+
+// UpdatePosts replaces the posts of a series, numbering them in the given order starting from 1.
+func (r *seriesRepository) UpdatePosts(ctx context.Context, id int64, postIDs []int64) error {
+	tx, err := r.postgresClient.Conn().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+	DELETE FROM
+		series_post
+	WHERE
+		series_id = $1`
+
+	_, err = tx.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	query = `
+	INSERT INTO
+		series_post (series_id, post_id, position)
+	SELECT
+		$1, post_id, position
+	FROM
+		UNNEST($2::int[]) WITH ORDINALITY AS t(post_id, position)`
+
+	_, err = tx.Exec(ctx, query, id, postIDs)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *seriesRepository) Delete(ctx context.Context, id int64) error {
+	query := `
+	DELETE FROM
+		series
+	WHERE
+		id = $1`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query, id)
+	return err
+}
+
+// listPosts returns the posts of a series in reading order, renumbering positions so that
+// gaps left by deleted posts do not show up as missing parts.
+func (r *seriesRepository) listPosts(ctx context.Context, id int64) ([]model.SeriesPost, error) {
+	query := `
+	SELECT
+		post.id, post.title, post.status, ROW_NUMBER() OVER (ORDER BY series_post.position)
+	FROM
+		series_post
+	INNER JOIN
+		post ON post.id = series_post.post_id
+	WHERE
+		series_post.series_id = $1
+	ORDER BY
+		series_post.position`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []model.SeriesPost
+	for rows.Next() {
+		var post model.SeriesPost
+		err := rows.Scan(&post.PostID, &post.Title, &post.Status, &post.Position)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
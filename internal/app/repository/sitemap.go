@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	cache "github.com/go-redis/cache/v8"
+	redisclient "github.com/go-redis/redis/v8"
+)
+
+type SitemapRepository interface {
+	ListPages(ctx context.Context, size int) ([]time.Time, error)
+	List(ctx context.Context, limit, offset int) ([]*model.SitemapEntry, error)
+	DocumentKey(ctx context.Context, name string) (string, error)
+	GetDocument(ctx context.Context, key string) (*model.SitemapDocument, error)
+	SetDocument(ctx context.Context, key string, doc *model.SitemapDocument) error
+}
+
+func NewSitemapRepository(postgresClient postgres.Client, redisClient redis.Client) SitemapRepository {
+	return &sitemapRepository{postgresClient, redisClient}
+}
+
+type sitemapRepository struct {
+	postgresClient postgres.Client
+	redisClient    redis.Client
+}
+
+// sitemapGenerationKey holds a counter that is part of every cached sitemap key, bumping it drops all
+// the cached sitemaps at once.
+const sitemapGenerationKey = "sitemap_generation"
+
+// ListPages splits the published posts into pages of the given size and returns when each page was last
+// modified, in page order.
+func (r *sitemapRepository) ListPages(ctx context.Context, size int) ([]time.Time, error) {
+	query := `
+	SELECT
+		MAX(last_modified)
+	FROM (
+		SELECT
+			COALESCE(post.updated_at, post.created_at) AS last_modified,
+			(ROW_NUMBER() OVER (ORDER BY post.id) - 1) / $1 AS page
+		FROM
+			post
+		WHERE
+			post.status = 'published'
+	) AS sitemap
+	GROUP BY
+		page
+	ORDER BY
+		page`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, size)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pages []time.Time
+	for rows.Next() {
+		var lastModified time.Time
+		err := rows.Scan(&lastModified)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, lastModified)
+	}
+
+	return pages, nil
+}
+
+func (r *sitemapRepository) List(ctx context.Context, limit, offset int) ([]*model.SitemapEntry, error) {
+	query := `
+	SELECT
+		post.id, COALESCE(post.updated_at, post.created_at)
+	FROM
+		post
+	WHERE
+		post.status = 'published'
+	ORDER BY
+		post.id
+	LIMIT
+		$1 OFFSET $2`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*model.SitemapEntry
+	for rows.Next() {
+		entry := new(model.SitemapEntry)
+		err := rows.Scan(&entry.PostID, &entry.LastModified)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// DocumentKey returns the cache key of the sitemap with the given name in the current generation. A sitemap
+// is looked up and stored under the same key, so one rendered before the posts changed is never stored
+// under the generation that followed.
+func (r *sitemapRepository) DocumentKey(ctx context.Context, name string) (string, error) {
+	generation, err := r.redisClient.Conn().Get(ctx, sitemapGenerationKey).Int64()
+	if err != nil && err != redisclient.Nil {
+		return "", err
+	}
+	return fmt.Sprintf("sitemap_%d_%s", generation, name), nil
+}
+
+// GetDocument returns the cached sitemap with the given key, nil when it has not been rendered since the
+// posts last changed.
+func (r *sitemapRepository) GetDocument(ctx context.Context, key string) (*model.SitemapDocument, error) {
+	doc := new(model.SitemapDocument)
+	err := r.redisClient.Cache().Get(ctx, key, doc)
+	if err == cache.ErrCacheMiss {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (r *sitemapRepository) SetDocument(ctx context.Context, key string, doc *model.SitemapDocument) error {
+	return r.redisClient.Cache().Set(&cache.Item{
+		Ctx:   ctx,
+		Key:   key,
+		Value: doc,
+		TTL:   config.Cfg().RedisTTL,
+	})
+}
+
+// invalidateSitemaps drops the cached sitemaps, it is called whenever a published post may have changed.
+func invalidateSitemaps(ctx context.Context, redisClient redis.Client) error {
+	return redisClient.Conn().Incr(ctx, sitemapGenerationKey).Err()
+}
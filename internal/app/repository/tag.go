@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+)
+
+type TagRepository interface {
+	List(ctx context.Context, limit, offset int, name string) ([]*model.Tag, error)
+}
+
+func NewTagRepository(postgresClient postgres.Client) TagRepository {
+	return &tagRepository{postgresClient}
+}
+
+type tagRepository struct {
+	postgresClient postgres.Client
+}
+
+func (r *tagRepository) List(ctx context.Context, limit, offset int, name string) ([]*model.Tag, error) {
+	query := `
+	SELECT
+		tag.id,
+		tag.name,
+		tag.created_at,
+		COUNT(post.id)
+	FROM
+		tag
+	LEFT JOIN
+		post_tag ON tag.id = post_tag.tag_id
+	LEFT JOIN
+		post ON post.id = post_tag.post_id AND post.status = 'published'
+	WHERE
+		tag.name LIKE $1
+	GROUP BY
+		tag.id
+	ORDER BY
+		COUNT(post.id) DESC, tag.name
+	LIMIT
+		$2 OFFSET $3`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query,
+		"%"+name+"%",
+		limit,
+		offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*model.Tag
+	for rows.Next() {
+		tag := new(model.Tag)
+		err := rows.Scan(&tag.ID, &tag.Name, &tag.CreatedAt, &tag.PostCount)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	redisclient "github.com/go-redis/redis/v8"
+)
+
+type TrendingRepository interface {
+	Recompute(ctx context.Context, window string, since time.Time) error
+	Exists(ctx context.Context, window string) (bool, error)
+	List(ctx context.Context, window string, limit, offset int) ([]int64, error)
+	Remove(ctx context.Context, window string, postID int64) error
+}
+
+func NewTrendingRepository(postgresClient postgres.Client, redisClient redis.Client) TrendingRepository {
+	return &trendingRepository{postgresClient, redisClient}
+}
+
+type trendingRepository struct {
+	postgresClient postgres.Client
+	redisClient    redis.Client
+}
+
+const (
+	// trendingSize caps how many posts are kept ranked per window
+	trendingSize = 1000
+
+	trendingViewWeight     = 1
+	trendingReactionWeight = 5
+	trendingCommentWeight  = 10
+
+	// trendingGravity controls how fast older posts sink, the score is divided by
+	// (age in hours + 2) ^ trendingGravity
+	trendingGravity = 1.5
+)
+
+func trendingKey(window string) string {
+	return fmt.Sprintf("trending_posts_%s", window)
+}
+
+// Recompute ranks posts by their engagement since the given time, weighted and decayed by
+// post age, and atomically replaces the sorted set of the window.
+func (r *trendingRepository) Recompute(ctx context.Context, window string, since time.Time) error {
+	query := `
+	SELECT
+		post.id,
+		(
+			$2::float8 * COALESCE((
+				SELECT
+					SUM(post_stats.views)
+				FROM
+					post_stats
+				WHERE
+					post_stats.post_id = post.id AND post_stats.day >= $1::date
+			), 0) +
+			$3::float8 * (
+				SELECT
+					COUNT(*)
+				FROM
+					reaction
+				WHERE
+					reaction.post_id = post.id AND reaction.created_at >= $1
+			) +
+			$4::float8 * (
+				SELECT
+					COUNT(*)
+				FROM
+					comment
+				WHERE
+					comment.post_id = post.id AND comment.created_at >= $1
+			)
+		) / POWER(GREATEST(EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP::timestamp - COALESCE(post.published_at, post.created_at)))::float8, 0) / 3600 + 2, $5::float8) AS score
+	FROM
+		post
+	WHERE
+		post.status = 'published'
+	ORDER BY
+		score DESC
+	LIMIT
+		$6`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query,
+		since,
+		trendingViewWeight,
+		trendingReactionWeight,
+		trendingCommentWeight,
+		trendingGravity,
+		trendingSize)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var members []*redisclient.Z
+	for rows.Next() {
+		var postID int64
+		var score float64
+		err := rows.Scan(&postID, &score)
+		if err != nil {
+			return err
+		}
+		if score > 0 {
+			members = append(members, &redisclient.Z{Score: score, Member: postID})
+		}
+	}
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	key := trendingKey(window)
+	pipe := r.redisClient.Conn().TxPipeline()
+	pipe.Del(ctx, key)
+	if len(members) > 0 {
+		pipe.ZAdd(ctx, key, members...)
+	} else {
+		// keep an empty marker so readers know the window has been computed
+		pipe.ZAdd(ctx, key, &redisclient.Z{Score: 0, Member: 0})
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (r *trendingRepository) Exists(ctx context.Context, window string) (bool, error) {
+	n, err := r.redisClient.Conn().Exists(ctx, trendingKey(window)).Result()
+	return n > 0, err
+}
+
+func (r *trendingRepository) List(ctx context.Context, window string, limit, offset int) ([]int64, error) {
+	members, err := r.redisClient.Conn().ZRevRangeByScore(ctx, trendingKey(window), &redisclient.ZRangeBy{
+		Min:    "(0",
+		Max:    "+inf",
+		Offset: int64(offset),
+		Count:  int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Remove drops a post from the ranking of the window until the next recompute.
+func (r *trendingRepository) Remove(ctx context.Context, window string, postID int64) error {
+	return r.redisClient.Conn().ZRem(ctx, trendingKey(window), postID).Err()
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	redisclient "github.com/go-redis/redis/v8"
+)
+
+type ViewRepository interface {
+	Record(ctx context.Context, postID int64, visitor string, at time.Time) error
+	Flush(ctx context.Context) error
+	ListStats(ctx context.Context, postID int64, from, to time.Time) ([]*model.PostStat, error)
+}
+
+func NewViewRepository(postgresClient postgres.Client, redisClient redis.Client) ViewRepository {
+	return &viewRepository{postgresClient, redisClient}
+}
+
+type viewRepository struct {
+	postgresClient postgres.Client
+	redisClient    redis.Client
+}
+
+const (
+	viewKeyPrefix   = "post_views_"
+	uniqueKeyPrefix = "post_uniques_"
+	dayLayout       = "20060102"
+
+	// daily counters only need to outlive the last flush of their day
+	viewCounterTTL = 48 * time.Hour
+)
+
+func viewKey(postID int64, day string) string {
+	return fmt.Sprintf("%s%d_%s", viewKeyPrefix, postID, day)
+}
+
+func uniqueKey(postID int64, day string) string {
+	return fmt.Sprintf("%s%d_%s", uniqueKeyPrefix, postID, day)
+}
+
+// Record counts a view of the post unless the same visitor already viewed it within the
+// dedup window, unique visitors are estimated per day with a HyperLogLog.
+func (r *viewRepository) Record(ctx context.Context, postID int64, visitor string, at time.Time) error {
+	dedupKey := fmt.Sprintf("post_view_dedup_%d_%s", postID, visitor)
+	fresh, err := r.redisClient.Conn().SetNX(ctx, dedupKey, 1, config.Cfg().ViewDedupWindow).Result()
+	if err != nil || !fresh {
+		return err
+	}
+
+	day := at.UTC().Format(dayLayout)
+	pipe := r.redisClient.Conn().TxPipeline()
+	pipe.Incr(ctx, viewKey(postID, day))
+	pipe.Expire(ctx, viewKey(postID, day), viewCounterTTL)
+	pipe.PFAdd(ctx, uniqueKey(postID, day), visitor)
+	pipe.Expire(ctx, uniqueKey(postID, day), viewCounterTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Flush writes the daily counters kept in redis to post_stats. Counters are cumulative for
+// their day so flushing the same day twice is harmless.
+func (r *viewRepository) Flush(ctx context.Context) error {
+	query := `
+	INSERT INTO
+		post_stats (post_id, day, views, uniques)
+	SELECT
+		id, $2, $3, $4
+	FROM
+		post
+	WHERE
+		id = $1
+	ON CONFLICT (post_id, day) DO UPDATE SET
+		views = GREATEST(post_stats.views, EXCLUDED.views),
+		uniques = GREATEST(post_stats.uniques, EXCLUDED.uniques)`
+
+	iter := r.redisClient.Conn().Scan(ctx, 0, viewKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		parts := strings.SplitN(strings.TrimPrefix(iter.Val(), viewKeyPrefix), "_", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		postID, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		day := parts[1]
+		date, err := time.Parse(dayLayout, day)
+		if err != nil {
+			continue
+		}
+
+		views, err := r.redisClient.Conn().Get(ctx, iter.Val()).Int64()
+		if err == redisclient.Nil {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		uniques, err := r.redisClient.Conn().PFCount(ctx, uniqueKey(postID, day)).Result()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.postgresClient.Conn().Exec(ctx, query, postID, date, views, uniques)
+		if err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
+
+func (r *viewRepository) ListStats(ctx context.Context, postID int64, from, to time.Time) ([]*model.PostStat, error) {
+	query := `
+	SELECT
+		post_id, day, views, uniques
+	FROM
+		post_stats
+	WHERE
+		post_id = $1 AND day BETWEEN $2 AND $3
+	ORDER BY
+		day`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, postID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*model.PostStat
+	for rows.Next() {
+		stat := new(model.PostStat)
+		err := rows.Scan(&stat.PostID, &stat.Day, &stat.Views, &stat.Uniques)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type WebmentionRepository interface {
+	Set(ctx context.Context, mention *model.Webmention) error
+	List(ctx context.Context, postID int64, limit, offset int) ([]*model.Webmention, error)
+	Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.Webmention, error)
+	Verify(ctx context.Context, id int64, verifiedAt time.Time) error
+	Retry(ctx context.Context, mention *model.Webmention) error
+	Delete(ctx context.Context, id int64) error
+	EnqueueDeliveries(ctx context.Context, postID int64, source string, targets []string, createdAt time.Time) error
+	ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.WebmentionDelivery, error)
+	RetryDelivery(ctx context.Context, delivery *model.WebmentionDelivery) error
+	DeleteDelivery(ctx context.Context, id int64) error
+}
+
+func NewWebmentionRepository(postgresClient postgres.Client) WebmentionRepository {
+	return &webmentionRepository{postgresClient}
+}
+
+type webmentionRepository struct {
+	postgresClient postgres.Client
+}
+
+const webmentionColumns = `
+		id, post_id, source, target, status, attempts, next_check_at, created_at, verified_at`
+
+// Set stores a received mention and schedules its verification, a mention sent again is checked again
+// while a verified one stays listed until the check fails.
+func (r *webmentionRepository) Set(ctx context.Context, mention *model.Webmention) error {
+	query := `
+	INSERT INTO
+		webmention (post_id, source, target, status, next_check_at, created_at)
+	VALUES
+		($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (post_id, source) DO UPDATE SET
+		target = EXCLUDED.target, attempts = 0, next_check_at = EXCLUDED.next_check_at
+	RETURNING
+		id`
+
+	return r.postgresClient.Conn().QueryRow(ctx, query,
+		mention.PostID, mention.Source, mention.Target, mention.Status, mention.NextCheckAt, mention.CreatedAt,
+	).Scan(&mention.ID)
+}
+
+// List lists the verified mentions of a post, the most recent first.
+func (r *webmentionRepository) List(ctx context.Context, postID int64, limit, offset int) ([]*model.Webmention, error) {
+	query := `
+	SELECT` + webmentionColumns + `
+	FROM
+		webmention
+	WHERE
+		post_id = $1 AND status = 'verified'
+	ORDER BY
+		created_at DESC
+	LIMIT
+		$2 OFFSET $3`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, postID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mentions []*model.Webmention
+	for rows.Next() {
+		mention := new(model.Webmention)
+		err := scanWebmention(rows, mention)
+		if err != nil {
+			return nil, err
+		}
+		mentions = append(mentions, mention)
+	}
+
+	return mentions, nil
+}
+
+// Claim returns the mentions due for verification and postpones them until leaseUntil, so a mention is
+// not checked twice when several workers run at once.
+func (r *webmentionRepository) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.Webmention, error) {
+	query := `
+	UPDATE
+		webmention
+	SET
+		next_check_at = $2
+	WHERE
+		id IN (
+			SELECT
+				id
+			FROM
+				webmention
+			WHERE
+				next_check_at <= $1
+			ORDER BY
+				next_check_at
+			LIMIT
+				$3
+			FOR UPDATE SKIP LOCKED
+		)
+	RETURNING` + webmentionColumns
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mentions []*model.Webmention
+	for rows.Next() {
+		mention := new(model.Webmention)
+		err := scanWebmention(rows, mention)
+		if err != nil {
+			return nil, err
+		}
+		mentions = append(mentions, mention)
+	}
+
+	return mentions, nil
+}
+
+func (r *webmentionRepository) Verify(ctx context.Context, id int64, verifiedAt time.Time) error {
+	query := `
+	UPDATE
+		webmention
+	SET
+		status = 'verified', attempts = 0, next_check_at = NULL, verified_at = $2
+	WHERE
+		id = $1`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query, id, verifiedAt)
+	return err
+}
+
+func (r *webmentionRepository) Retry(ctx context.Context, mention *model.Webmention) error {
+	query := `
+	UPDATE
+		webmention
+	SET
+		attempts = $2, next_check_at = $3
+	WHERE
+		id = $1`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query, mention.ID, mention.Attempts, mention.NextCheckAt)
+	return err
+}
+
+func (r *webmentionRepository) Delete(ctx context.Context, id int64) error {
+	query := `
+	DELETE FROM
+		webmention
+	WHERE
+		id = $1`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query, id)
+	return err
+}
+
+// EnqueueDeliveries queues a mention of each target, a target still queued from an earlier change of the
+// post is sent once.
+func (r *webmentionRepository) EnqueueDeliveries(ctx context.Context, postID int64, source string, targets []string, createdAt time.Time) error {
+	query := `
+	INSERT INTO
+		webmention_delivery (post_id, source, target, next_attempt_at, created_at)
+	SELECT
+		$1, $2, target, $4, $4
+	FROM
+		UNNEST($3::text[]) AS t(target)
+	ON CONFLICT (post_id, target) DO UPDATE SET
+		attempts = 0, last_error = '', next_attempt_at = EXCLUDED.next_attempt_at`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query, postID, source, targets, createdAt)
+	return err
+}
+
+// ClaimDeliveries returns the deliveries that are due and postpones them until leaseUntil.
+func (r *webmentionRepository) ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.WebmentionDelivery, error) {
+	query := `
+	UPDATE
+		webmention_delivery
+	SET
+		next_attempt_at = $2
+	WHERE
+		id IN (
+			SELECT
+				id
+			FROM
+				webmention_delivery
+			WHERE
+				next_attempt_at <= $1
+			ORDER BY
+				next_attempt_at
+			LIMIT
+				$3
+			FOR UPDATE SKIP LOCKED
+		)
+	RETURNING
+		id, post_id, source, target, attempts, last_error, next_attempt_at, created_at`
+
+	rows, err := r.postgresClient.Conn().Query(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*model.WebmentionDelivery
+	for rows.Next() {
+		delivery := new(model.WebmentionDelivery)
+		err := rows.Scan(
+			&delivery.ID, &delivery.PostID, &delivery.Source, &delivery.Target,
+			&delivery.Attempts, &delivery.LastError, &delivery.NextAttemptAt, &delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (r *webmentionRepository) RetryDelivery(ctx context.Context, delivery *model.WebmentionDelivery) error {
+	query := `
+	UPDATE
+		webmention_delivery
+	SET
+		attempts = $2, last_error = $3, next_attempt_at = $4
+	WHERE
+		id = $1`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query,
+		delivery.ID, delivery.Attempts, delivery.LastError, delivery.NextAttemptAt,
+	)
+	return err
+}
+
+func (r *webmentionRepository) DeleteDelivery(ctx context.Context, id int64) error {
+	query := `
+	DELETE FROM
+		webmention_delivery
+	WHERE
+		id = $1`
+
+	_, err := r.postgresClient.Conn().Exec(ctx, query, id)
+	return err
+}
+
+// scanWebmention scans the columns selected by webmentionColumns into mention.
+func scanWebmention(row pgx.Row, mention *model.Webmention) error {
+	return row.Scan(
+		&mention.ID,
+		&mention.PostID,
+		&mention.Source,
+		&mention.Target,
+		&mention.Status,
+		&mention.Attempts,
+		&mention.NextCheckAt,
+		&mention.CreatedAt,
+		&mention.VerifiedAt)
+}
@@ -110,6 +110,10 @@ func (s *accountService) Update(ctx context.Context, req model.AccountUpdateRequ
 		}
 	}
 
+	if req.Version != 0 && req.Version != account.Version {
+		return nil, constant.ErrPrecondition
+	}
+
 	account.Name = req.Name
 	account.Email = req.Email
 	account.UpdatedAt.Time = time.Now()
@@ -117,7 +121,12 @@ func (s *accountService) Update(ctx context.Context, req model.AccountUpdateRequ
 	err = s.accountRepository.Update(ctx, account)
 	if err != nil {
 		logger.Log().Err(err).Msg("failed to update account")
-		return nil, constant.ErrServer
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrPrecondition
+		default:
+			return nil, constant.ErrServer
+		}
 	}
 
 	return model.NewAccountResponse(account), nil
@@ -139,6 +148,10 @@ func (s *accountService) UpdatePassword(ctx context.Context, req model.AccountPa
 		}
 	}
 
+	if req.Version != 0 && req.Version != account.Version {
+		return nil, constant.ErrPrecondition
+	}
+
 	err = bcrypt.CompareHashAndPassword([]byte(account.Password), []byte(req.OldPassword))
 	if err != nil {
 		return nil, constant.ErrWrongPassword
@@ -156,7 +169,12 @@ func (s *accountService) UpdatePassword(ctx context.Context, req model.AccountPa
 	err = s.accountRepository.Update(ctx, account)
 	if err != nil {
 		logger.Log().Err(err).Msg("failed to update account password")
-		return nil, constant.ErrServer
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrPrecondition
+		default:
+			return nil, constant.ErrServer
+		}
 	}
 
 	return model.NewAccountResponse(account), nil
@@ -167,10 +185,30 @@ func (s *accountService) Delete(ctx context.Context, req model.AccountDeleteRequ
 		return constant.ErrUnauthorized
 	}
 
-	err := s.accountRepository.Delete(ctx, req.ID)
+	account, err := s.accountRepository.Get(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get account by id")
+		switch err {
+		case pgx.ErrNoRows:
+			return constant.ErrAccountNotFound
+		default:
+			return constant.ErrServer
+		}
+	}
+
+	if req.Version != 0 && req.Version != account.Version {
+		return constant.ErrPrecondition
+	}
+
+	err = s.accountRepository.Delete(ctx, req.ID, account.Version)
 	if err != nil {
 		logger.Log().Err(err).Msg("failed to delete account")
-		return constant.ErrServer
+		switch err {
+		case pgx.ErrNoRows:
+			return constant.ErrPrecondition
+		default:
+			return constant.ErrServer
+		}
 	}
 
 	return nil
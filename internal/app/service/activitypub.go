@@ -0,0 +1,507 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/activitypub"
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type ActivityPubService interface {
+	WebFinger(ctx context.Context, req model.WebFingerRequest) (*model.WebFingerResponse, error)
+	GetActor(ctx context.Context, req model.ActorGetRequest) (*activitypub.Actor, error)
+	GetOutbox(ctx context.Context, req model.OutboxGetRequest) (*activitypub.OrderedCollection, error)
+	GetFollowers(ctx context.Context, req model.FollowersGetRequest) (*activitypub.OrderedCollection, error)
+	GetObject(ctx context.Context, req model.ObjectGetRequest) (*activitypub.Article, error)
+	Inbox(ctx context.Context, req model.InboxRequest) error
+	Deliver(ctx context.Context) error
+}
+
+func NewActivityPubService(
+	activityPubRepository repository.ActivityPubRepository,
+	accountRepository repository.AccountRepository,
+	postRepository repository.PostRepository,
+) ActivityPubService {
+	return &activityPubService{
+		activityPubRepository,
+		accountRepository,
+		postRepository,
+		activitypub.NewClient(config.Cfg().ActivityPubTimeout),
+	}
+}
+
+type activityPubService struct {
+	activityPubRepository repository.ActivityPubRepository
+	accountRepository     repository.AccountRepository
+	postRepository        repository.PostRepository
+	client                *activitypub.Client
+}
+
+// WebFinger resolves acct:{account_id}@{host} or the actor URL to the actor of the account.
+func (s *activityPubService) WebFinger(ctx context.Context, req model.WebFingerRequest) (*model.WebFingerResponse, error) {
+	accountID, valid := parseResource(req.Resource)
+	if !valid {
+		return nil, constant.ErrWebFingerResource
+	}
+
+	account, err := s.getAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WebFingerResponse{
+		Subject: fmt.Sprintf("acct:%d@%s", account.ID, siteHost()),
+		Aliases: []string{actorURL(account.ID)},
+		Links: []model.WebFingerLink{
+			{Rel: "self", Type: activitypub.ContentType, Href: actorURL(account.ID)},
+			{Rel: "http://webfinger.net/rel/profile-page", Type: "text/html", Href: accountPageURL(account.ID)},
+		},
+	}, nil
+}
+
+func (s *activityPubService) GetActor(ctx context.Context, req model.ActorGetRequest) (*activitypub.Actor, error) {
+	account, err := s.getAccount(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.getKey(ctx, account.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get actor key")
+		return nil, constant.ErrServer
+	}
+
+	id := actorURL(account.ID)
+	return &activitypub.Actor{
+		Context:           []string{activitypub.ActivityStreamsContext, activitypub.SecurityContext},
+		ID:                id,
+		Type:              "Person",
+		PreferredUsername: strconv.FormatInt(account.ID, 10),
+		Name:              account.Name,
+		URL:               accountPageURL(account.ID),
+		Inbox:             accountURL(account.ID, "inbox"),
+		Outbox:            accountURL(account.ID, "outbox"),
+		Followers:         accountURL(account.ID, "followers"),
+		PublicKey: activitypub.PublicKey{
+			ID:           keyID(account.ID),
+			Owner:        id,
+			PublicKeyPem: key.PublicKey,
+		},
+	}, nil
+}
+
+// GetOutbox lists the Create activities of the latest posts the account published.
+func (s *activityPubService) GetOutbox(ctx context.Context, req model.OutboxGetRequest) (*activitypub.OrderedCollection, error) {
+	account, err := s.getAccount(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.postRepository.ListPublished(ctx, account.ID, config.Cfg().FeedItemCount)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list published posts")
+		return nil, constant.ErrServer
+	}
+
+	res := &activitypub.OrderedCollection{
+		Context:      activitypub.ActivityStreamsContext,
+		ID:           accountURL(account.ID, "outbox"),
+		Type:         "OrderedCollection",
+		TotalItems:   len(posts),
+		OrderedItems: make([]interface{}, len(posts)),
+	}
+	for i, post := range model.NewPostListResponse(posts) {
+		article := newArticle(post)
+		activity := newActivity(article.ID+"#create", constant.ACTIVITY_CREATE, account.ID, article)
+		activity.Context = nil
+		activity.Published = article.Published
+		res.OrderedItems[i] = activity
+	}
+
+	return res, nil
+}
+
+// GetFollowers only exposes how many followers an account has, who follows it is kept private.
+func (s *activityPubService) GetFollowers(ctx context.Context, req model.FollowersGetRequest) (*activitypub.OrderedCollection, error) {
+	account, err := s.getAccount(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.activityPubRepository.CountFollowers(ctx, account.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to count followers")
+		return nil, constant.ErrServer
+	}
+
+	return &activitypub.OrderedCollection{
+		Context:    activitypub.ActivityStreamsContext,
+		ID:         accountURL(account.ID, "followers"),
+		Type:       "OrderedCollection",
+		TotalItems: count,
+	}, nil
+}
+
+func (s *activityPubService) GetObject(ctx context.Context, req model.ObjectGetRequest) (*activitypub.Article, error) {
+	post, err := s.postRepository.Get(ctx, req.PostID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get post")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrPostNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	if post.Status != constant.POST_STATUS_PUBLISHED {
+		return nil, constant.ErrPostNotFound
+	}
+
+	return newArticle(model.NewPostResponse(post)), nil
+}
+
+// Inbox handles an activity posted to an account, the request has to be signed by the actor of the
+// activity. Follows are accepted right away, other activities than follows and their undo are ignored.
+func (s *activityPubService) Inbox(ctx context.Context, req model.InboxRequest) error {
+	account, err := s.getAccount(ctx, req.AccountID)
+	if err != nil {
+		return err
+	}
+
+	key, err := s.getKey(ctx, account.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get actor key")
+		return constant.ErrServer
+	}
+
+	privateKey, err := activitypub.ParsePrivateKey(key.PrivateKey)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to parse actor key")
+		return constant.ErrServer
+	}
+
+	signer, err := activitypub.Verify(req.Request, req.Body, s.client.FetchKey(keyID(account.ID), privateKey))
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to verify inbox signature")
+		return constant.ErrSignature
+	}
+
+	activity, err := activitypub.ParseActivity(req.Body)
+	if err != nil {
+		return constant.ErrActivity
+	} else if activity.Actor != signer {
+		return constant.ErrSignature
+	}
+
+	switch activity.Type {
+	case constant.ACTIVITY_FOLLOW:
+		if activity.ObjectID() != actorURL(account.ID) {
+			return constant.ErrActivity
+		}
+		return s.follow(ctx, account.ID, privateKey, activity, req.Body)
+	case constant.ACTIVITY_UNDO:
+		if activity.ObjectType() != constant.ACTIVITY_FOLLOW {
+			return nil
+		}
+
+		err = s.activityPubRepository.DeleteFollower(ctx, account.ID, activity.Actor)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to delete follower")
+			return constant.ErrServer
+		}
+	}
+
+	return nil
+}
+
+// Deliver sends the queued activities that are due, failed deliveries are retried with an exponential
+// backoff until the maximum number of attempts is reached.
+func (s *activityPubService) Deliver(ctx context.Context) error {
+	now := time.Now()
+	lease := config.Cfg().ActivityPubTimeout * constant.ACTIVITY_DELIVERY_BATCH
+	deliveries, err := s.activityPubRepository.ClaimDeliveries(ctx, now, now.Add(lease), constant.ACTIVITY_DELIVERY_BATCH)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		key, err := s.getKey(ctx, delivery.AccountID)
+		if err != nil {
+			return err
+		}
+
+		privateKey, err := activitypub.ParsePrivateKey(key.PrivateKey)
+		if err != nil {
+			return err
+		}
+
+		err = s.client.Deliver(ctx, delivery.Inbox, delivery.Activity, keyID(delivery.AccountID), privateKey)
+		if err == nil {
+			err = s.activityPubRepository.DeleteDelivery(ctx, delivery.ID)
+			if err != nil {
+				return err
+			}
+			continue
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		delivery.Attempts++
+		if delivery.Attempts >= config.Cfg().ActivityPubMaxAttempts {
+			logger.Log().Err(err).Msgf("giving up delivering activity to %s", delivery.Inbox)
+			err = s.activityPubRepository.DeleteDelivery(ctx, delivery.ID)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = time.Now().Add(constant.ACTIVITY_RETRY_DELAY << (delivery.Attempts - 1))
+		err = s.activityPubRepository.RetryDelivery(ctx, delivery)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// follow stores the remote actor as a follower and queues the Accept, the inbox comes from the actor
+// document rather than from the activity.
+func (s *activityPubService) follow(ctx context.Context, accountID int64, privateKey *rsa.PrivateKey, activity *activitypub.IncomingActivity, body []byte) error {
+	actor, err := s.client.FetchActor(ctx, activity.Actor, keyID(accountID), privateKey)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to fetch actor")
+		return constant.ErrActivity
+	}
+
+	inbox := actor.Inbox
+	if actor.Endpoints != nil && actor.Endpoints.SharedInbox != "" {
+		inbox = actor.Endpoints.SharedInbox
+	}
+
+	err = s.activityPubRepository.SetFollower(ctx, &model.Follower{
+		AccountID: accountID,
+		Actor:     actor.ID,
+		Inbox:     inbox,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to set follower")
+		return constant.ErrServer
+	}
+
+	accept := &activitypub.Activity{
+		Context: activitypub.ActivityStreamsContext,
+		ID:      activityID(actorURL(accountID), constant.ACTIVITY_ACCEPT),
+		Type:    constant.ACTIVITY_ACCEPT,
+		Actor:   actorURL(accountID),
+		To:      []string{actor.ID},
+		Object:  json.RawMessage(body),
+	}
+
+	data, err := json.Marshal(accept)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to marshal activity")
+		return constant.ErrServer
+	}
+
+	err = s.activityPubRepository.Enqueue(ctx, &model.ActivityDelivery{
+		AccountID:     accountID,
+		Inbox:         actor.Inbox,
+		Activity:      data,
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to enqueue activity")
+		return constant.ErrServer
+	}
+
+	return nil
+}
+
+func (s *activityPubService) getAccount(ctx context.Context, id int64) (*model.Account, error) {
+	account, err := s.accountRepository.Get(ctx, id)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get account")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrAccountNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+	return account, nil
+}
+
+// getKey returns the key pair of an actor, it is generated the first time the actor is needed.
+func (s *activityPubService) getKey(ctx context.Context, accountID int64) (*model.ActorKey, error) {
+	key, err := s.activityPubRepository.GetKey(ctx, accountID)
+	if err != pgx.ErrNoRows {
+		return key, err
+	}
+
+	privateKey, publicKey, err := activitypub.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.activityPubRepository.CreateKey(ctx, &model.ActorKey{
+		AccountID:  accountID,
+		PublicKey:  publicKey,
+		PrivateKey: privateKey,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.activityPubRepository.GetKey(ctx, accountID)
+}
+
+// enqueueActivity queues an activity about a post for the followers of each of its authors, every author
+// publishes the activity as their own actor.
+func enqueueActivity(ctx context.Context, activityPubRepository repository.ActivityPubRepository, activityType string, post *model.PostResponse) error {
+	var object interface{} = newArticle(post)
+	if activityType == constant.ACTIVITY_DELETE {
+		object = &activitypub.Tombstone{ID: objectURL(post.ID), Type: "Tombstone"}
+	}
+
+	for _, accountID := range authorIDs(post) {
+		activity := newActivity(activityID(objectURL(post.ID), activityType), activityType, accountID, object)
+		data, err := json.Marshal(activity)
+		if err != nil {
+			return err
+		}
+
+		err = activityPubRepository.EnqueueForFollowers(ctx, accountID, data, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newActivity(id, activityType string, accountID int64, object interface{}) *activitypub.Activity {
+	return &activitypub.Activity{
+		Context: activitypub.ActivityStreamsContext,
+		ID:      id,
+		Type:    activityType,
+		Actor:   actorURL(accountID),
+		To:      []string{activitypub.Public},
+		Cc:      []string{accountURL(accountID, "followers")},
+		Object:  object,
+	}
+}
+
+// newArticle renders a post as the object federated to other servers, the post page is its URL.
+func newArticle(post *model.PostResponse) *activitypub.Article {
+	ids := authorIDs(post)
+	article := &activitypub.Article{
+		ID:           objectURL(post.ID),
+		Type:         "Article",
+		AttributedTo: make([]string, len(ids)),
+		Name:         post.Title,
+		Summary:      html.EscapeString(summarize(post.Body)),
+		Content:      bodyHTML(post.Body),
+		URL:          postURL(post.ID),
+		To:           []string{activitypub.Public},
+		Cc:           make([]string, len(ids)),
+	}
+	for i, id := range ids {
+		article.AttributedTo[i] = actorURL(id)
+		article.Cc[i] = accountURL(id, "followers")
+	}
+	for _, tag := range post.Tags {
+		article.Tag = append(article.Tag, activitypub.Tag{Type: "Hashtag", Name: "#" + tag})
+	}
+	if post.PublishedAt != nil {
+		article.Published = post.PublishedAt.UTC().Format(time.RFC3339)
+	}
+	if post.UpdatedAt != nil {
+		article.Updated = post.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	return article
+}
+
+// authorIDs lists the accounts who wrote the post, reviewers do not publish it.
+func authorIDs(post *model.PostResponse) []int64 {
+	var ids []int64
+	for _, author := range post.Authors {
+		if author.Role != constant.POST_AUTHOR_REVIEWER {
+			ids = append(ids, author.AccountID)
+		}
+	}
+	if len(ids) == 0 {
+		return []int64{post.AccountID}
+	}
+	return ids
+}
+
+// activityID names an activity after the object or actor it is about, the time keeps it unique.
+func activityID(base, activityType string) string {
+	return fmt.Sprintf("%s#%s-%d", base, strings.ToLower(activityType), time.Now().UnixNano())
+}
+
+func actorURL(accountID int64) string {
+	return accountURL(accountID, "actor")
+}
+
+func accountURL(accountID int64, name string) string {
+	return siteURL(fmt.Sprintf("/v1/accounts/%d/%s", accountID, name))
+}
+
+func keyID(accountID int64) string {
+	return actorURL(accountID) + "#main-key"
+}
+
+func objectURL(postID int64) string {
+	return siteURL(fmt.Sprintf("/v1/posts/%d/object", postID))
+}
+
+func siteHost() string {
+	u, err := url.Parse(config.Cfg().SiteUrl)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// parseResource extracts the account id from a WebFinger resource, either acct:{account_id}@{host} or
+// the actor URL.
+func parseResource(resource string) (int64, bool) {
+	if strings.HasPrefix(resource, "acct:") {
+		parts := strings.SplitN(strings.TrimPrefix(resource, "acct:"), "@", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[1], siteHost()) {
+			return 0, false
+		}
+
+		id, err := strconv.ParseInt(parts[0], 10, 64)
+		return id, err == nil
+	}
+
+	prefix, suffix := siteURL("/v1/accounts/"), "/actor"
+	if !strings.HasPrefix(resource, prefix) || !strings.HasSuffix(resource, suffix) {
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(resource, prefix), suffix), 10, 64)
+	return id, err == nil
+}
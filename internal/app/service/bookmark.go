@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/security/middleware"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type BookmarkService interface {
+	Create(ctx context.Context, req model.BookmarkCreateRequest) (*model.BookmarkResponse, error)
+	List(ctx context.Context, req model.BookmarkListRequest) (*model.BookmarkListResponse, error)
+	ListFolders(ctx context.Context, req model.BookmarkFolderListRequest) ([]*model.BookmarkFolderResponse, error)
+	Delete(ctx context.Context, req model.BookmarkDeleteRequest) error
+}
+
+func NewBookmarkService(bookmarkRepository repository.BookmarkRepository, postRepository repository.PostRepository) BookmarkService {
+	return &bookmarkService{bookmarkRepository, postRepository}
+}
+
+type bookmarkService struct {
+	bookmarkRepository repository.BookmarkRepository
+	postRepository     repository.PostRepository
+}
+
+func (s *bookmarkService) Create(ctx context.Context, req model.BookmarkCreateRequest) (*model.BookmarkResponse, error) {
+	if !middleware.IsMe(ctx, req.AccountID) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	post, err := s.postRepository.Get(ctx, req.PostID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get post")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrPostNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	if !isPostVisible(ctx, post) {
+		return nil, constant.ErrPostNotFound
+	}
+
+	bookmark := &model.Bookmark{
+		Folder:    req.Folder,
+		CreatedAt: time.Now(),
+		AccountID: req.AccountID,
+		PostID:    post.ID,
+		Post:      *post,
+	}
+
+	err = s.bookmarkRepository.Create(ctx, bookmark)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to create bookmark")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewBookmarkResponse(bookmark), nil
+}
+
+func (s *bookmarkService) List(ctx context.Context, req model.BookmarkListRequest) (*model.BookmarkListResponse, error) {
+	if !middleware.IsMe(ctx, req.AccountID) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	// one extra bookmark tells whether another page follows
+	bookmarks, err := s.bookmarkRepository.List(ctx, req.AccountID, req.Limit+1, req.Cursor, req.Folder)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list bookmarks")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewBookmarkListResponse(bookmarks, req.Limit), nil
+}
+
+func (s *bookmarkService) ListFolders(ctx context.Context, req model.BookmarkFolderListRequest) ([]*model.BookmarkFolderResponse, error) {
+	if !middleware.IsMe(ctx, req.AccountID) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	folders, err := s.bookmarkRepository.ListFolders(ctx, req.AccountID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list bookmark folders")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewBookmarkFolderListResponse(folders), nil
+}
+
+func (s *bookmarkService) Delete(ctx context.Context, req model.BookmarkDeleteRequest) error {
+	if !middleware.IsMe(ctx, req.AccountID) {
+		return constant.ErrUnauthorized
+	}
+
+	err := s.bookmarkRepository.Delete(ctx, req.AccountID, req.PostID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to delete bookmark")
+		return constant.ErrServer
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/security/middleware"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type CategoryService interface {
+	Create(ctx context.Context, req model.CategoryCreateRequest) (*model.CategoryResponse, error)
+	List(ctx context.Context, req model.CategoryListRequest) ([]*model.CategoryResponse, error)
+	Get(ctx context.Context, req model.CategoryGetRequest) (*model.CategoryResponse, error)
+	Update(ctx context.Context, req model.CategoryUpdateRequest) (*model.CategoryResponse, error)
+	Delete(ctx context.Context, req model.CategoryDeleteRequest) error
+	ListPosts(ctx context.Context, req model.CategoryPostListRequest) ([]*model.PostResponse, error)
+}
+
+func NewCategoryService(categoryRepository repository.CategoryRepository, postRepository repository.PostRepository) CategoryService {
+	return &categoryService{categoryRepository, postRepository}
+}
+
+type categoryService struct {
+	categoryRepository repository.CategoryRepository
+	postRepository     repository.PostRepository
+}
+
+func (s *categoryService) Create(ctx context.Context, req model.CategoryCreateRequest) (*model.CategoryResponse, error) {
+	if !middleware.IsAdmin(ctx) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	category := &model.Category{
+		Name:      req.Name,
+		CreatedAt: time.Now(),
+	}
+
+	if req.ParentID != nil {
+		_, err := s.categoryRepository.Get(ctx, *req.ParentID)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to get parent category")
+			switch err {
+			case pgx.ErrNoRows:
+				return nil, constant.ErrCategoryNotFound
+			default:
+				return nil, constant.ErrServer
+			}
+		}
+		category.ParentID = sql.NullInt64{Int64: *req.ParentID, Valid: true}
+	}
+
+	err := s.categoryRepository.Create(ctx, category)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to create category")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewCategoryResponse(category), nil
+}
+
+func (s *categoryService) List(ctx context.Context, req model.CategoryListRequest) ([]*model.CategoryResponse, error) {
+	categories, err := s.categoryRepository.List(ctx, req.Limit, req.Offset, req.Name, req.ParentID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list categories")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewCategoryListResponse(categories), nil
+}
+
+func (s *categoryService) Get(ctx context.Context, req model.CategoryGetRequest) (*model.CategoryResponse, error) {
+	category, err := s.categoryRepository.Get(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get category")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrCategoryNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	return model.NewCategoryResponse(category), nil
+}
+
+func (s *categoryService) Update(ctx context.Context, req model.CategoryUpdateRequest) (*model.CategoryResponse, error) {
+	if !middleware.IsAdmin(ctx) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	category, err := s.categoryRepository.Get(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get category")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrCategoryNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	category.ParentID = sql.NullInt64{}
+	if req.ParentID != nil {
+		_, err := s.categoryRepository.Get(ctx, *req.ParentID)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to get parent category")
+			switch err {
+			case pgx.ErrNoRows:
+				return nil, constant.ErrCategoryNotFound
+			default:
+				return nil, constant.ErrServer
+			}
+		}
+
+		descendantIDs, err := s.categoryRepository.ListDescendantIDs(ctx, req.ID)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to list category descendants")
+			return nil, constant.ErrServer
+		}
+		for _, id := range descendantIDs {
+			if id == *req.ParentID {
+				return nil, constant.ErrCategoryParent
+			}
+		}
+
+		category.ParentID = sql.NullInt64{Int64: *req.ParentID, Valid: true}
+	}
+
+	category.Name = req.Name
+	category.UpdatedAt.Time = time.Now()
+
+	err = s.categoryRepository.Update(ctx, category)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to update category")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewCategoryResponse(category), nil
+}
+
+func (s *categoryService) Delete(ctx context.Context, req model.CategoryDeleteRequest) error {
+	if !middleware.IsAdmin(ctx) {
+		return constant.ErrUnauthorized
+	}
+
+	_, err := s.categoryRepository.Get(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get category")
+		switch err {
+		case pgx.ErrNoRows:
+			return constant.ErrCategoryNotFound
+		default:
+			return constant.ErrServer
+		}
+	}
+
+	children, err := s.categoryRepository.CountChildren(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to count category children")
+		return constant.ErrServer
+	} else if children > 0 {
+		return constant.ErrCategoryHasChildren
+	}
+
+	err = s.categoryRepository.Delete(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to delete category")
+		return constant.ErrServer
+	}
+
+	return nil
+}
+
+func (s *categoryService) ListPosts(ctx context.Context, req model.CategoryPostListRequest) ([]*model.PostResponse, error) {
+	_, err := s.categoryRepository.Get(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get category")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrCategoryNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	posts, err := s.postRepository.ListByCategory(ctx, req.ID, req.Limit, req.Offset)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list category posts")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewPostListResponse(posts), nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/security/middleware"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type CommentService interface {
+	Create(ctx context.Context, req model.CommentCreateRequest) (*model.CommentResponse, error)
+	List(ctx context.Context, req model.CommentListRequest) ([]*model.CommentResponse, error)
+	Update(ctx context.Context, req model.CommentUpdateRequest) (*model.CommentResponse, error)
+	Delete(ctx context.Context, req model.CommentDeleteRequest) error
+}
+
+func NewCommentService(commentRepository repository.CommentRepository, postRepository repository.PostRepository) CommentService {
+	return &commentService{commentRepository, postRepository}
+}
+
+type commentService struct {
+	commentRepository repository.CommentRepository
+	postRepository    repository.PostRepository
+}
+
+func (s *commentService) Create(ctx context.Context, req model.CommentCreateRequest) (*model.CommentResponse, error) {
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	if !valid {
+		return nil, constant.ErrUnauthorized
+	}
+
+	_, err := s.getPost(ctx, req.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &model.Comment{
+		Body:      req.Body,
+		CreatedAt: time.Now(),
+		PostID:    req.PostID,
+		AccountID: claimsID,
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.commentRepository.Get(ctx, *req.ParentID)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to get parent comment")
+			switch err {
+			case pgx.ErrNoRows:
+				return nil, constant.ErrCommentNotFound
+			default:
+				return nil, constant.ErrServer
+			}
+		}
+
+		if parent.PostID != req.PostID {
+			return nil, constant.ErrCommentParent
+		}
+		comment.ParentID = sql.NullInt64{Int64: parent.ID, Valid: true}
+	}
+
+	err = s.commentRepository.Create(ctx, comment)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to create comment")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewCommentResponse(comment), nil
+}
+
+func (s *commentService) List(ctx context.Context, req model.CommentListRequest) ([]*model.CommentResponse, error) {
+	_, err := s.getPost(ctx, req.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Tree {
+		comments, err := s.commentRepository.ListTree(ctx, req.PostID, req.Limit, req.Offset)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to list comment tree")
+			return nil, constant.ErrServer
+		}
+		return model.NewCommentTreeResponse(comments), nil
+	}
+
+	comments, err := s.commentRepository.List(ctx, req.PostID, req.Limit, req.Offset)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list comments")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewCommentListResponse(comments), nil
+}
+
+func (s *commentService) Update(ctx context.Context, req model.CommentUpdateRequest) (*model.CommentResponse, error) {
+	comment, err := s.getComment(ctx, req.ID, req.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !middleware.IsMe(ctx, comment.AccountID) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	comment.Body = req.Body
+	comment.UpdatedAt.Time = time.Now()
+
+	err = s.commentRepository.Update(ctx, comment)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to update comment")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewCommentResponse(comment), nil
+}
+
+func (s *commentService) Delete(ctx context.Context, req model.CommentDeleteRequest) error {
+	post, err := s.getPost(ctx, req.PostID)
+	if err != nil {
+		return err
+	}
+
+	comment, err := s.getComment(ctx, req.ID, req.PostID)
+	if err != nil {
+		return err
+	}
+
+	// post authors are allowed to moderate the discussion on their own posts
+	if !middleware.IsMe(ctx, comment.AccountID) && !canEditPost(ctx, post) {
+		return constant.ErrUnauthorized
+	}
+
+	err = s.commentRepository.Delete(ctx, comment.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to delete comment")
+		return constant.ErrServer
+	}
+
+	return nil
+}
+
+func (s *commentService) getPost(ctx context.Context, id int64) (*model.Post, error) {
+	post, err := s.postRepository.Get(ctx, id)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get post")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrPostNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	if !isPostVisible(ctx, post) {
+		return nil, constant.ErrPostNotFound
+	}
+	return post, nil
+}
+
+// getComment fetches a comment and makes sure it is addressed through the post it belongs to.
+func (s *commentService) getComment(ctx context.Context, id, postID int64) (*model.Comment, error) {
+	comment, err := s.commentRepository.Get(ctx, id)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get comment")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrCommentNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	if comment.PostID != postID {
+		return nil, constant.ErrCommentNotFound
+	}
+	return comment, nil
+}
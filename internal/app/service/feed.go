@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/feed"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/storage"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type FeedService interface {
+	Get(ctx context.Context, req model.FeedRequest) (*feed.Feed, error)
+	List(ctx context.Context, req model.FeedListRequest) (*feed.Feed, error)
+}
+
+func NewFeedService(
+	postService PostService,
+	postRepository repository.PostRepository,
+	accountRepository repository.AccountRepository,
+	mediaRepository repository.MediaRepository,
+	mediaStorage storage.Storage,
+) FeedService {
+	return &feedService{postService, postRepository, accountRepository, mediaRepository, mediaStorage}
+}
+
+type feedService struct {
+	postService       PostService
+	postRepository    repository.PostRepository
+	accountRepository repository.AccountRepository
+	mediaRepository   repository.MediaRepository
+	mediaStorage      storage.Storage
+}
+
+// Get builds the feed of the latest published posts, of the whole blog or of a single author.
+func (s *feedService) Get(ctx context.Context, req model.FeedRequest) (*feed.Feed, error) {
+	res := &feed.Feed{
+		Title:       config.Cfg().SiteTitle,
+		Description: config.Cfg().SiteDescription,
+		Link:        siteURL(""),
+		FeedLink:    siteURL(req.Path),
+	}
+
+	if req.AccountID != 0 {
+		account, err := s.accountRepository.Get(ctx, req.AccountID)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to get account")
+			switch err {
+			case pgx.ErrNoRows:
+				return nil, constant.ErrAccountNotFound
+			default:
+				return nil, constant.ErrServer
+			}
+		}
+		res.Title = fmt.Sprintf("%s - %s", account.Name, config.Cfg().SiteTitle)
+	}
+
+	posts, err := s.postRepository.ListPublished(ctx, req.AccountID, config.Cfg().FeedItemCount)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list published posts")
+		return nil, constant.ErrServer
+	}
+
+	err = s.setItems(ctx, res, model.NewPostListResponse(posts), req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// List builds a page of the feed from the posts listed by the API, next_url points to the following page
+// while more posts are left.
+func (s *feedService) List(ctx context.Context, req model.FeedListRequest) (*feed.Feed, error) {
+	res := &feed.Feed{
+		Title:       config.Cfg().SiteTitle,
+		Description: config.Cfg().SiteDescription,
+		Link:        siteURL(""),
+		FeedLink:    siteURL(req.Path),
+	}
+
+	// one more post than asked for tells whether there is a next page
+	posts, err := s.postService.List(ctx, model.PostListRequest{
+		Limit:  req.Limit + 1,
+		Offset: req.Offset,
+		Status: constant.POST_STATUS_PUBLISHED,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(posts) > req.Limit {
+		posts = posts[:req.Limit]
+		res.NextLink = res.FeedLink + "?" + url.Values{
+			"limit":   {strconv.Itoa(req.Limit)},
+			"offset":  {strconv.Itoa(req.Offset + req.Limit)},
+			"content": {req.Content},
+		}.Encode()
+	}
+
+	err = s.setItems(ctx, res, posts, req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// setItems adds the posts to the feed with their cover image as attachment, the feed is as recent as its
+// latest post.
+func (s *feedService) setItems(ctx context.Context, res *feed.Feed, posts []*model.PostResponse, content string) error {
+	covers, err := getCovers(ctx, s.mediaRepository, s.mediaStorage, posts...)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list media")
+		return constant.ErrServer
+	}
+
+	res.Items = make([]*feed.Item, len(posts))
+	for i, post := range posts {
+		item := &feed.Item{
+			ID:         postURL(post.ID),
+			Title:      post.Title,
+			Link:       postURL(post.ID),
+			Authors:    authorNames(post),
+			Categories: post.Tags,
+			Summary:    html.EscapeString(summarize(post.Body)),
+		}
+		if post.PublishedAt != nil {
+			item.Published = *post.PublishedAt
+		}
+		if post.UpdatedAt != nil {
+			item.Updated = *post.UpdatedAt
+		}
+		if content == constant.FEED_CONTENT_FULL {
+			item.Content = bodyHTML(post.Body)
+		}
+
+		if cover := coverOf(covers, post); cover != nil {
+			item.Image = cover.URL
+			item.Attachments = []*feed.Attachment{{
+				URL:         cover.URL,
+				ContentType: cover.ContentType,
+				Title:       cover.AltText,
+				Size:        cover.Size,
+			}}
+		}
+
+		if item.Published.After(res.Updated) {
+			res.Updated = item.Published
+		}
+		if item.Updated.After(res.Updated) {
+			res.Updated = item.Updated
+		}
+		res.Items[i] = item
+	}
+
+	return nil
+}
+
+// siteURL resolves a path against the public address of the blog.
+func siteURL(path string) string {
+	return strings.TrimSuffix(config.Cfg().SiteUrl, "/") + path
+}
+
+func postURL(id int64) string {
+	return siteURL(fmt.Sprintf("/posts/%d", id))
+}
+
+func accountPageURL(id int64) string {
+	return siteURL(fmt.Sprintf("/accounts/%d", id))
+}
+
+// authorNames lists the people who wrote the post, reviewers are not credited.
+func authorNames(post *model.PostResponse) []string {
+	var names []string
+	for _, author := range post.Authors {
+		if author.Role != constant.POST_AUTHOR_REVIEWER {
+			names = append(names, author.Name)
+		}
+	}
+	if len(names) == 0 {
+		return []string{post.Account.Name}
+	}
+	return names
+}
+
+// summarize shortens the body to its first characters, cutting at a word boundary.
+func summarize(body string) string {
+	text := strings.Join(strings.Fields(body), " ")
+	if utf8.RuneCountInString(text) <= constant.FEED_SUMMARY_LENGTH {
+		return text
+	}
+
+	text = string([]rune(text)[:constant.FEED_SUMMARY_LENGTH])
+	if i := strings.LastIndex(text, " "); i > 0 {
+		text = text[:i]
+	}
+	return text + "…"
+}
+
+// bodyHTML renders the plain text body as HTML paragraphs, single line breaks are kept.
+func bodyHTML(body string) string {
+	var sb strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		sb.WriteString("<p>")
+		sb.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		sb.WriteString("</p>")
+	}
+	return sb.String()
+}
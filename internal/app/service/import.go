@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/frontmatter"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/markdown"
+	"github.com/anonychun/go-blog-api/internal/security/middleware"
+	"github.com/anonychun/go-blog-api/internal/wxr"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type ImportService interface {
+	WordPress(ctx context.Context, req model.WordPressImportRequest) (*model.ImportResponse, error)
+	Markdown(ctx context.Context, req model.MarkdownImportRequest) (*model.ImportResponse, error)
+	ExportMarkdown(ctx context.Context, req model.MarkdownExportRequest) ([]*model.MarkdownFile, error)
+}
+
+func NewImportService(importRepository repository.ImportRepository) ImportService {
+	return &importService{importRepository}
+}
+
+type importService struct {
+	importRepository repository.ImportRepository
+}
+
+// WordPress imports the posts of a WXR export. Authors are matched to accounts by email, accounts which
+// do not exist yet are created with a random password. Items other than posts, and posts which cannot
+// be imported, are skipped with a warning instead of failing the whole import.
+func (s *importService) WordPress(ctx context.Context, req model.WordPressImportRequest) (*model.ImportResponse, error) {
+	if !middleware.IsAdmin(ctx) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	export, err := wxr.Parse(req.File)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to parse wordpress export")
+		if errors.Is(err, wxr.ErrFormat) {
+			return nil, constant.ErrImportFormat
+		}
+		return nil, constant.ErrServer
+	}
+
+	b := &wordPressImport{
+		export:     export,
+		authors:    make(map[string]wxr.Author, len(export.Authors)),
+		accounts:   make(map[string]*model.ImportAccount),
+		categories: make(map[string]*model.ImportCategory),
+		tags:       make(map[string]bool),
+		data:       &model.Import{},
+	}
+	for _, author := range export.Authors {
+		b.authors[author.Login] = author
+	}
+
+	skipped := 0
+	for i := range export.Items {
+		post, err := b.post(&export.Items[i])
+		if err != nil {
+			b.warnings = append(b.warnings, err.Error())
+		}
+		if post == nil {
+			skipped++
+			continue
+		}
+		b.data.Posts = append(b.data.Posts, post)
+	}
+
+	for _, account := range b.data.Accounts {
+		account.Password, err = randomPassword()
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to generate password")
+			return nil, constant.ErrServer
+		}
+	}
+
+	res, err := s.importRepository.Import(ctx, b.data, req.DryRun)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to import wordpress export")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewImportResponse(res, req.DryRun, len(b.tags), skipped, b.warnings), nil
+}
+
+// wordPressImport builds an import out of a WXR export, accounts and categories are shared by the posts
+// which refer to them.
+type wordPressImport struct {
+	export     *wxr.Export
+	authors    map[string]wxr.Author
+	accounts   map[string]*model.ImportAccount
+	categories map[string]*model.ImportCategory
+	tags       map[string]bool
+	warnings   []string
+	data       *model.Import
+}
+
+// post converts an item to a post, nil is returned for items which are skipped. Only skipping a post
+// which should have been imported returns an error, to be reported as a warning.
+func (b *wordPressImport) post(item *wxr.Item) (*model.ImportPost, error) {
+	if item.PostType != wxr.PostTypePost {
+		return nil, nil
+	}
+
+	post := &model.ImportPost{Title: strings.TrimSpace(item.Title)}
+	switch item.Status {
+	case wxr.StatusPublish:
+		post.Status = constant.POST_STATUS_PUBLISHED
+	case wxr.StatusDraft, wxr.StatusPending, wxr.StatusFuture, wxr.StatusPrivate:
+		post.Status = constant.POST_STATUS_DRAFT
+	default:
+		return nil, nil
+	}
+
+	if post.Title == "" {
+		return nil, fmt.Errorf("post %d: title is empty", item.PostID)
+	} else if len([]rune(post.Title)) > 255 {
+		post.Title = string([]rune(post.Title)[:255])
+	}
+
+	var err error
+	post.CreatedAt, err = item.Date()
+	if err != nil {
+		return nil, fmt.Errorf("post %d: %v", item.PostID, err)
+	}
+	if post.Status == constant.POST_STATUS_PUBLISHED {
+		post.PublishedAt = sql.NullTime{Time: post.CreatedAt, Valid: true}
+	}
+
+	post.Body, err = markdown.FromHTML(item.Content)
+	if err != nil {
+		return nil, fmt.Errorf("post %d: %v", item.PostID, err)
+	}
+
+	post.Account = b.account(item.Creator)
+	if post.Account == nil {
+		return nil, fmt.Errorf("post %d: author %q has no email", item.PostID, item.Creator)
+	}
+
+	if categories := item.Categories(); len(categories) > 0 {
+		post.Category = b.category(categories[0].Nicename, categories[0].Name, 0)
+		if len(categories) > 1 {
+			b.warnings = append(b.warnings, fmt.Sprintf("post %d: only the category %q is kept", item.PostID, categories[0].Name))
+		}
+	}
+
+	post.Tags = normalizeTags(item.Tags())
+	for _, tag := range post.Tags {
+		b.tags[tag] = true
+	}
+	return post, nil
+}
+
+func (b *wordPressImport) account(login string) *model.ImportAccount {
+	author, found := b.authors[login]
+	if !found || author.Email == "" {
+		return nil
+	}
+
+	key := strings.ToLower(author.Email)
+	account, found := b.accounts[key]
+	if !found {
+		name := author.DisplayName
+		if name == "" {
+			name = author.Login
+		}
+		account = &model.ImportAccount{Name: name, Email: author.Email}
+		b.accounts[key] = account
+		b.data.Accounts = append(b.data.Accounts, account)
+	}
+	return account
+}
+
+// category maps a WordPress category along with its parents, the default Uncategorized category is
+// left out since posts without a category are the same thing here.
+func (b *wordPressImport) category(nicename, name string, depth int) *model.ImportCategory {
+	if nicename == "uncategorized" || depth > len(b.export.Categories) {
+		return nil
+	} else if category, found := b.categories[nicename]; found {
+		return category
+	}
+
+	category := &model.ImportCategory{Name: name}
+	for _, c := range b.export.Categories {
+		if c.Nicename == nicename {
+			category.Name = c.Name
+			if c.Parent != "" {
+				category.Parent = b.category(c.Parent, c.Parent, depth+1)
+			}
+			break
+		}
+	}
+
+	b.categories[nicename] = category
+	b.data.Categories = append(b.data.Categories, category)
+	return category
+}
+
+// Markdown imports Markdown files with front matter. A post is identified by its slug, taken from the file
+// name when the front matter has none, so importing the same files again updates the posts they created.
+// Authors are matched to accounts by email, accounts which do not exist yet are created with a random
+// password and the part of the email before the @ as name.
+func (s *importService) Markdown(ctx context.Context, req model.MarkdownImportRequest) (*model.ImportResponse, error) {
+	if !middleware.IsAdmin(ctx) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	data := &model.Import{}
+	accounts := make(map[string]*model.ImportAccount)
+	slugs := make(map[string]string)
+	tags := make(map[string]bool)
+	var warnings []string
+	skipped := 0
+	for _, file := range req.Files {
+		post, err := markdownPost(file)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", file.Name, err))
+			skipped++
+			continue
+		} else if other, found := slugs[post.Slug]; found {
+			warnings = append(warnings, fmt.Sprintf("%s: slug %q is already used by %s", file.Name, post.Slug, other))
+			skipped++
+			continue
+		}
+		slugs[post.Slug] = file.Name
+
+		key := strings.ToLower(post.Account.Email)
+		if account, found := accounts[key]; found {
+			post.Account = account
+		} else {
+			post.Account.Password, err = randomPassword()
+			if err != nil {
+				logger.Log().Err(err).Msg("failed to generate password")
+				return nil, constant.ErrServer
+			}
+			accounts[key] = post.Account
+			data.Accounts = append(data.Accounts, post.Account)
+		}
+
+		for _, tag := range post.Tags {
+			tags[tag] = true
+		}
+		data.Posts = append(data.Posts, post)
+	}
+
+	res, err := s.importRepository.Import(ctx, data, req.DryRun)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to import markdown files")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewImportResponse(res, req.DryRun, len(tags), skipped, warnings), nil
+}
+
+// ExportMarkdown writes every post as a Markdown file with front matter in the given format, the files
+// import back into the same posts. Posts without a slug get one from their title.
+func (s *importService) ExportMarkdown(ctx context.Context, req model.MarkdownExportRequest) ([]*model.MarkdownFile, error) {
+	if !middleware.IsAdmin(ctx) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	if req.Format == "" {
+		req.Format = frontmatter.FormatYAML
+	} else if req.Format != frontmatter.FormatYAML && req.Format != frontmatter.FormatTOML {
+		return nil, constant.ErrMarkdownFormat
+	}
+
+	posts, err := s.importRepository.ListPosts(ctx)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list posts")
+		return nil, constant.ErrServer
+	}
+
+	used := make(map[string]bool, len(posts))
+	for _, post := range posts {
+		if post.Slug != "" {
+			used[post.Slug] = true
+		}
+	}
+
+	files := make([]*model.MarkdownFile, 0, len(posts))
+	for _, post := range posts {
+		slug := post.Slug
+		if slug == "" {
+			slug = slugify(post.Title)
+			if slug == "" || used[slug] {
+				slug = strings.Trim(fmt.Sprintf("%s-%d", slug, post.ID), "-")
+			}
+			used[slug] = true
+		}
+
+		doc := &frontmatter.Document{
+			Format: req.Format,
+			Meta: frontmatter.Meta{
+				Title:  post.Title,
+				Date:   post.CreatedAt.UTC(),
+				Slug:   slug,
+				Tags:   post.Tags,
+				Author: post.Account.Email,
+				Draft:  post.Status != constant.POST_STATUS_PUBLISHED,
+			},
+			Body: post.Body,
+		}
+
+		data, err := doc.Marshal()
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to marshal markdown file")
+			return nil, constant.ErrServer
+		}
+		files = append(files, &model.MarkdownFile{Name: slug + ".md", Data: data})
+	}
+	return files, nil
+}
+
+// markdownPost converts a Markdown file to a post, the returned account still has to be matched.
+func markdownPost(file *model.MarkdownFile) (*model.ImportPost, error) {
+	doc, err := frontmatter.Parse(file.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	post := &model.ImportPost{
+		Slug:      slugify(doc.Meta.Slug),
+		Title:     strings.TrimSpace(doc.Meta.Title),
+		Body:      doc.Body,
+		Status:    constant.POST_STATUS_PUBLISHED,
+		Tags:      normalizeTags(doc.Meta.Tags),
+		CreatedAt: doc.Meta.Date.UTC(),
+		Account:   &model.ImportAccount{Email: strings.TrimSpace(doc.Meta.Author)},
+	}
+	// slugs name the exported files, so they are normalized the same way as the ones made from titles
+	if post.Slug == "" {
+		post.Slug = slugify(strings.TrimSuffix(path.Base(file.Name), path.Ext(file.Name)))
+	}
+
+	switch {
+	case post.Title == "":
+		return nil, errors.New("title is missing")
+	case post.Slug == "":
+		return nil, errors.New("slug has no letters or digits")
+	case len([]rune(post.Title)) > 255:
+		return nil, errors.New("title is longer than 255 characters")
+	case len(post.Slug) > 255:
+		return nil, errors.New("slug is longer than 255 characters")
+	case doc.Meta.Date.IsZero():
+		return nil, errors.New("date is missing")
+	case !strings.Contains(post.Account.Email, "@"):
+		return nil, errors.New("author is not an email")
+	}
+	post.Account.Name = post.Account.Email[:strings.Index(post.Account.Email, "@")]
+
+	if doc.Meta.Draft {
+		post.Status = constant.POST_STATUS_DRAFT
+	} else {
+		post.PublishedAt = sql.NullTime{Time: post.CreatedAt, Valid: true}
+	}
+	return post, nil
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify turns a title into a slug made of lowercase letters, digits and dashes.
+func slugify(title string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
+
+// randomPassword hashes a password nobody knows, imported authors have to be given a new one to sign in.
+func randomPassword() (string, error) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+
+	password, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(secret)), bcrypt.DefaultCost)
+	return string(password), err
+}
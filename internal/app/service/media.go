@@ -0,0 +1,352 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/imaging"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/security/middleware"
+	"github.com/anonychun/go-blog-api/internal/storage"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type MediaService interface {
+	Create(ctx context.Context, req model.MediaCreateRequest) (*model.MediaResponse, error)
+	List(ctx context.Context, req model.MediaListRequest) ([]*model.MediaResponse, error)
+	Get(ctx context.Context, req model.MediaGetRequest) (*model.MediaResponse, error)
+	Update(ctx context.Context, req model.MediaUpdateRequest) (*model.MediaResponse, error)
+	Delete(ctx context.Context, req model.MediaDeleteRequest) error
+	CollectGarbage(ctx context.Context) error
+}
+
+// mediaGarbageBatch is the number of unreferenced media removed per query by the garbage collector.
+const mediaGarbageBatch = 100
+
+func NewMediaService(mediaRepository repository.MediaRepository, mediaStorage storage.Storage) MediaService {
+	return &mediaService{mediaRepository, mediaStorage}
+}
+
+type mediaService struct {
+	mediaRepository repository.MediaRepository
+	mediaStorage    storage.Storage
+}
+
+func (s *mediaService) Create(ctx context.Context, req model.MediaCreateRequest) (*model.MediaResponse, error) {
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	if !valid {
+		return nil, constant.ErrUnauthorized
+	}
+
+	if req.Size > config.Cfg().MediaMaxSize {
+		return nil, constant.ErrMediaTooLarge
+	}
+
+	// the content type is sniffed from the file itself, the one sent by the client is not trusted
+	head := make([]byte, 512)
+	n, err := io.ReadFull(req.File, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		logger.Log().Err(err).Msg("failed to read media")
+		return nil, constant.ErrServer
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	extension, found := constant.MEDIA_TYPES[contentType]
+	if !found {
+		return nil, constant.ErrMediaType
+	}
+
+	key, err := newMediaKey(claimsID, extension)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to generate media key")
+		return nil, constant.ErrServer
+	}
+
+	media := &model.Media{
+		Key:         key,
+		Filename:    mediaFilename(req.Filename),
+		ContentType: contentType,
+		Size:        req.Size,
+		AltText:     req.AltText,
+		Caption:     req.Caption,
+		CreatedAt:   time.Now(),
+		AccountID:   claimsID,
+	}
+
+	file := io.MultiReader(bytes.NewReader(head), req.File)
+	if isProcessableImage(contentType) {
+		err = s.createImage(ctx, media, file)
+	} else {
+		err = s.mediaStorage.Put(ctx, media.Key, file, media.Size, media.ContentType)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to store media")
+			err = constant.ErrServer
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.mediaRepository.Create(ctx, media)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to create media")
+		s.deleteObjects(ctx, media)
+		return nil, constant.ErrServer
+	}
+
+	s.setURLs(media)
+	return model.NewMediaResponse(media), nil
+}
+
+// createImage runs an uploaded image through the processing pipeline and stores the cleaned original
+// together with its resized and thumbnail variants.
+func (s *mediaService) createImage(ctx context.Context, media *model.Media, file io.Reader) error {
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to read media")
+		return constant.ErrServer
+	}
+
+	img, err := imaging.Process(data, imaging.Options{
+		Widths:        config.Cfg().MediaImageWidths,
+		ThumbnailSize: config.Cfg().MediaThumbnailSize,
+		JpegQuality:   config.Cfg().MediaJpegQuality,
+	})
+	if err != nil {
+		switch err {
+		case imaging.ErrDecode, imaging.ErrTooLarge:
+			return constant.ErrMediaImage
+		default:
+			logger.Log().Err(err).Msg("failed to process image")
+			return constant.ErrServer
+		}
+	}
+
+	media.Size = int64(len(img.Data))
+	media.Width = sql.NullInt64{Int64: int64(img.Width), Valid: true}
+	media.Height = sql.NullInt64{Int64: int64(img.Height), Valid: true}
+
+	base := strings.TrimSuffix(media.Key, path.Ext(media.Key))
+	for _, variant := range img.Variants {
+		media.Variants = append(media.Variants, model.MediaVariant{
+			Name:        variant.Name,
+			Key:         base + "_" + variant.Name + constant.MEDIA_TYPES[variant.ContentType],
+			ContentType: variant.ContentType,
+			Size:        int64(len(variant.Data)),
+			Width:       int64(variant.Width),
+			Height:      int64(variant.Height),
+		})
+	}
+
+	err = s.mediaStorage.Put(ctx, media.Key, bytes.NewReader(img.Data), media.Size, media.ContentType)
+	for i := 0; err == nil && i < len(img.Variants); i++ {
+		variant := &media.Variants[i]
+		err = s.mediaStorage.Put(ctx, variant.Key, bytes.NewReader(img.Variants[i].Data), variant.Size, variant.ContentType)
+	}
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to store media")
+		s.deleteObjects(ctx, media)
+		return constant.ErrServer
+	}
+
+	return nil
+}
+
+func (s *mediaService) Get(ctx context.Context, req model.MediaGetRequest) (*model.MediaResponse, error) {
+	media, err := s.mediaRepository.Get(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get media")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrMediaNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	// usages name unpublished posts, they are only shown to the owner of the media
+	if !middleware.IsMe(ctx, media.AccountID) && !middleware.IsAdmin(ctx) {
+		media.Usages = nil
+	}
+
+	s.setURLs(media)
+	return model.NewMediaResponse(media), nil
+}
+
+func (s *mediaService) List(ctx context.Context, req model.MediaListRequest) ([]*model.MediaResponse, error) {
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	if !valid {
+		return nil, constant.ErrUnauthorized
+	}
+
+	mediaList, err := s.mediaRepository.List(ctx, claimsID, req.Limit, req.Offset, req.Query)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list media")
+		return nil, constant.ErrServer
+	}
+
+	for _, media := range mediaList {
+		s.setURLs(media)
+	}
+	return model.NewMediaListResponse(mediaList), nil
+}
+
+func (s *mediaService) Update(ctx context.Context, req model.MediaUpdateRequest) (*model.MediaResponse, error) {
+	media, err := s.mediaRepository.Get(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get media")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrMediaNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	if !middleware.IsMe(ctx, media.AccountID) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	media.AltText = req.AltText
+	media.Caption = req.Caption
+	media.UpdatedAt.Time = time.Now()
+
+	err = s.mediaRepository.Update(ctx, media)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to update media")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrMediaNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	s.setURLs(media)
+	return model.NewMediaResponse(media), nil
+}
+
+func (s *mediaService) Delete(ctx context.Context, req model.MediaDeleteRequest) error {
+	media, err := s.mediaRepository.Get(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get media")
+		switch err {
+		case pgx.ErrNoRows:
+			return constant.ErrMediaNotFound
+		default:
+			return constant.ErrServer
+		}
+	}
+
+	if !middleware.IsMe(ctx, media.AccountID) {
+		return constant.ErrUnauthorized
+	}
+
+	// deleting media that posts still show would leave them with broken images unless explicitly forced
+	if len(media.Usages) > 0 && !req.Force {
+		return constant.ErrMediaInUse
+	}
+
+	err = s.mediaRepository.Delete(ctx, media.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to delete media")
+		return constant.ErrServer
+	}
+
+	s.deleteObjects(ctx, media)
+	return nil
+}
+
+// CollectGarbage deletes media that no post references and that are older than the retention period,
+// the retention leaves authors time to use a fresh upload in a post.
+func (s *mediaService) CollectGarbage(ctx context.Context) error {
+	createdBefore := time.Now().Add(-config.Cfg().MediaRetention)
+	for {
+		mediaList, err := s.mediaRepository.ListUnreferenced(ctx, createdBefore, mediaGarbageBatch)
+		if err != nil {
+			return err
+		}
+
+		for _, media := range mediaList {
+			deleted, err := s.mediaRepository.DeleteUnreferenced(ctx, media.ID)
+			if err != nil {
+				return err
+			} else if deleted {
+				s.deleteObjects(ctx, media)
+			}
+		}
+
+		if len(mediaList) < mediaGarbageBatch {
+			return nil
+		}
+	}
+}
+
+func (s *mediaService) setURLs(media *model.Media) {
+	media.URL = s.mediaStorage.URL(media.Key)
+	for i := range media.Variants {
+		media.Variants[i].URL = s.mediaStorage.URL(media.Variants[i].Key)
+	}
+}
+
+// deleteObjects removes the stored files of a media on a best effort basis, a leftover file is harmless.
+func (s *mediaService) deleteObjects(ctx context.Context, media *model.Media) {
+	keys := []string{media.Key}
+	for _, variant := range media.Variants {
+		keys = append(keys, variant.Key)
+	}
+
+	for _, key := range keys {
+		err := s.mediaStorage.Delete(ctx, key)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to delete stored media")
+		}
+	}
+}
+
+// isProcessableImage reports whether the image pipeline can decode the content type.
+func isProcessableImage(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	default:
+		return false
+	}
+}
+
+// mediaFilename keeps the base name of an uploaded file for display, client paths are dropped.
+func mediaFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if filename == "." || filename == "/" {
+		return ""
+	}
+	if len(filename) > 255 {
+		filename = filename[:255]
+	}
+	return strings.ToValidUTF8(filename, "")
+}
+
+// newMediaKey generates an unguessable storage key grouped by the uploading account.
+func newMediaKey(accountID int64, extension string) (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d/%s%s", accountID, hex.EncodeToString(b), extension), nil
+}
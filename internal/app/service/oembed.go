@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/storage"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type OEmbedService interface {
+	Get(ctx context.Context, req model.OEmbedRequest) (*model.OEmbedResponse, error)
+}
+
+func NewOEmbedService(
+	postRepository repository.PostRepository,
+	mediaRepository repository.MediaRepository,
+	mediaStorage storage.Storage,
+) OEmbedService {
+	return &oembedService{postRepository, mediaRepository, mediaStorage}
+}
+
+type oembedService struct {
+	postRepository  repository.PostRepository
+	mediaRepository repository.MediaRepository
+	mediaStorage    storage.Storage
+}
+
+// Get returns the rich embed of a published post, a card linking to the post with its summary.
+func (s *oembedService) Get(ctx context.Context, req model.OEmbedRequest) (*model.OEmbedResponse, error) {
+	if req.Format != "" && req.Format != constant.OEMBED_FORMAT_JSON {
+		return nil, constant.ErrOEmbedFormat
+	}
+
+	postID, valid := parsePostURL(req.URL)
+	if !valid {
+		return nil, constant.ErrOEmbedURL
+	}
+
+	post, err := s.postRepository.Get(ctx, postID)
+	if err == pgx.ErrNoRows {
+		return nil, constant.ErrOEmbedURL
+	} else if err != nil {
+		logger.Log().Err(err).Msg("failed to get post")
+		return nil, constant.ErrServer
+	} else if post.Status != constant.POST_STATUS_PUBLISHED {
+		return nil, constant.ErrOEmbedURL
+	}
+
+	res := model.NewPostResponse(post)
+	cover, err := getCover(ctx, s.mediaRepository, s.mediaStorage, res)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get media")
+		return nil, constant.ErrServer
+	}
+
+	authors := strings.Join(authorNames(res), ", ")
+	width := oembedSize(constant.OEMBED_WIDTH, req.MaxWidth)
+	embed := &model.OEmbedResponse{
+		Type:         "rich",
+		Version:      "1.0",
+		Title:        res.Title,
+		AuthorName:   authors,
+		AuthorURL:    accountPageURL(res.AccountID),
+		ProviderName: config.Cfg().SiteTitle,
+		ProviderURL:  siteURL(""),
+		Width:        width,
+		Height:       oembedSize(constant.OEMBED_HEIGHT, req.MaxHeight),
+		HTML: fmt.Sprintf(
+			`<blockquote class="post-embed" cite="%[1]s" style="max-width:%[2]dpx"><p><a href="%[1]s">%[3]s</a></p><p>%[4]s</p><footer>%[5]s, <a href="%[6]s">%[7]s</a></footer></blockquote>`,
+			html.EscapeString(postURL(res.ID)),
+			width,
+			html.EscapeString(res.Title),
+			html.EscapeString(summarize(res.Body)),
+			html.EscapeString(authors),
+			html.EscapeString(siteURL("")),
+			html.EscapeString(config.Cfg().SiteTitle),
+		),
+	}
+
+	// consumers expect the thumbnail URL together with its dimensions
+	if cover != nil && cover.Width.Valid && cover.Height.Valid {
+		embed.ThumbnailURL = cover.URL
+		embed.ThumbnailWidth = int(cover.Width.Int64)
+		embed.ThumbnailHeight = int(cover.Height.Int64)
+	}
+
+	return embed, nil
+}
+
+// oembedSize fits the default size of an embed within the maximum asked by the consumer.
+func oembedSize(size, max int) int {
+	if max > 0 && max < size {
+		return max
+	}
+	return size
+}
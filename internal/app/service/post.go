@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/anonychun/go-blog-api/internal/app/model"
@@ -9,23 +11,77 @@ import (
 	"github.com/anonychun/go-blog-api/internal/constant"
 	"github.com/anonychun/go-blog-api/internal/logger"
 	"github.com/anonychun/go-blog-api/internal/security/middleware"
+	"github.com/anonychun/go-blog-api/internal/storage"
 	pgx "github.com/jackc/pgx/v4"
 )
 
 type PostService interface {
 	Create(ctx context.Context, req model.PostCreateRequest) (*model.PostResponse, error)
 	List(ctx context.Context, req model.PostListRequest) ([]*model.PostResponse, error)
+	ListTrending(ctx context.Context, req model.PostTrendingListRequest) ([]*model.PostResponse, error)
+	Search(ctx context.Context, req model.PostSearchRequest) ([]*model.PostSearchResponse, error)
 	Get(ctx context.Context, req model.PostGetRequest) (*model.PostResponse, error)
 	Update(ctx context.Context, req model.PostUpdateRequest) (*model.PostResponse, error)
 	Delete(ctx context.Context, req model.PostDeleteRequest) error
+	ListStats(ctx context.Context, req model.PostStatListRequest) ([]*model.PostStatResponse, error)
+	Transition(ctx context.Context, req model.PostReviewRequest) (*model.PostResponse, error)
+	ListReviews(ctx context.Context, req model.PostReviewListRequest) ([]*model.PostReviewResponse, error)
+	FlushViews(ctx context.Context) error
+	RecomputeTrending(ctx context.Context) error
 }
 
-func NewPostService(postRepository repository.PostRepository) PostService {
-	return &postService{postRepository}
+var trendingWindows = map[string]time.Duration{
+	constant.TRENDING_WINDOW_DAY:   24 * time.Hour,
+	constant.TRENDING_WINDOW_WEEK:  7 * 24 * time.Hour,
+	constant.TRENDING_WINDOW_MONTH: 30 * 24 * time.Hour,
+}
+
+func NewPostService(
+	postRepository repository.PostRepository,
+	categoryRepository repository.CategoryRepository,
+	seriesRepository repository.SeriesRepository,
+	reactionRepository repository.ReactionRepository,
+	viewRepository repository.ViewRepository,
+	trendingRepository repository.TrendingRepository,
+	mediaRepository repository.MediaRepository,
+	postAuthorRepository repository.PostAuthorRepository,
+	postReviewRepository repository.PostReviewRepository,
+	accountRepository repository.AccountRepository,
+	activityPubRepository repository.ActivityPubRepository,
+	webmentionRepository repository.WebmentionRepository,
+	mediaStorage storage.Storage,
+) PostService {
+	return &postService{
+		postRepository,
+		categoryRepository,
+		seriesRepository,
+		reactionRepository,
+		viewRepository,
+		trendingRepository,
+		mediaRepository,
+		postAuthorRepository,
+		postReviewRepository,
+		accountRepository,
+		activityPubRepository,
+		webmentionRepository,
+		mediaStorage,
+	}
 }
 
 type postService struct {
-	postRepository repository.PostRepository
+	postRepository        repository.PostRepository
+	categoryRepository    repository.CategoryRepository
+	seriesRepository      repository.SeriesRepository
+	reactionRepository    repository.ReactionRepository
+	viewRepository        repository.ViewRepository
+	trendingRepository    repository.TrendingRepository
+	mediaRepository       repository.MediaRepository
+	postAuthorRepository  repository.PostAuthorRepository
+	postReviewRepository  repository.PostReviewRepository
+	accountRepository     repository.AccountRepository
+	activityPubRepository repository.ActivityPubRepository
+	webmentionRepository  repository.WebmentionRepository
+	mediaStorage          storage.Storage
 }
 
 func (s *postService) Create(ctx context.Context, req model.PostCreateRequest) (*model.PostResponse, error) {
@@ -34,14 +90,28 @@ func (s *postService) Create(ctx context.Context, req model.PostCreateRequest) (
 		return nil, constant.ErrUnauthorized
 	}
 
+	categoryID, err := s.getCategoryID(ctx, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	coverMediaID, err := s.getCoverMediaID(ctx, req.CoverMediaID, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	post := &model.Post{
-		Title:     req.Title,
-		Body:      req.Body,
-		CreatedAt: time.Now(),
-		AccountID: claimsID,
+		Title:        req.Title,
+		Body:         req.Body,
+		CreatedAt:    time.Now(),
+		AccountID:    claimsID,
+		Status:       constant.POST_STATUS_DRAFT,
+		Tags:         normalizeTags(req.Tags),
+		CategoryID:   categoryID,
+		CoverMediaID: coverMediaID,
 	}
 
-	err := s.postRepository.Create(ctx, post)
+	err = s.postRepository.Create(ctx, post)
 	if err != nil {
 		logger.Log().Err(err).Msg("failed to create post")
 		return nil, constant.ErrServer
@@ -50,14 +120,116 @@ func (s *postService) Create(ctx context.Context, req model.PostCreateRequest) (
 	return model.NewPostResponse(post), nil
 }
 
+// List lists published posts, other statuses list the posts the authenticated account works on or
+// every post for admins. Posts can be narrowed down to those written by an account.
 func (s *postService) List(ctx context.Context, req model.PostListRequest) ([]*model.PostResponse, error) {
-	posts, err := s.postRepository.List(ctx, req.Limit, req.Offset, req.Title)
+	authorID := req.AccountID
+	if req.Status != constant.POST_STATUS_PUBLISHED {
+		if !isPostStatus(req.Status) {
+			return nil, constant.ErrPostStatus
+		}
+
+		claimsID, valid := middleware.GetClaimsID(ctx)
+		if !valid {
+			return nil, constant.ErrUnauthorized
+		} else if !middleware.IsAdmin(ctx) {
+			if authorID != 0 && authorID != claimsID {
+				return nil, constant.ErrUnauthorized
+			}
+			authorID = claimsID
+		}
+	}
+
+	posts, err := s.postRepository.List(ctx, req.Limit, req.Offset, req.Title,
+		normalizeTags(req.Tags),
+		req.TagMatch == constant.TAG_MATCH_ALL,
+		req.Status,
+		authorID)
 	if err != nil {
 		logger.Log().Err(err).Msg("failed to list posts")
 		return nil, constant.ErrServer
 	}
 
-	return model.NewPostListResponse(posts), nil
+	for _, post := range posts {
+		post.Reactions, err = listReactions(ctx, s.reactionRepository, post.ID)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to list reactions")
+			return nil, constant.ErrServer
+		}
+	}
+
+	res := model.NewPostListResponse(posts)
+	err = s.setMeta(ctx, res...)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *postService) ListTrending(ctx context.Context, req model.PostTrendingListRequest) ([]*model.PostResponse, error) {
+	window, found := trendingWindows[req.Window]
+	if !found {
+		return nil, constant.ErrTrendingWindow
+	}
+
+	exists, err := s.trendingRepository.Exists(ctx, req.Window)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to check trending posts")
+		return nil, constant.ErrServer
+	} else if !exists {
+		err = s.trendingRepository.Recompute(ctx, req.Window, time.Now().Add(-window))
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to recompute trending posts")
+			return nil, constant.ErrServer
+		}
+	}
+
+	ids, err := s.trendingRepository.List(ctx, req.Window, req.Limit, req.Offset)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list trending posts")
+		return nil, constant.ErrServer
+	}
+
+	posts := make([]*model.Post, 0, len(ids))
+	for _, id := range ids {
+		post, err := s.postRepository.Get(ctx, id)
+		if err == pgx.ErrNoRows {
+			// deleted since the ranking was computed
+			continue
+		} else if err != nil {
+			logger.Log().Err(err).Msg("failed to get post")
+			return nil, constant.ErrServer
+		} else if !isPostVisible(ctx, post) {
+			// unpublished since the ranking was computed
+			continue
+		}
+
+		post.Reactions, err = listReactions(ctx, s.reactionRepository, post.ID)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to list reactions")
+			return nil, constant.ErrServer
+		}
+		posts = append(posts, post)
+	}
+
+	res := model.NewPostListResponse(posts)
+	err = s.setMeta(ctx, res...)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *postService) Search(ctx context.Context, req model.PostSearchRequest) ([]*model.PostSearchResponse, error) {
+	results, err := s.postRepository.Search(ctx, req.Query, req.Limit, req.Offset)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to search posts")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewPostSearchListResponse(results), nil
 }
 
 func (s *postService) Get(ctx context.Context, req model.PostGetRequest) (*model.PostResponse, error) {
@@ -72,7 +244,38 @@ func (s *postService) Get(ctx context.Context, req model.PostGetRequest) (*model
 		}
 	}
 
-	return model.NewPostResponse(post), nil
+	if !isPostVisible(ctx, post) {
+		return nil, constant.ErrPostNotFound
+	}
+
+	post.Series, err = s.seriesRepository.GetByPostID(ctx, post.ID)
+	if err != nil && err != pgx.ErrNoRows {
+		logger.Log().Err(err).Msg("failed to get series by post id")
+		return nil, constant.ErrServer
+	} else if post.Series != nil {
+		hideUnpublishedParts(ctx, post.Series)
+	}
+
+	post.Reactions, err = listReactions(ctx, s.reactionRepository, post.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list reactions")
+		return nil, constant.ErrServer
+	}
+
+	if post.Status == constant.POST_STATUS_PUBLISHED && !isBot(req.UserAgent) {
+		err = s.viewRepository.Record(ctx, post.ID, visitorID(ctx, req.RemoteAddr, req.UserAgent), time.Now())
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to record post view")
+		}
+	}
+
+	res := model.NewPostResponse(post)
+	err = s.setMeta(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
 }
 
 func (s *postService) Update(ctx context.Context, req model.PostUpdateRequest) (*model.PostResponse, error) {
@@ -87,20 +290,44 @@ func (s *postService) Update(ctx context.Context, req model.PostUpdateRequest) (
 		}
 	}
 
-	if !middleware.IsMe(ctx, post.AccountID) {
+	if !canEditPost(ctx, post) {
 		return nil, constant.ErrUnauthorized
+	} else if req.Version != 0 && req.Version != post.Version {
+		return nil, constant.ErrPrecondition
+	} else if post.Status == constant.POST_STATUS_IN_REVIEW {
+		return nil, constant.ErrPostInReview
+	} else if post.Status == constant.POST_STATUS_PUBLISHED {
+		return nil, constant.ErrPostPublished
+	}
+
+	categoryID, err := s.getCategoryID(ctx, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	coverMediaID, err := s.getCoverMediaID(ctx, req.CoverMediaID, post)
+	if err != nil {
+		return nil, err
 	}
 
 	post.Title = req.Title
 	post.Body = req.Body
+	post.Tags = normalizeTags(req.Tags)
+	post.CategoryID = categoryID
+	post.CoverMediaID = coverMediaID
 	post.UpdatedAt.Time = time.Now()
 
+	// an approval only covers the reviewed content
+	if post.Status == constant.POST_STATUS_APPROVED {
+		post.Status = constant.POST_STATUS_DRAFT
+	}
+
 	err = s.postRepository.Update(ctx, post)
 	if err != nil {
 		logger.Log().Err(err).Msg("failed to update post")
 		switch err {
 		case pgx.ErrNoRows:
-			return nil, constant.ErrPostNotFound
+			return nil, constant.ErrPrecondition
 		default:
 			return nil, constant.ErrServer
 		}
@@ -121,15 +348,144 @@ func (s *postService) Delete(ctx context.Context, req model.PostDeleteRequest) e
 		}
 	}
 
-	if !middleware.IsMe(ctx, post.AccountID) {
+	if !isPostOwner(ctx, post) {
 		return constant.ErrUnauthorized
+	} else if req.Version != 0 && req.Version != post.Version {
+		return constant.ErrPrecondition
 	}
 
-	err = s.postRepository.Delete(ctx, req.ID)
+	err = s.postRepository.Delete(ctx, req.ID, post.Version)
 	if err != nil {
 		logger.Log().Err(err).Msg("failed to delete post")
-		return constant.ErrServer
+		switch err {
+		case pgx.ErrNoRows:
+			return constant.ErrPrecondition
+		default:
+			return constant.ErrServer
+		}
+	}
+
+	if post.Status == constant.POST_STATUS_PUBLISHED {
+		err = enqueueActivity(ctx, s.activityPubRepository, constant.ACTIVITY_DELETE, model.NewPostResponse(post))
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to enqueue activity")
+		}
+	}
+
+	return nil
+}
+
+func (s *postService) ListStats(ctx context.Context, req model.PostStatListRequest) ([]*model.PostStatResponse, error) {
+	post, err := s.postRepository.Get(ctx, req.PostID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get post")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrPostNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	if !canEditPost(ctx, post) {
+		return nil, constant.ErrUnauthorized
 	}
 
+	stats, err := s.viewRepository.ListStats(ctx, post.ID, req.From, req.To)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list post stats")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewPostStatListResponse(stats), nil
+}
+
+func (s *postService) FlushViews(ctx context.Context) error {
+	return s.viewRepository.Flush(ctx)
+}
+
+func (s *postService) RecomputeTrending(ctx context.Context) error {
+	for name, window := range trendingWindows {
+		err := s.trendingRepository.Recompute(ctx, name, time.Now().Add(-window))
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+func (s *postService) getPost(ctx context.Context, id int64) (*model.Post, error) {
+	post, err := s.postRepository.Get(ctx, id)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get post")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrPostNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+	return post, nil
+}
+
+func isPostStatus(status string) bool {
+	for _, s := range constant.POST_STATUSES {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// getCategoryID verifies that the requested category exists before it is assigned to a post.
+func (s *postService) getCategoryID(ctx context.Context, id *int64) (sql.NullInt64, error) {
+	if id == nil {
+		return sql.NullInt64{}, nil
+	}
+
+	_, err := s.categoryRepository.Get(ctx, *id)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get category")
+		switch err {
+		case pgx.ErrNoRows:
+			return sql.NullInt64{}, constant.ErrCategoryNotFound
+		default:
+			return sql.NullInt64{}, constant.ErrServer
+		}
+	}
+
+	return sql.NullInt64{Int64: *id, Valid: true}, nil
+}
+
+// getCoverMediaID verifies that the requested cover is an image uploaded by the editing account, or by
+// another editor when an existing post is updated.
+func (s *postService) getCoverMediaID(ctx context.Context, id *int64, post *model.Post) (sql.NullInt64, error) {
+	if id == nil {
+		return sql.NullInt64{}, nil
+	} else if post != nil && post.CoverMediaID.Valid && post.CoverMediaID.Int64 == *id {
+		return post.CoverMediaID, nil
+	}
+
+	media, err := s.mediaRepository.Get(ctx, *id)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get media")
+		switch err {
+		case pgx.ErrNoRows:
+			return sql.NullInt64{}, constant.ErrMediaNotFound
+		default:
+			return sql.NullInt64{}, constant.ErrServer
+		}
+	}
+
+	uploadedByEditor := middleware.IsMe(ctx, media.AccountID)
+	if post != nil {
+		role := postRole(post, media.AccountID)
+		uploadedByEditor = uploadedByEditor || role == constant.POST_AUTHOR_OWNER || role == constant.POST_AUTHOR_CO_AUTHOR
+	}
+
+	if !uploadedByEditor || !strings.HasPrefix(media.ContentType, "image/") {
+		return sql.NullInt64{}, constant.ErrMediaNotFound
+	}
+
+	return sql.NullInt64{Int64: *id, Valid: true}, nil
+}
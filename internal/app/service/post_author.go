@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/security/middleware"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type PostAuthorService interface {
+	List(ctx context.Context, req model.PostAuthorListRequest) ([]*model.PostAuthorResponse, error)
+	Update(ctx context.Context, req model.PostAuthorUpdateRequest) ([]*model.PostAuthorResponse, error)
+	Delete(ctx context.Context, req model.PostAuthorDeleteRequest) error
+}
+
+func NewPostAuthorService(
+	postAuthorRepository repository.PostAuthorRepository,
+	postRepository repository.PostRepository,
+	accountRepository repository.AccountRepository,
+) PostAuthorService {
+	return &postAuthorService{postAuthorRepository, postRepository, accountRepository}
+}
+
+type postAuthorService struct {
+	postAuthorRepository repository.PostAuthorRepository
+	postRepository       repository.PostRepository
+	accountRepository    repository.AccountRepository
+}
+
+func (s *postAuthorService) List(ctx context.Context, req model.PostAuthorListRequest) ([]*model.PostAuthorResponse, error) {
+	post, err := s.getPost(ctx, req.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isPostVisible(ctx, post) {
+		return nil, constant.ErrPostNotFound
+	}
+
+	return model.NewPostAuthorListResponse(post.Authors), nil
+}
+
+// Update invites an account to collaborate on a post or changes its role, only owners manage the authors.
+func (s *postAuthorService) Update(ctx context.Context, req model.PostAuthorUpdateRequest) ([]*model.PostAuthorResponse, error) {
+	post, err := s.getPost(ctx, req.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isPostOwner(ctx, post) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	_, err = s.accountRepository.Get(ctx, req.AccountID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get account")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrAccountNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	if req.Role != constant.POST_AUTHOR_OWNER && isLastOwner(post, req.AccountID) {
+		return nil, constant.ErrPostLastOwner
+	}
+
+	err = s.postAuthorRepository.Set(ctx, post.ID, req.AccountID, req.Role, time.Now())
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to set post author")
+		return nil, constant.ErrServer
+	}
+
+	post, err = s.getPost(ctx, req.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewPostAuthorListResponse(post.Authors), nil
+}
+
+// Delete removes an author from a post, owners may remove anyone and other authors may leave on their own.
+func (s *postAuthorService) Delete(ctx context.Context, req model.PostAuthorDeleteRequest) error {
+	post, err := s.getPost(ctx, req.PostID)
+	if err != nil {
+		return err
+	}
+
+	if !isPostOwner(ctx, post) && !middleware.IsMe(ctx, req.AccountID) {
+		return constant.ErrUnauthorized
+	}
+
+	if postRole(post, req.AccountID) == "" {
+		return constant.ErrPostAuthorNotFound
+	} else if isLastOwner(post, req.AccountID) {
+		return constant.ErrPostLastOwner
+	}
+
+	err = s.postAuthorRepository.Delete(ctx, post.ID, req.AccountID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to delete post author")
+		return constant.ErrServer
+	}
+
+	return nil
+}
+
+func (s *postAuthorService) getPost(ctx context.Context, id int64) (*model.Post, error) {
+	post, err := s.postRepository.Get(ctx, id)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get post")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrPostNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+	return post, nil
+}
+
+// postRole returns the role an account has on a post, empty when it is not one of its authors.
+func postRole(post *model.Post, accountID int64) string {
+	for _, author := range post.Authors {
+		if author.AccountID == accountID {
+			return author.Role
+		}
+	}
+	return ""
+}
+
+// isPostOwner reports whether the authenticated account owns the post.
+func isPostOwner(ctx context.Context, post *model.Post) bool {
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	return valid && postRole(post, claimsID) == constant.POST_AUTHOR_OWNER
+}
+
+// canEditPost reports whether the authenticated account may change the content of the post,
+// reviewers can only read it.
+func canEditPost(ctx context.Context, post *model.Post) bool {
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	if !valid {
+		return false
+	}
+
+	role := postRole(post, claimsID)
+	return role == constant.POST_AUTHOR_OWNER || role == constant.POST_AUTHOR_CO_AUTHOR
+}
+
+// isLastOwner reports whether the account is the only owner left, a post can never be left without one.
+func isLastOwner(post *model.Post, accountID int64) bool {
+	if postRole(post, accountID) != constant.POST_AUTHOR_OWNER {
+		return false
+	}
+
+	owners := 0
+	for _, author := range post.Authors {
+		if author.Role == constant.POST_AUTHOR_OWNER {
+			owners++
+		}
+	}
+	return owners == 1
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/storage"
+)
+
+// setMeta computes the link preview metadata of the posts, the cover image is the preview image.
+func (s *postService) setMeta(ctx context.Context, posts ...*model.PostResponse) error {
+	covers, err := getCovers(ctx, s.mediaRepository, s.mediaStorage, posts...)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list media")
+		return constant.ErrServer
+	}
+
+	for _, post := range posts {
+		post.Meta = newPostMeta(post, coverOf(covers, post))
+	}
+	return nil
+}
+
+func newPostMeta(post *model.PostResponse, cover *model.Media) *model.PostMetaResponse {
+	meta := &model.PostMetaResponse{
+		Title:         post.Title,
+		Description:   summarize(post.Body),
+		URL:           postURL(post.ID),
+		Type:          "article",
+		SiteName:      config.Cfg().SiteTitle,
+		Authors:       authorNames(post),
+		Tags:          post.Tags,
+		PublishedTime: post.PublishedAt,
+		ModifiedTime:  post.UpdatedAt,
+		TwitterCard:   constant.TWITTER_CARD_SUMMARY,
+		OEmbedURL:     oembedURL(post.ID),
+	}
+	if cover != nil {
+		meta.Image = cover.URL
+		meta.ImageAlt = cover.AltText
+		meta.ImageWidth = cover.Width.Int64
+		meta.ImageHeight = cover.Height.Int64
+		meta.TwitterCard = constant.TWITTER_CARD_SUMMARY_IMAGE
+	}
+	return meta
+}
+
+// getCover returns the cover image of a post with its public URL, nil when the post has none.
+func getCover(ctx context.Context, mediaRepository repository.MediaRepository, mediaStorage storage.Storage, post *model.PostResponse) (*model.Media, error) {
+	covers, err := getCovers(ctx, mediaRepository, mediaStorage, post)
+	if err != nil {
+		return nil, err
+	}
+	return coverOf(covers, post), nil
+}
+
+// getCovers loads the cover images of the posts with their public URL in one query, by media id.
+func getCovers(ctx context.Context, mediaRepository repository.MediaRepository, mediaStorage storage.Storage, posts ...*model.PostResponse) (map[int64]*model.Media, error) {
+	var ids []int64
+	for _, post := range posts {
+		if post.CoverMediaID != nil {
+			ids = append(ids, *post.CoverMediaID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	mediaList, err := mediaRepository.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	covers := make(map[int64]*model.Media, len(mediaList))
+	for _, media := range mediaList {
+		media.URL = mediaStorage.URL(media.Key)
+		covers[media.ID] = media
+	}
+	return covers, nil
+}
+
+// coverOf picks the cover of a post out of the loaded covers, nil when it has none or it was deleted.
+func coverOf(covers map[int64]*model.Media, post *model.PostResponse) *model.Media {
+	if post.CoverMediaID == nil {
+		return nil
+	}
+	return covers[*post.CoverMediaID]
+}
+
+func oembedURL(postID int64) string {
+	return siteURL("/v1/oembed?" + url.Values{
+		"url":    {postURL(postID)},
+		"format": {constant.OEMBED_FORMAT_JSON},
+	}.Encode())
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/security/middleware"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type reviewTransition struct {
+	from []string
+	to   string
+}
+
+// reviewTransitions is the editorial workflow, a post can only reach published through an approval.
+// Published posts cannot be edited, unpublishing takes them back to draft so changes are reviewed again.
+var reviewTransitions = map[string]reviewTransition{
+	constant.REVIEW_SUBMIT: {
+		from: []string{constant.POST_STATUS_DRAFT, constant.POST_STATUS_CHANGES_REQUESTED},
+		to:   constant.POST_STATUS_IN_REVIEW,
+	},
+	constant.REVIEW_APPROVE: {
+		from: []string{constant.POST_STATUS_IN_REVIEW},
+		to:   constant.POST_STATUS_APPROVED,
+	},
+	constant.REVIEW_REQUEST_CHANGES: {
+		from: []string{constant.POST_STATUS_IN_REVIEW},
+		to:   constant.POST_STATUS_CHANGES_REQUESTED,
+	},
+	constant.REVIEW_PUBLISH: {
+		from: []string{constant.POST_STATUS_APPROVED},
+		to:   constant.POST_STATUS_PUBLISHED,
+	},
+	constant.REVIEW_UNPUBLISH: {
+		from: []string{constant.POST_STATUS_PUBLISHED},
+		to:   constant.POST_STATUS_DRAFT,
+	},
+}
+
+// Transition moves a post through the editorial workflow, only approved posts can be published.
+func (s *postService) Transition(ctx context.Context, req model.PostReviewRequest) (*model.PostResponse, error) {
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	if !valid {
+		return nil, constant.ErrUnauthorized
+	}
+
+	transition, found := reviewTransitions[req.Action]
+	if !found {
+		return nil, constant.ErrPostTransition
+	}
+
+	post, err := s.getPost(ctx, req.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Action {
+	case constant.REVIEW_SUBMIT, constant.REVIEW_PUBLISH, constant.REVIEW_UNPUBLISH:
+		if !canEditPost(ctx, post) {
+			return nil, constant.ErrUnauthorized
+		}
+	case constant.REVIEW_APPROVE, constant.REVIEW_REQUEST_CHANGES:
+		if !canReviewPost(ctx, post) {
+			return nil, constant.ErrUnauthorized
+		}
+	}
+
+	if !transition.allows(post.Status) {
+		return nil, constant.ErrPostTransition
+	} else if req.Action == constant.REVIEW_REQUEST_CHANGES && req.Comment == "" {
+		return nil, constant.ErrReviewComment
+	}
+
+	if req.Action == constant.REVIEW_SUBMIT && req.ReviewerID != nil {
+		err = s.assignReviewer(ctx, post, *req.ReviewerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the links of the last publish, pages which are no longer linked are notified too
+	var previousBody string
+	if transition.to == constant.POST_STATUS_PUBLISHED {
+		previousBody, err = s.postRepository.GetPublishedBody(ctx, post.ID)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to get published post body")
+			return nil, constant.ErrServer
+		}
+	}
+
+	review := &model.PostReview{
+		Action:    req.Action,
+		Comment:   req.Comment,
+		CreatedAt: time.Now(),
+		PostID:    post.ID,
+		AccountID: claimsID,
+	}
+
+	err = s.postReviewRepository.Create(ctx, review, transition.from, transition.to)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to create post review")
+		switch err {
+		case pgx.ErrNoRows:
+			// the post moved on since it was read
+			return nil, constant.ErrPostTransition
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	// followers are sent a Delete when a post is unpublished, publishing it again updates their copy
+	activityType := constant.ACTIVITY_CREATE
+	if post.PublishedAt.Valid {
+		activityType = constant.ACTIVITY_UPDATE
+	}
+
+	post, err = s.getPost(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := model.NewPostResponse(post)
+	if transition.to == constant.POST_STATUS_PUBLISHED {
+		err = enqueueActivity(ctx, s.activityPubRepository, activityType, res)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to enqueue activity")
+		}
+
+		err = enqueueWebmentions(ctx, s.webmentionRepository, res, previousBody)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to enqueue webmentions")
+		}
+	} else if req.Action == constant.REVIEW_UNPUBLISH {
+		err = enqueueActivity(ctx, s.activityPubRepository, constant.ACTIVITY_DELETE, res)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to enqueue activity")
+		}
+
+		for window := range trendingWindows {
+			err = s.trendingRepository.Remove(ctx, window, post.ID)
+			if err != nil {
+				logger.Log().Err(err).Msg("failed to remove trending post")
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// ListReviews returns the review history of a post, it is only visible to the people involved in the post.
+func (s *postService) ListReviews(ctx context.Context, req model.PostReviewListRequest) ([]*model.PostReviewResponse, error) {
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	if !valid {
+		return nil, constant.ErrUnauthorized
+	}
+
+	post, err := s.getPost(ctx, req.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	if postRole(post, claimsID) == "" && !middleware.IsAdmin(ctx) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	reviews, err := s.postReviewRepository.List(ctx, post.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list post reviews")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewPostReviewListResponse(reviews), nil
+}
+
+// assignReviewer adds the reviewer to the post authors, the people writing a post cannot sign it off.
+func (s *postService) assignReviewer(ctx context.Context, post *model.Post, reviewerID int64) error {
+	role := postRole(post, reviewerID)
+	if role == constant.POST_AUTHOR_OWNER || role == constant.POST_AUTHOR_CO_AUTHOR {
+		return constant.ErrReviewerIsAuthor
+	} else if role == constant.POST_AUTHOR_REVIEWER {
+		return nil
+	}
+
+	_, err := s.accountRepository.Get(ctx, reviewerID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get account")
+		switch err {
+		case pgx.ErrNoRows:
+			return constant.ErrAccountNotFound
+		default:
+			return constant.ErrServer
+		}
+	}
+
+	err = s.postAuthorRepository.Set(ctx, post.ID, reviewerID, constant.POST_AUTHOR_REVIEWER, time.Now())
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to set post author")
+		return constant.ErrServer
+	}
+
+	return nil
+}
+
+// canReviewPost reports whether the authenticated account may approve or reject the post, that is an
+// assigned reviewer or an admin who is not writing the post.
+func canReviewPost(ctx context.Context, post *model.Post) bool {
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	if !valid {
+		return false
+	}
+
+	role := postRole(post, claimsID)
+	return role == constant.POST_AUTHOR_REVIEWER || (role == "" && middleware.IsAdmin(ctx))
+}
+
+// isPostVisible reports whether the authenticated account may read the post, unpublished posts are
+// only visible to their authors and admins.
+func isPostVisible(ctx context.Context, post *model.Post) bool {
+	if post.Status == constant.POST_STATUS_PUBLISHED || middleware.IsAdmin(ctx) {
+		return true
+	}
+
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	return valid && postRole(post, claimsID) != ""
+}
+
+func (t reviewTransition) allows(status string) bool {
+	for _, from := range t.from {
+		if from == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/security/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	ownerID    int64 = 1
+	coAuthorID int64 = 2
+	reviewerID int64 = 3
+	otherID    int64 = 4
+)
+
+func testPost(status string) *model.Post {
+	return &model.Post{
+		ID:     1,
+		Status: status,
+		Authors: []model.PostAuthor{
+			{AccountID: ownerID, Role: constant.POST_AUTHOR_OWNER},
+			{AccountID: coAuthorID, Role: constant.POST_AUTHOR_CO_AUTHOR},
+			{AccountID: reviewerID, Role: constant.POST_AUTHOR_REVIEWER},
+		},
+	}
+}
+
+func TestReviewTransitions(t *testing.T) {
+	tests := []struct {
+		action  string
+		from    string
+		allowed bool
+	}{
+		{constant.REVIEW_SUBMIT, constant.POST_STATUS_DRAFT, true},
+		{constant.REVIEW_SUBMIT, constant.POST_STATUS_CHANGES_REQUESTED, true},
+		{constant.REVIEW_SUBMIT, constant.POST_STATUS_IN_REVIEW, false},
+		{constant.REVIEW_SUBMIT, constant.POST_STATUS_PUBLISHED, false},
+		{constant.REVIEW_APPROVE, constant.POST_STATUS_IN_REVIEW, true},
+		{constant.REVIEW_APPROVE, constant.POST_STATUS_DRAFT, false},
+		{constant.REVIEW_APPROVE, constant.POST_STATUS_CHANGES_REQUESTED, false},
+		{constant.REVIEW_REQUEST_CHANGES, constant.POST_STATUS_IN_REVIEW, true},
+		{constant.REVIEW_REQUEST_CHANGES, constant.POST_STATUS_APPROVED, false},
+		{constant.REVIEW_PUBLISH, constant.POST_STATUS_APPROVED, true},
+		{constant.REVIEW_PUBLISH, constant.POST_STATUS_DRAFT, false},
+		{constant.REVIEW_PUBLISH, constant.POST_STATUS_IN_REVIEW, false},
+		{constant.REVIEW_PUBLISH, constant.POST_STATUS_CHANGES_REQUESTED, false},
+		{constant.REVIEW_PUBLISH, constant.POST_STATUS_PUBLISHED, false},
+		{constant.REVIEW_UNPUBLISH, constant.POST_STATUS_PUBLISHED, true},
+		{constant.REVIEW_UNPUBLISH, constant.POST_STATUS_APPROVED, false},
+	}
+
+	for _, tt := range tests {
+		transition, found := reviewTransitions[tt.action]
+		if assert.True(t, found, tt.action) {
+			assert.Equal(t, tt.allowed, transition.allows(tt.from), "%s from %s", tt.action, tt.from)
+		}
+	}
+
+	// published can only be reached through an approval
+	for action, transition := range reviewTransitions {
+		if transition.to == constant.POST_STATUS_PUBLISHED {
+			assert.Equal(t, []string{constant.POST_STATUS_APPROVED}, transition.from, action)
+		}
+	}
+}
+
+func TestCanReviewPost(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		review bool
+	}{
+		{"anonymous", context.Background(), false},
+		{"owner", middleware.WithClaims(context.Background(), ownerID, constant.ROLE_AUTHOR), false},
+		{"co-author", middleware.WithClaims(context.Background(), coAuthorID, constant.ROLE_AUTHOR), false},
+		{"reviewer", middleware.WithClaims(context.Background(), reviewerID, constant.ROLE_AUTHOR), true},
+		{"other author", middleware.WithClaims(context.Background(), otherID, constant.ROLE_AUTHOR), false},
+		{"admin", middleware.WithClaims(context.Background(), otherID, constant.ROLE_ADMIN), true},
+		{"admin owning the post", middleware.WithClaims(context.Background(), ownerID, constant.ROLE_ADMIN), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.review, canReviewPost(tt.ctx, testPost(constant.POST_STATUS_IN_REVIEW)))
+		})
+	}
+}
+
+func TestAssignReviewer(t *testing.T) {
+	s := &postService{}
+	ctx := middleware.WithClaims(context.Background(), ownerID, constant.ROLE_AUTHOR)
+	post := testPost(constant.POST_STATUS_DRAFT)
+
+	assert.Equal(t, constant.ErrReviewerIsAuthor, s.assignReviewer(ctx, post, ownerID))
+	assert.Equal(t, constant.ErrReviewerIsAuthor, s.assignReviewer(ctx, post, coAuthorID))
+	assert.NoError(t, s.assignReviewer(ctx, post, reviewerID))
+}
+
+func TestIsPostVisible(t *testing.T) {
+	roles := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"anonymous", context.Background()},
+		{"owner", middleware.WithClaims(context.Background(), ownerID, constant.ROLE_AUTHOR)},
+		{"co-author", middleware.WithClaims(context.Background(), coAuthorID, constant.ROLE_AUTHOR)},
+		{"reviewer", middleware.WithClaims(context.Background(), reviewerID, constant.ROLE_AUTHOR)},
+		{"other author", middleware.WithClaims(context.Background(), otherID, constant.ROLE_AUTHOR)},
+		{"admin", middleware.WithClaims(context.Background(), otherID, constant.ROLE_ADMIN)},
+	}
+
+	for _, status := range constant.POST_STATUSES {
+		for _, role := range roles {
+			t.Run(status+"/"+role.name, func(t *testing.T) {
+				visible := status == constant.POST_STATUS_PUBLISHED ||
+					(role.name != "anonymous" && role.name != "other author")
+				assert.Equal(t, visible, isPostVisible(role.ctx, testPost(status)))
+			})
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/security/middleware"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type ReactionService interface {
+	Add(ctx context.Context, req model.ReactionRequest) ([]*model.ReactionResponse, error)
+	Remove(ctx context.Context, req model.ReactionRequest) ([]*model.ReactionResponse, error)
+	Reconcile(ctx context.Context) error
+}
+
+func NewReactionService(reactionRepository repository.ReactionRepository, postRepository repository.PostRepository) ReactionService {
+	return &reactionService{reactionRepository, postRepository}
+}
+
+type reactionService struct {
+	reactionRepository repository.ReactionRepository
+	postRepository     repository.PostRepository
+}
+
+func (s *reactionService) Add(ctx context.Context, req model.ReactionRequest) ([]*model.ReactionResponse, error) {
+	claimsID, err := s.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.reactionRepository.Add(ctx, req.PostID, claimsID, req.Type)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to add reaction")
+		return nil, constant.ErrServer
+	}
+
+	return s.list(ctx, req.PostID)
+}
+
+func (s *reactionService) Remove(ctx context.Context, req model.ReactionRequest) ([]*model.ReactionResponse, error) {
+	claimsID, err := s.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.reactionRepository.Remove(ctx, req.PostID, claimsID, req.Type)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to remove reaction")
+		return nil, constant.ErrServer
+	}
+
+	return s.list(ctx, req.PostID)
+}
+
+func (s *reactionService) Reconcile(ctx context.Context) error {
+	return s.reactionRepository.Reconcile(ctx)
+}
+
+func (s *reactionService) validate(ctx context.Context, req model.ReactionRequest) (int64, error) {
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	if !valid {
+		return 0, constant.ErrUnauthorized
+	}
+
+	if !isReactionType(req.Type) {
+		return 0, constant.ErrReactionType
+	}
+
+	post, err := s.postRepository.Get(ctx, req.PostID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get post")
+		switch err {
+		case pgx.ErrNoRows:
+			return 0, constant.ErrPostNotFound
+		default:
+			return 0, constant.ErrServer
+		}
+	}
+
+	if !isPostVisible(ctx, post) {
+		return 0, constant.ErrPostNotFound
+	}
+
+	return claimsID, nil
+}
+
+func (s *reactionService) list(ctx context.Context, postID int64) ([]*model.ReactionResponse, error) {
+	reactions, err := listReactions(ctx, s.reactionRepository, postID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list reactions")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewReactionListResponse(reactions), nil
+}
+
+func isReactionType(reactionType string) bool {
+	for _, t := range constant.REACTION_TYPES {
+		if t == reactionType {
+			return true
+		}
+	}
+	return false
+}
+
+// listReactions aggregates the reactions of a post in the order of constant.REACTION_TYPES,
+// flagging the ones given by the authenticated account when there is one.
+func listReactions(ctx context.Context, reactionRepository repository.ReactionRepository, postID int64) ([]model.Reaction, error) {
+	counts, err := reactionRepository.Count(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	mine := make(map[string]bool)
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	if valid {
+		reactionTypes, err := reactionRepository.ListTypesByAccount(ctx, postID, claimsID)
+		if err != nil {
+			return nil, err
+		}
+		for _, reactionType := range reactionTypes {
+			mine[reactionType] = true
+		}
+	}
+
+	reactions := make([]model.Reaction, len(constant.REACTION_TYPES))
+	for i, reactionType := range constant.REACTION_TYPES {
+		reactions[i] = model.Reaction{
+			Type:        reactionType,
+			Count:       counts[reactionType],
+			ReactedByMe: mine[reactionType],
+		}
+	}
+	return reactions, nil
+}
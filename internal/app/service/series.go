@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/security/middleware"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type SeriesService interface {
+	Create(ctx context.Context, req model.SeriesCreateRequest) (*model.SeriesResponse, error)
+	List(ctx context.Context, req model.SeriesListRequest) ([]*model.SeriesResponse, error)
+	Get(ctx context.Context, req model.SeriesGetRequest) (*model.SeriesResponse, error)
+	Update(ctx context.Context, req model.SeriesUpdateRequest) (*model.SeriesResponse, error)
+	UpdatePosts(ctx context.Context, req model.SeriesPostUpdateRequest) (*model.SeriesResponse, error)
+	Delete(ctx context.Context, req model.SeriesDeleteRequest) error
+}
+
+func NewSeriesService(seriesRepository repository.SeriesRepository, postRepository repository.PostRepository) SeriesService {
+	return &seriesService{seriesRepository, postRepository}
+}
+
+type seriesService struct {
+	seriesRepository repository.SeriesRepository
+	postRepository   repository.PostRepository
+}
+
+func (s *seriesService) Create(ctx context.Context, req model.SeriesCreateRequest) (*model.SeriesResponse, error) {
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	if !valid {
+		return nil, constant.ErrUnauthorized
+	}
+
+	series := &model.Series{
+		Title:       req.Title,
+		Description: req.Description,
+		CreatedAt:   time.Now(),
+		AccountID:   claimsID,
+	}
+
+	err := s.seriesRepository.Create(ctx, series)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to create series")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewSeriesResponse(series), nil
+}
+
+func (s *seriesService) List(ctx context.Context, req model.SeriesListRequest) ([]*model.SeriesResponse, error) {
+	seriesList, err := s.seriesRepository.List(ctx, req.Limit, req.Offset, req.Title)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list series")
+		return nil, constant.ErrServer
+	}
+
+	for _, series := range seriesList {
+		hideUnpublishedParts(ctx, series)
+	}
+
+	return model.NewSeriesListResponse(seriesList), nil
+}
+
+func (s *seriesService) Get(ctx context.Context, req model.SeriesGetRequest) (*model.SeriesResponse, error) {
+	series, err := s.seriesRepository.Get(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get series")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrSeriesNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	hideUnpublishedParts(ctx, series)
+	return model.NewSeriesResponse(series), nil
+}
+
+func (s *seriesService) Update(ctx context.Context, req model.SeriesUpdateRequest) (*model.SeriesResponse, error) {
+	series, err := s.seriesRepository.Get(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get series")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrSeriesNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	if !middleware.IsMe(ctx, series.AccountID) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	series.Title = req.Title
+	series.Description = req.Description
+	series.UpdatedAt.Time = time.Now()
+
+	err = s.seriesRepository.Update(ctx, series)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to update series")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewSeriesResponse(series), nil
+}
+
+func (s *seriesService) UpdatePosts(ctx context.Context, req model.SeriesPostUpdateRequest) (*model.SeriesResponse, error) {
+	series, err := s.seriesRepository.Get(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get series")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrSeriesNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	if !middleware.IsMe(ctx, series.AccountID) {
+		return nil, constant.ErrUnauthorized
+	}
+
+	seen := make(map[int64]bool, len(req.PostIDs))
+	for _, postID := range req.PostIDs {
+		if seen[postID] {
+			return nil, constant.ErrSeriesPostDuplicate
+		}
+		seen[postID] = true
+
+		post, err := s.postRepository.Get(ctx, postID)
+		if err != nil {
+			logger.Log().Err(err).Msg("failed to get post")
+			switch err {
+			case pgx.ErrNoRows:
+				return nil, constant.ErrPostNotFound
+			default:
+				return nil, constant.ErrServer
+			}
+		}
+
+		if !canEditPost(ctx, post) {
+			return nil, constant.ErrUnauthorized
+		}
+
+		other, err := s.seriesRepository.GetByPostID(ctx, postID)
+		if err != nil && err != pgx.ErrNoRows {
+			logger.Log().Err(err).Msg("failed to get series by post id")
+			return nil, constant.ErrServer
+		} else if err == nil && other.ID != series.ID {
+			return nil, constant.ErrPostInOtherSeries
+		}
+	}
+
+	err = s.seriesRepository.UpdatePosts(ctx, series.ID, req.PostIDs)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to update series posts")
+		return nil, constant.ErrServer
+	}
+
+	series, err = s.seriesRepository.Get(ctx, series.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get series")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewSeriesResponse(series), nil
+}
+
+func (s *seriesService) Delete(ctx context.Context, req model.SeriesDeleteRequest) error {
+	series, err := s.seriesRepository.Get(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get series")
+		switch err {
+		case pgx.ErrNoRows:
+			return constant.ErrSeriesNotFound
+		default:
+			return constant.ErrServer
+		}
+	}
+
+	if !middleware.IsMe(ctx, series.AccountID) {
+		return constant.ErrUnauthorized
+	}
+
+	err = s.seriesRepository.Delete(ctx, req.ID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to delete series")
+		return constant.ErrServer
+	}
+
+	return nil
+}
+
+// hideUnpublishedParts leaves only the published posts of a series, numbered again, unless the series
+// belongs to the authenticated account.
+func hideUnpublishedParts(ctx context.Context, series *model.Series) {
+	if middleware.IsMe(ctx, series.AccountID) {
+		return
+	}
+
+	posts := series.Posts[:0]
+	for _, post := range series.Posts {
+		if post.Status == constant.POST_STATUS_PUBLISHED {
+			post.Position = len(posts) + 1
+			posts = append(posts, post)
+		}
+	}
+	series.Posts = posts
+}
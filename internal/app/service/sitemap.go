@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/sitemap"
+)
+
+type SitemapService interface {
+	Get(ctx context.Context, req model.SitemapGetRequest) (*model.SitemapDocument, error)
+}
+
+func NewSitemapService(sitemapRepository repository.SitemapRepository) SitemapService {
+	return &sitemapService{sitemapRepository}
+}
+
+type sitemapService struct {
+	sitemapRepository repository.SitemapRepository
+}
+
+// Get returns the sitemap page, or the root sitemap when the page is zero. The root sitemap lists the
+// posts directly until they no longer fit in one sitemap, then it becomes an index of the pages.
+func (s *sitemapService) Get(ctx context.Context, req model.SitemapGetRequest) (*model.SitemapDocument, error) {
+	key, err := s.sitemapRepository.DocumentKey(ctx, strconv.Itoa(req.Page))
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get sitemap cache key")
+		return nil, constant.ErrServer
+	}
+
+	doc, err := s.sitemapRepository.GetDocument(ctx, key)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get cached sitemap")
+	} else if doc != nil {
+		return doc, nil
+	}
+
+	pages, err := s.sitemapRepository.ListPages(ctx, sitemap.MaxURLs)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list sitemap pages")
+		return nil, constant.ErrServer
+	}
+
+	switch {
+	case req.Page == 0 && len(pages) > 1:
+		doc, err = s.renderIndex(pages)
+	case req.Page == 0 || req.Page <= len(pages):
+		doc, err = s.renderPage(ctx, req.Page)
+	default:
+		return nil, constant.ErrSitemapNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sitemapRepository.SetDocument(ctx, key, doc)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to cache sitemap")
+	}
+
+	return doc, nil
+}
+
+func (s *sitemapService) renderIndex(pages []time.Time) (*model.SitemapDocument, error) {
+	doc := new(model.SitemapDocument)
+	sitemaps := make([]sitemap.URL, len(pages))
+	for i, lastModified := range pages {
+		sitemaps[i] = sitemap.URL{Loc: siteURL(fmt.Sprintf("/sitemap-%d.xml", i+1)), LastMod: lastModified}
+		if lastModified.After(doc.Modified) {
+			doc.Modified = lastModified
+		}
+	}
+
+	data, err := sitemap.Index(sitemaps)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to render sitemap index")
+		return nil, constant.ErrServer
+	}
+
+	doc.Data = data
+	return doc, nil
+}
+
+// renderPage renders the posts of a page, the root sitemap holds the first page.
+func (s *sitemapService) renderPage(ctx context.Context, page int) (*model.SitemapDocument, error) {
+	offset := 0
+	if page > 0 {
+		offset = (page - 1) * sitemap.MaxURLs
+	}
+
+	entries, err := s.sitemapRepository.List(ctx, sitemap.MaxURLs, offset)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list sitemap entries")
+		return nil, constant.ErrServer
+	}
+
+	doc := new(model.SitemapDocument)
+	urls := make([]sitemap.URL, len(entries))
+	for i, entry := range entries {
+		urls[i] = sitemap.URL{Loc: postURL(entry.PostID), LastMod: entry.LastModified}
+		if entry.LastModified.After(doc.Modified) {
+			doc.Modified = entry.LastModified
+		}
+	}
+
+	data, err := sitemap.URLSet(urls)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to render sitemap")
+		return nil, constant.ErrServer
+	}
+
+	doc.Data = data
+	return doc, nil
+}
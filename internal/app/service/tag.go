@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+)
+
+type TagService interface {
+	List(ctx context.Context, req model.TagListRequest) ([]*model.TagResponse, error)
+}
+
+func NewTagService(tagRepository repository.TagRepository) TagService {
+	return &tagService{tagRepository}
+}
+
+type tagService struct {
+	tagRepository repository.TagRepository
+}
+
+func (s *tagService) List(ctx context.Context, req model.TagListRequest) ([]*model.TagResponse, error) {
+	tags, err := s.tagRepository.List(ctx, req.Limit, req.Offset, strings.ToLower(req.Name))
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list tags")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewTagListResponse(tags), nil
+}
+
+// normalizeTags lowercases and trims tag names, dropping empty and duplicate entries.
+func normalizeTags(tags []string) []string {
+	res := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		res = append(res, tag)
+	}
+	return res
+}
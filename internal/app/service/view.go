@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"regexp"
+
+	"github.com/anonychun/go-blog-api/internal/security/middleware"
+)
+
+var botUserAgent = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|preview|fetch|monitor|headless|curl|wget|httpclient|python-requests|go-http-client|facebookexternalhit`)
+
+// isBot reports whether the user agent belongs to an automated client, requests without
+// a user agent are treated as automated as well.
+func isBot(userAgent string) bool {
+	return userAgent == "" || botUserAgent.MatchString(userAgent)
+}
+
+// visitorID identifies a visitor for view deduplication, authenticated accounts are
+// identified by their id and anonymous visitors by a hash of their address and user agent.
+func visitorID(ctx context.Context, remoteAddr, userAgent string) string {
+	claimsID, valid := middleware.GetClaimsID(ctx)
+	if valid {
+		return fmt.Sprintf("account:%d", claimsID)
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	sum := sha256.Sum256([]byte(host + "|" + userAgent))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/webmention"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+type WebmentionService interface {
+	Create(ctx context.Context, req model.WebmentionCreateRequest) error
+	List(ctx context.Context, req model.WebmentionListRequest) ([]*model.WebmentionResponse, error)
+	Verify(ctx context.Context) error
+	Send(ctx context.Context) error
+}
+
+func NewWebmentionService(webmentionRepository repository.WebmentionRepository, postRepository repository.PostRepository) WebmentionService {
+	return &webmentionService{
+		webmentionRepository,
+		postRepository,
+		webmention.NewClient(config.Cfg().WebmentionTimeout),
+	}
+}
+
+type webmentionService struct {
+	webmentionRepository repository.WebmentionRepository
+	postRepository       repository.PostRepository
+	client               *webmention.Client
+}
+
+// Create accepts a mention of a published post, the source is fetched later by the verification worker.
+func (s *webmentionService) Create(ctx context.Context, req model.WebmentionCreateRequest) error {
+	postID, valid := parsePostURL(req.Target)
+	if !valid {
+		return constant.ErrWebmentionTarget
+	}
+
+	source, err := url.Parse(req.Source)
+	if err != nil || (source.Scheme != "http" && source.Scheme != "https") || source.Host == "" || req.Source == req.Target {
+		return constant.ErrWebmentionSource
+	}
+
+	post, err := s.postRepository.Get(ctx, postID)
+	if err == pgx.ErrNoRows {
+		return constant.ErrWebmentionTarget
+	} else if err != nil {
+		logger.Log().Err(err).Msg("failed to get post")
+		return constant.ErrServer
+	} else if post.Status != constant.POST_STATUS_PUBLISHED {
+		return constant.ErrWebmentionTarget
+	}
+
+	err = s.webmentionRepository.Set(ctx, &model.Webmention{
+		PostID:      post.ID,
+		Source:      req.Source,
+		Target:      req.Target,
+		Status:      constant.WEBMENTION_PENDING,
+		NextCheckAt: sql.NullTime{Time: time.Now(), Valid: true},
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to set webmention")
+		return constant.ErrServer
+	}
+
+	return nil
+}
+
+func (s *webmentionService) List(ctx context.Context, req model.WebmentionListRequest) ([]*model.WebmentionResponse, error) {
+	post, err := s.postRepository.Get(ctx, req.PostID)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to get post")
+		switch err {
+		case pgx.ErrNoRows:
+			return nil, constant.ErrPostNotFound
+		default:
+			return nil, constant.ErrServer
+		}
+	}
+
+	if !isPostVisible(ctx, post) {
+		return nil, constant.ErrPostNotFound
+	}
+
+	mentions, err := s.webmentionRepository.List(ctx, post.ID, req.Limit, req.Offset)
+	if err != nil {
+		logger.Log().Err(err).Msg("failed to list webmentions")
+		return nil, constant.ErrServer
+	}
+
+	return model.NewWebmentionListResponse(mentions), nil
+}
+
+// Verify checks the received mentions that are due, a source that no longer links to the post removes
+// its mention while unreachable sources are retried with an exponential backoff.
+func (s *webmentionService) Verify(ctx context.Context) error {
+	now := time.Now()
+	lease := config.Cfg().WebmentionTimeout * constant.WEBMENTION_BATCH
+	mentions, err := s.webmentionRepository.Claim(ctx, now, now.Add(lease), constant.WEBMENTION_BATCH)
+	if err != nil {
+		return err
+	}
+
+	for _, mention := range mentions {
+		err = s.client.Verify(ctx, mention.Source, mention.Target)
+		switch {
+		case err == nil:
+			err = s.webmentionRepository.Verify(ctx, mention.ID, time.Now())
+		case err == webmention.ErrNoLink || err == webmention.ErrSourceGone:
+			err = s.webmentionRepository.Delete(ctx, mention.ID)
+		case ctx.Err() != nil:
+			return ctx.Err()
+		default:
+			mention.Attempts++
+			if mention.Attempts >= config.Cfg().WebmentionMaxAttempts {
+				logger.Log().Err(err).Msgf("giving up verifying webmention from %s", mention.Source)
+				err = s.webmentionRepository.Delete(ctx, mention.ID)
+				break
+			}
+
+			mention.NextCheckAt = sql.NullTime{Time: time.Now().Add(constant.WEBMENTION_RETRY_DELAY << (mention.Attempts - 1)), Valid: true}
+			err = s.webmentionRepository.Retry(ctx, mention)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Send notifies the pages linked from published posts, pages without a Webmention endpoint are skipped.
+func (s *webmentionService) Send(ctx context.Context) error {
+	now := time.Now()
+	lease := 2 * config.Cfg().WebmentionTimeout * constant.WEBMENTION_BATCH
+	deliveries, err := s.webmentionRepository.ClaimDeliveries(ctx, now, now.Add(lease), constant.WEBMENTION_BATCH)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		endpoint, err := s.client.Discover(ctx, delivery.Target)
+		if err == nil && endpoint != "" {
+			err = s.client.Send(ctx, endpoint, delivery.Source, delivery.Target)
+		}
+
+		if err == nil {
+			err = s.webmentionRepository.DeleteDelivery(ctx, delivery.ID)
+			if err != nil {
+				return err
+			}
+			continue
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		delivery.Attempts++
+		if delivery.Attempts >= config.Cfg().WebmentionMaxAttempts {
+			logger.Log().Err(err).Msgf("giving up sending webmention to %s", delivery.Target)
+			err = s.webmentionRepository.DeleteDelivery(ctx, delivery.ID)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = time.Now().Add(constant.WEBMENTION_RETRY_DELAY << (delivery.Attempts - 1))
+		err = s.webmentionRepository.RetryDelivery(ctx, delivery)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enqueueWebmentions queues a mention of every page linked from the post, pages that were only linked
+// from the previous body are notified too so they can drop the mention.
+func enqueueWebmentions(ctx context.Context, webmentionRepository repository.WebmentionRepository, post *model.PostResponse, previousBody string) error {
+	targets := webmention.Links(post.Body + "\n" + previousBody)
+	if len(targets) == 0 {
+		return nil
+	}
+
+	return webmentionRepository.EnqueueDeliveries(ctx, post.ID, postURL(post.ID), targets, time.Now())
+}
+
+// parsePostURL extracts the post id from the public URL of a post.
+func parsePostURL(target string) (int64, bool) {
+	prefix := siteURL("/posts/")
+	if !strings.HasPrefix(target, prefix) {
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(target, prefix), "/"), 10, 64)
+	return id, err == nil
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -33,6 +35,53 @@ type Config struct {
 	RedisDatabase int
 	RedisPoolSize int
 	RedisTTL      time.Duration
+
+	SearchLanguage string
+
+	ReactionReconcileInterval time.Duration
+
+	ViewDedupWindow   time.Duration
+	ViewFlushInterval time.Duration
+
+	TrendingInterval time.Duration
+
+	StorageDriver    string
+	StorageLocalPath string
+	StoragePublicUrl string
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3Timeout   time.Duration
+
+	MediaMaxSize       int64
+	MediaImageWidths   []int
+	MediaThumbnailSize int
+	MediaJpegQuality   int
+	MediaRetention     time.Duration
+	MediaGCInterval    time.Duration
+
+	SiteUrl         string
+	SiteTitle       string
+	SiteDescription string
+
+	FeedItemCount int
+	FeedContent   string
+
+	ActivityPubDeliveryInterval time.Duration
+	ActivityPubMaxAttempts      int
+	ActivityPubTimeout          time.Duration
+
+	WebmentionInterval    time.Duration
+	WebmentionMaxAttempts int
+	WebmentionTimeout     time.Duration
+
+	ThemePath   string
+	ThemeReload bool
+
+	ImportMaxSize int64
 }
 
 func load() Config {
@@ -49,27 +98,73 @@ func load() Config {
 	fang.ReadInConfig()
 
 	return Config{
-		AppPort:                 fang.GetInt("APP_PORT"),
-		HttpRateLimitRequest:    fang.GetInt("HTTP_RATE_LIMIT_REQUEST"),
-		HttpRateLimitTime:       fang.GetDuration("HTTP_RATE_LIMIT_TIME"),
-		JwtSecretKey:            fang.GetString("JWT_SECRET_KEY"),
-		JwtTTL:                  fang.GetDuration("JWT_TTL"),
-		PaginationLimit:         fang.GetInt("PAGINATION_LIMIT"),
-		PostgresUser:            fang.GetString("POSTGRES_USER"),
-		PostgresPassword:        fang.GetString("POSTGRES_PASSWORD"),
-		PostgresHost:            fang.GetString("POSTGRES_HOST"),
-		PostgresPort:            fang.GetInt("POSTGRES_PORT"),
-		PostgresDatabase:        fang.GetString("POSTGRES_DATABASE"),
-		PostgresMaxIdleConns:    fang.GetInt("POSTGRES_MAX_IDLE_CONNS"),
-		PostgresMaxOpenConns:    fang.GetInt("POSTGRES_MAX_OPEN_CONNS"),
-		PostgresConnMaxLifetime: fang.GetDuration("POSTGRES_CONN_MAX_LIFETIME"),
-		RedisPassword:           fang.GetString("REDIS_PASSWORD"),
-		RedisHost:               fang.GetString("REDIS_HOST"),
-		RedisPort:               fang.GetInt("REDIS_PORT"),
-		RedisDatabase:           fang.GetInt("REDIS_DATABASE"),
-		RedisPoolSize:           fang.GetInt("REDIS_POOL_SIZE"),
-		RedisTTL:                fang.GetDuration("REDIS_TTL"),
+		AppPort:                     fang.GetInt("APP_PORT"),
+		HttpRateLimitRequest:        fang.GetInt("HTTP_RATE_LIMIT_REQUEST"),
+		HttpRateLimitTime:           fang.GetDuration("HTTP_RATE_LIMIT_TIME"),
+		JwtSecretKey:                fang.GetString("JWT_SECRET_KEY"),
+		JwtTTL:                      fang.GetDuration("JWT_TTL"),
+		PaginationLimit:             fang.GetInt("PAGINATION_LIMIT"),
+		PostgresUser:                fang.GetString("POSTGRES_USER"),
+		PostgresPassword:            fang.GetString("POSTGRES_PASSWORD"),
+		PostgresHost:                fang.GetString("POSTGRES_HOST"),
+		PostgresPort:                fang.GetInt("POSTGRES_PORT"),
+		PostgresDatabase:            fang.GetString("POSTGRES_DATABASE"),
+		PostgresMaxIdleConns:        fang.GetInt("POSTGRES_MAX_IDLE_CONNS"),
+		PostgresMaxOpenConns:        fang.GetInt("POSTGRES_MAX_OPEN_CONNS"),
+		PostgresConnMaxLifetime:     fang.GetDuration("POSTGRES_CONN_MAX_LIFETIME"),
+		RedisPassword:               fang.GetString("REDIS_PASSWORD"),
+		RedisHost:                   fang.GetString("REDIS_HOST"),
+		RedisPort:                   fang.GetInt("REDIS_PORT"),
+		RedisDatabase:               fang.GetInt("REDIS_DATABASE"),
+		RedisPoolSize:               fang.GetInt("REDIS_POOL_SIZE"),
+		RedisTTL:                    fang.GetDuration("REDIS_TTL"),
+		SearchLanguage:              fang.GetString("SEARCH_LANGUAGE"),
+		ReactionReconcileInterval:   fang.GetDuration("REACTION_RECONCILE_INTERVAL"),
+		ViewDedupWindow:             fang.GetDuration("VIEW_DEDUP_WINDOW"),
+		ViewFlushInterval:           fang.GetDuration("VIEW_FLUSH_INTERVAL"),
+		TrendingInterval:            fang.GetDuration("TRENDING_INTERVAL"),
+		StorageDriver:               fang.GetString("STORAGE_DRIVER"),
+		StorageLocalPath:            fang.GetString("STORAGE_LOCAL_PATH"),
+		StoragePublicUrl:            fang.GetString("STORAGE_PUBLIC_URL"),
+		S3Endpoint:                  fang.GetString("S3_ENDPOINT"),
+		S3Region:                    fang.GetString("S3_REGION"),
+		S3Bucket:                    fang.GetString("S3_BUCKET"),
+		S3AccessKey:                 fang.GetString("S3_ACCESS_KEY"),
+		S3SecretKey:                 fang.GetString("S3_SECRET_KEY"),
+		S3Timeout:                   fang.GetDuration("S3_TIMEOUT"),
+		MediaMaxSize:                fang.GetInt64("MEDIA_MAX_SIZE"),
+		MediaImageWidths:            getInts(fang.GetString("MEDIA_IMAGE_WIDTHS")),
+		MediaThumbnailSize:          fang.GetInt("MEDIA_THUMBNAIL_SIZE"),
+		MediaJpegQuality:            fang.GetInt("MEDIA_JPEG_QUALITY"),
+		MediaRetention:              fang.GetDuration("MEDIA_RETENTION"),
+		MediaGCInterval:             fang.GetDuration("MEDIA_GC_INTERVAL"),
+		SiteUrl:                     fang.GetString("SITE_URL"),
+		SiteTitle:                   fang.GetString("SITE_TITLE"),
+		SiteDescription:             fang.GetString("SITE_DESCRIPTION"),
+		FeedItemCount:               fang.GetInt("FEED_ITEM_COUNT"),
+		FeedContent:                 fang.GetString("FEED_CONTENT"),
+		ActivityPubDeliveryInterval: fang.GetDuration("ACTIVITYPUB_DELIVERY_INTERVAL"),
+		ActivityPubMaxAttempts:      fang.GetInt("ACTIVITYPUB_MAX_ATTEMPTS"),
+		ActivityPubTimeout:          fang.GetDuration("ACTIVITYPUB_TIMEOUT"),
+		WebmentionInterval:          fang.GetDuration("WEBMENTION_INTERVAL"),
+		WebmentionMaxAttempts:       fang.GetInt("WEBMENTION_MAX_ATTEMPTS"),
+		WebmentionTimeout:           fang.GetDuration("WEBMENTION_TIMEOUT"),
+		ThemePath:                   fang.GetString("THEME_PATH"),
+		ThemeReload:                 fang.GetBool("THEME_RELOAD"),
+		ImportMaxSize:               fang.GetInt64("IMPORT_MAX_SIZE"),
+	}
+}
+
+// getInts parses a comma separated list of integers, invalid entries are skipped.
+func getInts(value string) []int {
+	var res []int
+	for _, field := range strings.Split(value, ",") {
+		i, err := strconv.Atoi(strings.TrimSpace(field))
+		if err == nil {
+			res = append(res, i)
+		}
 	}
+	return res
 }
 
 var config = load()
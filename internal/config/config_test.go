@@ -27,4 +27,32 @@ func TestConfig(t *testing.T) {
 	assert.GreaterOrEqual(t, Cfg().RedisDatabase, 0, "REDIS_DATABASE")
 	assert.NotZero(t, Cfg().RedisPoolSize, "REDIS_POOL_SIZE")
 	assert.NotEmpty(t, Cfg().RedisTTL, "REDIS_TTL")
+	assert.NotEmpty(t, Cfg().SearchLanguage, "SEARCH_LANGUAGE")
+	assert.NotEmpty(t, Cfg().ReactionReconcileInterval, "REACTION_RECONCILE_INTERVAL")
+	assert.NotEmpty(t, Cfg().ViewDedupWindow, "VIEW_DEDUP_WINDOW")
+	assert.NotEmpty(t, Cfg().ViewFlushInterval, "VIEW_FLUSH_INTERVAL")
+	assert.NotEmpty(t, Cfg().TrendingInterval, "TRENDING_INTERVAL")
+	assert.NotEmpty(t, Cfg().StorageDriver, "STORAGE_DRIVER")
+	assert.NotEmpty(t, Cfg().StorageLocalPath, "STORAGE_LOCAL_PATH")
+	assert.NotEmpty(t, Cfg().StoragePublicUrl, "STORAGE_PUBLIC_URL")
+	assert.NotEmpty(t, Cfg().S3Timeout, "S3_TIMEOUT")
+	assert.NotZero(t, Cfg().MediaMaxSize, "MEDIA_MAX_SIZE")
+	assert.NotEmpty(t, Cfg().MediaImageWidths, "MEDIA_IMAGE_WIDTHS")
+	assert.NotZero(t, Cfg().MediaThumbnailSize, "MEDIA_THUMBNAIL_SIZE")
+	assert.NotZero(t, Cfg().MediaJpegQuality, "MEDIA_JPEG_QUALITY")
+	assert.NotEmpty(t, Cfg().MediaRetention, "MEDIA_RETENTION")
+	assert.NotEmpty(t, Cfg().MediaGCInterval, "MEDIA_GC_INTERVAL")
+	assert.NotEmpty(t, Cfg().SiteUrl, "SITE_URL")
+	assert.NotEmpty(t, Cfg().SiteTitle, "SITE_TITLE")
+	assert.NotEmpty(t, Cfg().SiteDescription, "SITE_DESCRIPTION")
+	assert.NotZero(t, Cfg().FeedItemCount, "FEED_ITEM_COUNT")
+	assert.NotEmpty(t, Cfg().FeedContent, "FEED_CONTENT")
+	assert.NotEmpty(t, Cfg().ActivityPubDeliveryInterval, "ACTIVITYPUB_DELIVERY_INTERVAL")
+	assert.NotZero(t, Cfg().ActivityPubMaxAttempts, "ACTIVITYPUB_MAX_ATTEMPTS")
+	assert.NotEmpty(t, Cfg().ActivityPubTimeout, "ACTIVITYPUB_TIMEOUT")
+	assert.NotEmpty(t, Cfg().WebmentionInterval, "WEBMENTION_INTERVAL")
+	assert.NotZero(t, Cfg().WebmentionMaxAttempts, "WEBMENTION_MAX_ATTEMPTS")
+	assert.NotEmpty(t, Cfg().WebmentionTimeout, "WEBMENTION_TIMEOUT")
+	assert.NotEmpty(t, Cfg().ThemePath, "THEME_PATH")
+	assert.NotZero(t, Cfg().ImportMaxSize, "IMPORT_MAX_SIZE")
 }
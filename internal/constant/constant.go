@@ -1,5 +1,142 @@
 package constant
 
+import "time"
+
 const (
 	API_KEY_HEADER = "X-API-Key"
 )
+
+const (
+	TAG_MATCH_ANY = "any"
+	TAG_MATCH_ALL = "all"
+)
+
+const (
+	ROLE_AUTHOR = "author"
+	ROLE_ADMIN  = "admin"
+)
+
+const (
+	COMMENT_MODE_FLAT = "flat"
+	COMMENT_MODE_TREE = "tree"
+)
+
+const (
+	REACTION_LIKE   = "like"
+	REACTION_HEART  = "heart"
+	REACTION_LAUGH  = "laugh"
+	REACTION_HOORAY = "hooray"
+	REACTION_ROCKET = "rocket"
+	REACTION_EYES   = "eyes"
+)
+
+var REACTION_TYPES = []string{
+	REACTION_LIKE,
+	REACTION_HEART,
+	REACTION_LAUGH,
+	REACTION_HOORAY,
+	REACTION_ROCKET,
+	REACTION_EYES,
+}
+
+const (
+	TRENDING_WINDOW_DAY   = "day"
+	TRENDING_WINDOW_WEEK  = "week"
+	TRENDING_WINDOW_MONTH = "month"
+)
+
+const (
+	STORAGE_DRIVER_LOCAL = "local"
+	STORAGE_DRIVER_S3    = "s3"
+)
+
+// MEDIA_TYPES maps the sniffed content types accepted for uploads to their file extension.
+var MEDIA_TYPES = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+const MEDIA_VARIANT_THUMBNAIL = "thumbnail"
+
+const (
+	POST_AUTHOR_OWNER     = "owner"
+	POST_AUTHOR_CO_AUTHOR = "co-author"
+	POST_AUTHOR_REVIEWER  = "reviewer"
+)
+
+const (
+	POST_STATUS_DRAFT             = "draft"
+	POST_STATUS_IN_REVIEW         = "in_review"
+	POST_STATUS_CHANGES_REQUESTED = "changes_requested"
+	POST_STATUS_APPROVED          = "approved"
+	POST_STATUS_PUBLISHED         = "published"
+)
+
+var POST_STATUSES = []string{
+	POST_STATUS_DRAFT,
+	POST_STATUS_IN_REVIEW,
+	POST_STATUS_CHANGES_REQUESTED,
+	POST_STATUS_APPROVED,
+	POST_STATUS_PUBLISHED,
+}
+
+const (
+	REVIEW_SUBMIT          = "submit"
+	REVIEW_APPROVE         = "approve"
+	REVIEW_REQUEST_CHANGES = "request_changes"
+	REVIEW_PUBLISH         = "publish"
+	REVIEW_UNPUBLISH       = "unpublish"
+)
+
+const (
+	FEED_CONTENT_FULL    = "full"
+	FEED_CONTENT_SUMMARY = "summary"
+)
+
+// FEED_SUMMARY_LENGTH is the number of characters of the body kept in feed summaries.
+const FEED_SUMMARY_LENGTH = 280
+
+const (
+	ACTIVITY_CREATE = "Create"
+	ACTIVITY_UPDATE = "Update"
+	ACTIVITY_DELETE = "Delete"
+	ACTIVITY_FOLLOW = "Follow"
+	ACTIVITY_ACCEPT = "Accept"
+	ACTIVITY_UNDO   = "Undo"
+)
+
+const (
+	// ACTIVITY_DELIVERY_BATCH is the number of queued deliveries sent on each run of the delivery worker.
+	ACTIVITY_DELIVERY_BATCH = 50
+
+	// ACTIVITY_RETRY_DELAY is the wait after the first failed delivery, it doubles with every attempt.
+	ACTIVITY_RETRY_DELAY = time.Minute
+)
+
+const (
+	WEBMENTION_PENDING  = "pending"
+	WEBMENTION_VERIFIED = "verified"
+)
+
+const (
+	// WEBMENTION_BATCH is the number of mentions verified and sent on each run of the webmention workers.
+	WEBMENTION_BATCH = 50
+
+	// WEBMENTION_RETRY_DELAY is the wait after the first failed fetch, it doubles with every attempt.
+	WEBMENTION_RETRY_DELAY = time.Minute
+)
+
+const (
+	TWITTER_CARD_SUMMARY       = "summary"
+	TWITTER_CARD_SUMMARY_IMAGE = "summary_large_image"
+)
+
+const (
+	OEMBED_FORMAT_JSON = "json"
+
+	// OEMBED_WIDTH and OEMBED_HEIGHT are the size of an embedded post unless the consumer asks for less.
+	OEMBED_WIDTH  = 600
+	OEMBED_HEIGHT = 240
+)
@@ -15,13 +15,64 @@ var (
 	ErrRequestBody       = errors.New("Invalid request body")
 	ErrUnauthorized      = errors.New("You are not authorized to perform this action")
 	ErrFieldValidation   = errors.New("Field is not valid")
+	ErrPrecondition      = errors.New("Resource was modified since it was read, fetch it again and retry")
 
 	ErrAccountNotFound    = errors.New("Account not found")
 	ErrEmailRegistered    = errors.New("Email already in use")
 	ErrEmailNotRegistered = errors.New("Email not registered")
 	ErrWrongPassword      = errors.New("Password incorrect")
 
-	ErrPostNotFound = errors.New("Post not found")
+	ErrPostNotFound   = errors.New("Post not found")
+	ErrTrendingWindow = errors.New("Trending window must be one of day, week or month")
+
+	ErrPostAuthorNotFound = errors.New("Account is not an author of the post")
+	ErrPostLastOwner      = errors.New("Post must keep at least one owner")
+
+	ErrPostStatus       = errors.New("Post status is not valid")
+	ErrPostInReview     = errors.New("Post is in review and cannot be edited")
+	ErrPostPublished    = errors.New("Post is published, unpublish it before editing")
+	ErrPostTransition   = errors.New("Action is not allowed in the current post status")
+	ErrReviewComment    = errors.New("Comment is required when requesting changes")
+	ErrReviewerIsAuthor = errors.New("Reviewer cannot be an owner or co-author of the post")
+
+	ErrCategoryNotFound    = errors.New("Category not found")
+	ErrCategoryParent      = errors.New("Category cannot be placed under itself or its descendants")
+	ErrCategoryHasChildren = errors.New("Category still has child categories")
+
+	ErrSeriesNotFound      = errors.New("Series not found")
+	ErrSeriesPostDuplicate = errors.New("Post appears more than once in series")
+	ErrPostInOtherSeries   = errors.New("Post already belongs to another series")
+
+	ErrCommentNotFound = errors.New("Comment not found")
+	ErrCommentParent   = errors.New("Parent comment belongs to another post")
+
+	ErrReactionType = errors.New("Reaction type is not supported")
+
+	ErrMediaNotFound = errors.New("Media not found")
+	ErrMediaFile     = errors.New("Media file is missing from the form field file")
+	ErrMediaTooLarge = errors.New("Media file exceeds the maximum upload size")
+	ErrMediaType     = errors.New("Media type is not supported")
+	ErrMediaImage    = errors.New("Image is corrupted or its dimensions are too large")
+	ErrMediaInUse    = errors.New("Media is still used by posts, delete it with force to detach it")
+
+	ErrSitemapNotFound = errors.New("Sitemap not found")
+
+	ErrWebFingerResource = errors.New("Resource is not an account of this site")
+	ErrSignature         = errors.New("HTTP signature is missing or invalid")
+	ErrActivity          = errors.New("Activity is malformed or not addressed to this actor")
+
+	ErrWebmentionSource = errors.New("Source must be an http or https URL other than the target")
+	ErrWebmentionTarget = errors.New("Target is not a published post of this site")
+
+	ErrOEmbedURL    = errors.New("URL is not a published post of this site")
+	ErrOEmbedFormat = errors.New("Format is not supported, only json is available")
+
+	ErrPageNotFound = errors.New("Page not found")
+
+	ErrImportFile     = errors.New("Import file is missing from the form field file")
+	ErrImportTooLarge = errors.New("Import file exceeds the maximum upload size")
+	ErrImportFormat   = errors.New("Import file is not a valid WordPress export")
+	ErrMarkdownFormat = errors.New("Front matter format is not supported, use yaml or toml")
 )
 
 func NewErrFieldValidation(err validator.FieldError) error {
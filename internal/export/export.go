@@ -0,0 +1,181 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ManifestFile records the exported pages, it is kept in the output directory so later exports only
+// render what changed since.
+const ManifestFile = ".export.json"
+
+var (
+	ErrNotFound = errors.New("export: page not found")
+	ErrResponse = errors.New("export: unexpected response")
+)
+
+// Exporter renders pages through an http.Handler and writes the responses to a directory, laid out the
+// way static hosts serve them: /posts/1 is written to posts/1/index.html while paths with an extension,
+// such as /v1/feed.atom, are written as they are.
+type Exporter struct {
+	handler http.Handler
+	dir     string
+}
+
+func New(handler http.Handler, dir string) *Exporter {
+	return &Exporter{handler: handler, dir: dir}
+}
+
+// Write requests the page at urlPath and stores the response body, a 404 is reported as ErrNotFound
+// and leaves the directory untouched.
+func (e *Exporter) Write(urlPath string) error {
+	req := httptest.NewRequest(http.MethodGet, urlPath, nil)
+	rec := httptest.NewRecorder()
+	e.handler.ServeHTTP(rec, req)
+
+	switch rec.Code {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrNotFound, urlPath)
+	default:
+		return fmt.Errorf("%w: %s responded with %d", ErrResponse, urlPath, rec.Code)
+	}
+
+	file := e.file(urlPath)
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, rec.Body.Bytes(), 0644)
+}
+
+// Remove deletes an exported page, for pages written as an index.html the whole directory goes along
+// with the pages nested below it.
+func (e *Exporter) Remove(urlPath string) error {
+	file := e.file(urlPath)
+	if filepath.Base(file) == "index.html" && filepath.Dir(file) != filepath.Clean(e.dir) {
+		return os.RemoveAll(filepath.Dir(file))
+	}
+
+	err := os.Remove(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// CopyDir copies the files below src to urlPath, e.g. the assets of a theme to /static.
+func (e *Exporter) CopyDir(src, urlPath string) error {
+	dst := filepath.Join(e.dir, filepath.FromSlash(path.Clean("/"+urlPath)))
+	return filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		return copyFile(file, filepath.Join(dst, rel))
+	})
+}
+
+// file maps a URL path to the file it is exported to.
+func (e *Exporter) file(urlPath string) string {
+	urlPath = path.Clean("/" + urlPath)
+	if path.Ext(urlPath) == "" {
+		urlPath = path.Join(urlPath, "index.html")
+	}
+	return filepath.Join(e.dir, filepath.FromSlash(strings.TrimPrefix(urlPath, "/")))
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// Page is an exported page in the manifest. Related lists the pages built from this one, such as the
+// author pages listing a post, which have to be rebuilt whenever the page changes or goes away.
+type Page struct {
+	Modified time.Time `json:"modified"`
+	Related  []string  `json:"related,omitempty"`
+}
+
+// Manifest maps the URL path of the exported pages to the state they were exported in. Sitemaps is the
+// number of sitemap pages, so the ones left over when the sitemap shrinks can be removed.
+type Manifest struct {
+	Pages    map[string]*Page `json:"pages"`
+	Sitemaps int              `json:"sitemaps,omitempty"`
+}
+
+func NewManifest() *Manifest {
+	return &Manifest{Pages: make(map[string]*Page)}
+}
+
+// LoadManifest reads the manifest of a previous export from dir, an empty manifest is returned when
+// nothing was exported yet.
+func LoadManifest(dir string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return NewManifest(), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	manifest := NewManifest()
+	err = json.Unmarshal(data, manifest)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Pages == nil {
+		manifest.Pages = make(map[string]*Page)
+	}
+	return manifest, nil
+}
+
+// Changed reports whether the page at urlPath was modified since it was exported.
+func (m *Manifest) Changed(urlPath string, modified time.Time) bool {
+	page, found := m.Pages[urlPath]
+	return !found || !page.Modified.Equal(modified)
+}
+
+func (m *Manifest) Save(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, ManifestFile), data, 0644)
+}
@@ -0,0 +1,102 @@
+package export
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/", "/posts/1", "/v1/feed.atom":
+			w.Write([]byte("page " + r.URL.Path))
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	exporter := New(testHandler(), dir)
+
+	require.NoError(t, exporter.Write("/"))
+	require.NoError(t, exporter.Write("/posts/1"))
+	require.NoError(t, exporter.Write("/v1/feed.atom"))
+	assert.Equal(t, "page /", readFile(t, filepath.Join(dir, "index.html")))
+	assert.Equal(t, "page /posts/1", readFile(t, filepath.Join(dir, "posts", "1", "index.html")))
+	assert.Equal(t, "page /v1/feed.atom", readFile(t, filepath.Join(dir, "v1", "feed.atom")))
+
+	err := exporter.Write("/posts/2")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoDirExists(t, filepath.Join(dir, "posts", "2"))
+
+	err = exporter.Write("/broken")
+	assert.True(t, errors.Is(err, ErrResponse))
+}
+
+func TestRemove(t *testing.T) {
+	dir := t.TempDir()
+	exporter := New(testHandler(), dir)
+
+	require.NoError(t, exporter.Write("/"))
+	require.NoError(t, exporter.Write("/posts/1"))
+	require.NoError(t, exporter.Write("/v1/feed.atom"))
+
+	require.NoError(t, exporter.Remove("/posts/1"))
+	require.NoError(t, exporter.Remove("/v1/feed.atom"))
+	require.NoError(t, exporter.Remove("/posts/2"))
+	assert.NoDirExists(t, filepath.Join(dir, "posts", "1"))
+	assert.NoFileExists(t, filepath.Join(dir, "v1", "feed.atom"))
+
+	require.NoError(t, exporter.Remove("/"))
+	assert.NoFileExists(t, filepath.Join(dir, "index.html"))
+	assert.DirExists(t, dir)
+}
+
+func TestCopyDir(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "css"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "css", "style.css"), []byte("body {}"), 0644))
+
+	dir := t.TempDir()
+	require.NoError(t, New(testHandler(), dir).CopyDir(src, "/static"))
+	assert.Equal(t, "body {}", readFile(t, filepath.Join(dir, "static", "css", "style.css")))
+}
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	manifest, err := LoadManifest(dir)
+	require.NoError(t, err)
+	assert.Empty(t, manifest.Pages)
+
+	modified := time.Date(2021, 6, 1, 8, 30, 0, 0, time.UTC)
+	assert.True(t, manifest.Changed("/posts/1", modified))
+
+	manifest.Pages["/posts/1"] = &Page{Modified: modified, Related: []string{"/accounts/1"}}
+	manifest.Sitemaps = 3
+	require.NoError(t, manifest.Save(dir))
+
+	manifest, err = LoadManifest(dir)
+	require.NoError(t, err)
+	assert.False(t, manifest.Changed("/posts/1", modified.In(time.FixedZone("WIB", 7*60*60))))
+	assert.True(t, manifest.Changed("/posts/1", modified.Add(time.Second)))
+	assert.Equal(t, []string{"/accounts/1"}, manifest.Pages["/posts/1"].Related)
+	assert.Equal(t, 3, manifest.Sitemaps)
+}
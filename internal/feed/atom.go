@@ -0,0 +1,103 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const AtomContentType = "application/atom+xml; charset=utf-8"
+
+type atom struct {
+	XMLName  xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string       `xml:"title"`
+	Subtitle string       `xml:"subtitle,omitempty"`
+	ID       string       `xml:"id"`
+	Links    []atomLink   `xml:"link"`
+	Updated  string       `xml:"updated"`
+	Entries  []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length int64  `xml:"length,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary"`
+	Content    *atomText      `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom renders the feed as an Atom 1.0 document, entries without an update time use their publish time.
+func Atom(f *Feed) ([]byte, error) {
+	doc := atom{
+		Title:    f.Title,
+		Subtitle: f.Description,
+		ID:       f.Link,
+		Links: []atomLink{
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+			{Href: f.FeedLink, Rel: "self", Type: "application/atom+xml"},
+		},
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Entries: make([]*atomEntry, len(f.Items)),
+	}
+
+	for i, item := range f.Items {
+		updated := item.Updated
+		if updated.IsZero() {
+			updated = item.Published
+		}
+
+		entry := &atomEntry{
+			Title:     item.Title,
+			ID:        item.ID,
+			Links:     []atomLink{{Href: item.Link, Rel: "alternate", Type: "text/html"}},
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   updated.UTC().Format(time.RFC3339),
+		}
+		for _, author := range item.Authors {
+			entry.Authors = append(entry.Authors, atomPerson{Name: author})
+		}
+		for _, attachment := range item.Attachments {
+			entry.Links = append(entry.Links, atomLink{
+				Href:   attachment.URL,
+				Rel:    "enclosure",
+				Type:   attachment.ContentType,
+				Length: attachment.Size,
+			})
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		if item.Summary != "" {
+			entry.Summary = &atomText{Type: "html", Value: item.Summary}
+		}
+		if item.Content != "" {
+			entry.Content = &atomText{Type: "html", Value: item.Content}
+		}
+		doc.Entries[i] = entry
+	}
+
+	return marshal(doc)
+}
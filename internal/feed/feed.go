@@ -0,0 +1,37 @@
+package feed
+
+import "time"
+
+// Feed is a format agnostic syndication feed, Summary and Content of the items hold HTML.
+type Feed struct {
+	Title       string
+	Description string
+	Link        string
+	FeedLink    string
+	NextLink    string
+	Updated     time.Time
+
+	Items []*Item
+}
+
+type Item struct {
+	ID         string
+	Title      string
+	Link       string
+	Authors    []string
+	Categories []string
+	Summary    string
+	Content    string
+	Image      string
+	Published  time.Time
+	Updated    time.Time
+
+	Attachments []*Attachment
+}
+
+type Attachment struct {
+	URL         string
+	ContentType string
+	Title       string
+	Size        int64
+}
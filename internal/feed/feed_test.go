@@ -0,0 +1,189 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2021, 6, 1, 8, 30, 0, 0, time.UTC)
+	return &Feed{
+		Title:       "Blog",
+		Description: "Notes & thoughts",
+		Link:        "https://example.com",
+		FeedLink:    "https://example.com/v1/feed.rss",
+		Updated:     published.Add(time.Hour),
+		Items: []*Item{
+			{
+				ID:         "https://example.com/posts/1",
+				Title:      "Hello <world>",
+				Link:       "https://example.com/posts/1",
+				Authors:    []string{"Jane", "John"},
+				Categories: []string{"go", "web"},
+				Summary:    "<p>Short</p>",
+				Content:    "<p>Short and long</p>",
+				Image:      "https://example.com/media/cover.jpg",
+				Published:  published,
+				Updated:    published.Add(time.Hour),
+				Attachments: []*Attachment{
+					{URL: "https://example.com/media/cover.jpg", ContentType: "image/jpeg", Title: "Cover", Size: 2048},
+				},
+			},
+			{
+				ID:        "https://example.com/posts/2",
+				Title:     "Draft notes",
+				Link:      "https://example.com/posts/2",
+				Authors:   []string{"John"},
+				Summary:   "Only a summary &amp; more",
+				Published: published.Add(-24 * time.Hour),
+			},
+		},
+	}
+}
+
+func TestRSS(t *testing.T) {
+	data, err := RSS(testFeed())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<atom:link href="https://example.com/v1/feed.rss" rel="self" type="application/rss+xml"></atom:link>`)
+
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			Description   string `xml:"description"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title       string   `xml:"title"`
+				GUID        string   `xml:"guid"`
+				Creators    []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				Categories  []string `xml:"category"`
+				Description string   `xml:"description"`
+				Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+				Enclosure   *struct {
+					URL    string `xml:"url,attr"`
+					Length int64  `xml:"length,attr"`
+				} `xml:"enclosure"`
+				PubDate string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal(data, &doc))
+
+	assert.Equal(t, "2.0", doc.Version)
+	assert.Equal(t, "Notes & thoughts", doc.Channel.Description)
+	assert.Equal(t, "Tue, 01 Jun 2021 09:30:00 +0000", doc.Channel.LastBuildDate)
+	require.Len(t, doc.Channel.Items, 2)
+
+	item := doc.Channel.Items[0]
+	assert.Equal(t, "Hello <world>", item.Title)
+	assert.Equal(t, "https://example.com/posts/1", item.GUID)
+	assert.Equal(t, []string{"Jane", "John"}, item.Creators)
+	assert.Equal(t, []string{"go", "web"}, item.Categories)
+	assert.Equal(t, "<p>Short</p>", item.Description)
+	assert.Equal(t, "<p>Short and long</p>", item.Content)
+	assert.Equal(t, "Tue, 01 Jun 2021 08:30:00 +0000", item.PubDate)
+	require.NotNil(t, item.Enclosure)
+	assert.Equal(t, "https://example.com/media/cover.jpg", item.Enclosure.URL)
+	assert.Equal(t, int64(2048), item.Enclosure.Length)
+
+	assert.Empty(t, doc.Channel.Items[1].Content)
+	assert.Nil(t, doc.Channel.Items[1].Enclosure)
+}
+
+func TestAtom(t *testing.T) {
+	data, err := Atom(testFeed())
+	require.NoError(t, err)
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Title   string   `xml:"title"`
+		Updated string   `xml:"updated"`
+		Links   []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID      string   `xml:"id"`
+			Updated string   `xml:"updated"`
+			Authors []string `xml:"author>name"`
+			Summary *struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"summary"`
+			Content *struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(data, &doc))
+
+	assert.Equal(t, "Blog", doc.Title)
+	assert.Equal(t, "2021-06-01T09:30:00Z", doc.Updated)
+	require.Len(t, doc.Links, 2)
+	assert.Equal(t, "self", doc.Links[1].Rel)
+	assert.Equal(t, "https://example.com/v1/feed.rss", doc.Links[1].Href)
+	require.Len(t, doc.Entries, 2)
+
+	entry := doc.Entries[0]
+	assert.Equal(t, "https://example.com/posts/1", entry.ID)
+	assert.Equal(t, []string{"Jane", "John"}, entry.Authors)
+	require.NotNil(t, entry.Content)
+	assert.Equal(t, "html", entry.Content.Type)
+	assert.Equal(t, "<p>Short and long</p>", entry.Content.Value)
+
+	// entries that were never edited fall back to their publish time
+	assert.Equal(t, "2021-05-31T08:30:00Z", doc.Entries[1].Updated)
+	assert.Nil(t, doc.Entries[1].Content)
+	require.NotNil(t, doc.Entries[1].Summary)
+}
+
+func TestJSON(t *testing.T) {
+	f := testFeed()
+	f.NextLink = "https://example.com/v1/feed.json?limit=2&offset=2"
+
+	data, err := JSON(f)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", doc["version"])
+	assert.Equal(t, "https://example.com", doc["home_page_url"])
+	assert.Equal(t, "https://example.com/v1/feed.json?limit=2&offset=2", doc["next_url"])
+
+	items := doc["items"].([]interface{})
+	require.Len(t, items, 2)
+
+	item := items[0].(map[string]interface{})
+	assert.Equal(t, "https://example.com/posts/1", item["id"])
+	assert.Equal(t, "<p>Short and long</p>", item["content_html"])
+	assert.Equal(t, "2021-06-01T08:30:00Z", item["date_published"])
+	assert.Equal(t, "2021-06-01T09:30:00Z", item["date_modified"])
+	assert.Equal(t, []interface{}{"go", "web"}, item["tags"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "Jane"},
+		map[string]interface{}{"name": "John"},
+	}, item["authors"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"url":           "https://example.com/media/cover.jpg",
+			"mime_type":     "image/jpeg",
+			"title":         "Cover",
+			"size_in_bytes": float64(2048),
+		},
+	}, item["attachments"])
+
+	// summary only items carry the summary as content and leave out what they do not have
+	item = items[1].(map[string]interface{})
+	assert.Equal(t, "Only a summary &amp; more", item["content_html"])
+	assert.NotContains(t, item, "summary")
+	assert.NotContains(t, item, "tags")
+	assert.NotContains(t, item, "date_modified")
+	assert.NotContains(t, item, "attachments")
+}
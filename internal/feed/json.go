@@ -0,0 +1,95 @@
+package feed
+
+import (
+	"encoding/json"
+	"html"
+	"time"
+)
+
+const JSONContentType = "application/feed+json; charset=utf-8"
+
+type jsonFeed struct {
+	Version     string      `json:"version"`
+	Title       string      `json:"title"`
+	HomePageURL string      `json:"home_page_url,omitempty"`
+	FeedURL     string      `json:"feed_url,omitempty"`
+	Description string      `json:"description,omitempty"`
+	NextURL     string      `json:"next_url,omitempty"`
+	Items       []*jsonItem `json:"items"`
+}
+
+type jsonItem struct {
+	ID            string            `json:"id"`
+	URL           string            `json:"url,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	ContentHTML   string            `json:"content_html,omitempty"`
+	Summary       string            `json:"summary,omitempty"`
+	Image         string            `json:"image,omitempty"`
+	DatePublished string            `json:"date_published,omitempty"`
+	DateModified  string            `json:"date_modified,omitempty"`
+	Authors       []*jsonAuthor     `json:"authors,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
+	Attachments   []*jsonAttachment `json:"attachments,omitempty"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonAttachment struct {
+	URL         string `json:"url"`
+	MimeType    string `json:"mime_type"`
+	Title       string `json:"title,omitempty"`
+	SizeInBytes int64  `json:"size_in_bytes,omitempty"`
+}
+
+// JSON renders the feed as a JSON Feed 1.1 document, the summary of the items is turned back into plain text
+// as the format expects.
+func JSON(f *Feed) ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.FeedLink,
+		Description: f.Description,
+		NextURL:     f.NextLink,
+		Items:       make([]*jsonItem, len(f.Items)),
+	}
+
+	for i, item := range f.Items {
+		res := &jsonItem{
+			ID:          item.ID,
+			URL:         item.Link,
+			Title:       item.Title,
+			ContentHTML: item.Content,
+			Image:       item.Image,
+			Tags:        item.Categories,
+		}
+		if item.Content == "" {
+			// JSON Feed requires some content, the summary stands in when only summaries are published
+			res.ContentHTML = item.Summary
+		} else {
+			res.Summary = html.UnescapeString(item.Summary)
+		}
+		if !item.Published.IsZero() {
+			res.DatePublished = item.Published.UTC().Format(time.RFC3339)
+		}
+		if !item.Updated.IsZero() {
+			res.DateModified = item.Updated.UTC().Format(time.RFC3339)
+		}
+		for _, author := range item.Authors {
+			res.Authors = append(res.Authors, &jsonAuthor{Name: author})
+		}
+		for _, attachment := range item.Attachments {
+			res.Attachments = append(res.Attachments, &jsonAttachment{
+				URL:         attachment.URL,
+				MimeType:    attachment.ContentType,
+				Title:       attachment.Title,
+				SizeInBytes: attachment.Size,
+			})
+		}
+		doc.Items[i] = res
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
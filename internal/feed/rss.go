@@ -0,0 +1,98 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const RSSContentType = "application/rss+xml; charset=utf-8"
+
+type rss struct {
+	XMLName      xml.Name   `xml:"rss"`
+	Version      string     `xml:"version,attr"`
+	AtomNS       string     `xml:"xmlns:atom,attr"`
+	ContentNS    string     `xml:"xmlns:content,attr"`
+	DublinCoreNS string     `xml:"xmlns:dc,attr"`
+	Channel      rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	AtomLink      atomLink   `xml:"atom:link"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	Creators    []string      `xml:"dc:creator"`
+	Categories  []string      `xml:"category"`
+	Description string        `xml:"description"`
+	Content     string        `xml:"content:encoded,omitempty"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+	PubDate     string        `xml:"pubDate"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS renders the feed as an RSS 2.0 document, full content goes into content:encoded next to the summary.
+func RSS(f *Feed) ([]byte, error) {
+	doc := rss{
+		Version:      "2.0",
+		AtomNS:       "http://www.w3.org/2005/Atom",
+		ContentNS:    "http://purl.org/rss/1.0/modules/content/",
+		DublinCoreNS: "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+			AtomLink:    atomLink{Href: f.FeedLink, Rel: "self", Type: "application/rss+xml"},
+			Items:       make([]*rssItem, len(f.Items)),
+		},
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for i, item := range f.Items {
+		res := &rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{IsPermaLink: item.ID == item.Link, Value: item.ID},
+			Creators:    item.Authors,
+			Categories:  item.Categories,
+			Description: item.Summary,
+			Content:     item.Content,
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+		}
+		// RSS allows a single enclosure per item
+		if len(item.Attachments) > 0 {
+			attachment := item.Attachments[0]
+			res.Enclosure = &rssEnclosure{URL: attachment.URL, Length: attachment.Size, Type: attachment.ContentType}
+		}
+		doc.Channel.Items[i] = res
+	}
+
+	return marshal(doc)
+}
+
+func marshal(doc interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,231 @@
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	toml "github.com/pelletier/go-toml"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Formats of the front matter, told apart by their delimiter line.
+const (
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+var delimiters = map[string]string{
+	FormatYAML: "---",
+	FormatTOML: "+++",
+}
+
+// dateLayouts are tried in order for dates written as strings.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+var (
+	ErrMissing = errors.New("frontmatter: document does not start with front matter")
+	ErrFormat  = errors.New("frontmatter: unsupported format")
+	ErrDate    = errors.New("frontmatter: date is not a valid date")
+)
+
+// Meta is the front matter of a post. Author holds the email of the author and Draft marks posts which
+// are not published.
+type Meta struct {
+	Title  string
+	Date   time.Time
+	Slug   string
+	Tags   []string
+	Author string
+	Draft  bool
+}
+
+// Document is a Markdown file with front matter.
+type Document struct {
+	Format string
+	Meta   Meta
+	Body   string
+}
+
+// fields is how Meta is written, dates are kept apart since they may be a native date or a string.
+type fields struct {
+	Title  string      `yaml:"title"`
+	Date   interface{} `yaml:"date,omitempty"`
+	Slug   string      `yaml:"slug,omitempty"`
+	Tags   []string    `yaml:"tags,omitempty"`
+	Author string      `yaml:"author,omitempty"`
+	Draft  bool        `yaml:"draft,omitempty"`
+}
+
+// Parse splits a document into its front matter and body, YAML front matter is delimited by --- lines
+// and TOML front matter by +++ lines.
+func Parse(data []byte) (*Document, error) {
+	text := strings.ReplaceAll(strings.TrimPrefix(string(data), "\ufeff"), "\r\n", "\n")
+
+	for format, delimiter := range delimiters {
+		if !strings.HasPrefix(text, delimiter+"\n") {
+			continue
+		}
+
+		rest := text[len(delimiter)+1:]
+		end := strings.Index("\n"+rest, "\n"+delimiter+"\n")
+		if end < 0 {
+			if !strings.HasSuffix("\n"+rest, "\n"+delimiter) {
+				return nil, ErrMissing
+			}
+			end = len(rest) - len(delimiter)
+		}
+
+		head := rest[:end]
+		body := ""
+		if end+len(delimiter)+1 < len(rest) {
+			body = rest[end+len(delimiter)+1:]
+		}
+
+		var f fields
+		var err error
+		if format == FormatYAML {
+			err = yaml.Unmarshal([]byte(head), &f)
+		} else {
+			f, err = decodeTOML(head)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("frontmatter: %v", err)
+		}
+
+		date, err := parseDate(f.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Document{
+			Format: format,
+			Meta: Meta{
+				Title:  f.Title,
+				Date:   date,
+				Slug:   f.Slug,
+				Tags:   f.Tags,
+				Author: f.Author,
+				Draft:  f.Draft,
+			},
+			Body: strings.TrimSpace(body),
+		}, nil
+	}
+	return nil, ErrMissing
+}
+
+// Marshal writes the document back in its format, dates are written in RFC 3339 with as many
+// fractional seconds as needed to read them back unchanged.
+func (d *Document) Marshal() ([]byte, error) {
+	delimiter, found := delimiters[d.Format]
+	if !found {
+		return nil, ErrFormat
+	}
+
+	f := fields{
+		Title:  d.Meta.Title,
+		Slug:   d.Meta.Slug,
+		Tags:   d.Meta.Tags,
+		Author: d.Meta.Author,
+		Draft:  d.Meta.Draft,
+	}
+	if !d.Meta.Date.IsZero() {
+		f.Date = d.Meta.Date.Format(time.RFC3339Nano)
+	}
+
+	var head []byte
+	var err error
+	if d.Format == FormatYAML {
+		head, err = yaml.Marshal(f)
+	} else {
+		head, err = encodeTOML(f)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(delimiter + "\n")
+	buf.Write(head)
+	buf.WriteString(delimiter + "\n\n")
+	buf.WriteString(strings.TrimSpace(d.Body))
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+func parseDate(value interface{}) (time.Time, error) {
+	switch date := value.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return date, nil
+	case string:
+		for _, layout := range dateLayouts {
+			t, err := time.Parse(layout, strings.TrimSpace(date))
+			if err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %v", ErrDate, value)
+}
+
+// decodeTOML reads the fields from a TOML tree, values of another type than expected are ignored.
+func decodeTOML(head string) (fields, error) {
+	tree, err := toml.Load(head)
+	if err != nil {
+		return fields{}, err
+	}
+
+	f := fields{Date: tree.Get("date")}
+	f.Title, _ = tree.Get("title").(string)
+	f.Slug, _ = tree.Get("slug").(string)
+	f.Author, _ = tree.Get("author").(string)
+	f.Draft, _ = tree.Get("draft").(bool)
+	if tags, ok := tree.Get("tags").([]interface{}); ok {
+		for _, tag := range tags {
+			if tag, ok := tag.(string); ok {
+				f.Tags = append(f.Tags, tag)
+			}
+		}
+	}
+	return f, nil
+}
+
+func encodeTOML(f fields) ([]byte, error) {
+	values := map[string]interface{}{"title": f.Title}
+	if f.Date != nil {
+		values["date"] = f.Date
+	}
+	if f.Slug != "" {
+		values["slug"] = f.Slug
+	}
+	if len(f.Tags) > 0 {
+		tags := make([]interface{}, len(f.Tags))
+		for i, tag := range f.Tags {
+			tags[i] = tag
+		}
+		values["tags"] = tags
+	}
+	if f.Author != "" {
+		values["author"] = f.Author
+	}
+	if f.Draft {
+		values["draft"] = true
+	}
+
+	tree, err := toml.TreeFromMap(values)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := tree.ToTomlString()
+	return []byte(head), err
+}
@@ -0,0 +1,99 @@
+package frontmatter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		date   time.Time
+	}{
+		{
+			name:   "yaml",
+			data:   "---\ntitle: Hello world\ndate: 2021-06-01T08:30:00Z\nslug: hello-world\ntags: [go, web]\nauthor: jane@example.com\n---\n\nFirst paragraph\n\nSecond\n",
+			format: FormatYAML,
+			date:   time.Date(2021, 6, 1, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:   "yaml with a quoted date",
+			data:   "---\ntitle: Hello world\ndate: \"2021-06-01\"\nslug: hello-world\ntags:\n  - go\n  - web\nauthor: jane@example.com\n---\nFirst paragraph\n\nSecond",
+			format: FormatYAML,
+			date:   time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "toml",
+			data:   "+++\ntitle = \"Hello world\"\ndate = 2021-06-01T08:30:00Z\nslug = \"hello-world\"\ntags = [\"go\", \"web\"]\nauthor = \"jane@example.com\"\n+++\r\n\r\nFirst paragraph\r\n\r\nSecond\r\n",
+			format: FormatTOML,
+			date:   time.Date(2021, 6, 1, 8, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse([]byte(tt.data))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.format, doc.Format)
+			assert.Equal(t, "Hello world", doc.Meta.Title)
+			assert.True(t, tt.date.Equal(doc.Meta.Date), doc.Meta.Date)
+			assert.Equal(t, "hello-world", doc.Meta.Slug)
+			assert.Equal(t, []string{"go", "web"}, doc.Meta.Tags)
+			assert.Equal(t, "jane@example.com", doc.Meta.Author)
+			assert.False(t, doc.Meta.Draft)
+			assert.Equal(t, "First paragraph\n\nSecond", doc.Body)
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse([]byte("# Just Markdown\n"))
+	assert.True(t, errors.Is(err, ErrMissing))
+
+	_, err = Parse([]byte("---\ntitle: Unterminated\n"))
+	assert.True(t, errors.Is(err, ErrMissing))
+
+	_, err = Parse([]byte("---\ntitle: Hello\ndate: someday\n---\nBody"))
+	assert.True(t, errors.Is(err, ErrDate))
+}
+
+func TestMarshal(t *testing.T) {
+	for _, format := range []string{FormatYAML, FormatTOML} {
+		t.Run(format, func(t *testing.T) {
+			doc := &Document{
+				Format: format,
+				Meta: Meta{
+					Title:  "Hello: world",
+					Date:   time.Date(2021, 6, 1, 8, 30, 0, 0, time.UTC),
+					Slug:   "hello-world",
+					Tags:   []string{"go"},
+					Author: "jane@example.com",
+					Draft:  true,
+				},
+				Body: "Body\n",
+			}
+
+			data, err := doc.Marshal()
+			require.NoError(t, err)
+			assert.Contains(t, string(data), "\n\nBody\n")
+
+			parsed, err := Parse(data)
+			require.NoError(t, err)
+			assert.Equal(t, format, parsed.Format)
+			assert.True(t, doc.Meta.Date.Equal(parsed.Meta.Date))
+			parsed.Meta.Date = doc.Meta.Date
+			assert.Equal(t, doc.Meta, parsed.Meta)
+			assert.Equal(t, "Body", parsed.Body)
+		})
+	}
+
+	_, err := (&Document{Format: "json"}).Marshal()
+	assert.True(t, errors.Is(err, ErrFormat))
+}
@@ -0,0 +1,163 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/anonychun/go-blog-api/internal/constant"
+)
+
+var (
+	ErrDecode   = errors.New("imaging: image could not be decoded")
+	ErrTooLarge = errors.New("imaging: image dimensions are too large")
+)
+
+// maxPixels guards against decompression bombs, small files that decode into huge bitmaps.
+const maxPixels = 50_000_000
+
+type Options struct {
+	Widths        []int
+	ThumbnailSize int
+	JpegQuality   int
+}
+
+type Image struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+
+	Variants []Variant
+}
+
+type Variant struct {
+	Name        string
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Process decodes a JPEG, PNG or GIF image, strips its metadata and renders the resized and thumbnail variants,
+// widths larger than the image itself are skipped since upscaling only wastes space.
+func Process(data []byte, opts Options) (*Image, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrDecode
+	} else if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrDecode
+	}
+	img := toRGBA(decoded)
+
+	res := &Image{ContentType: "image/" + format}
+	switch format {
+	case "jpeg":
+		orientation := jpegOrientation(data)
+		if orientation > 1 {
+			// the orientation lives in the metadata being stripped, so it is applied to the pixels instead
+			img = orient(img, orientation)
+			res.Data, err = encode(img, res.ContentType, opts.JpegQuality)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			res.Data, err = stripJPEG(data)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case "png":
+		res.Data, err = stripPNG(data)
+		if err != nil {
+			return nil, err
+		}
+	case "gif":
+		res.Data = data
+	default:
+		return nil, ErrDecode
+	}
+	res.Width, res.Height = img.Bounds().Dx(), img.Bounds().Dy()
+
+	// only the first frame of an animation is kept for variants, so they are encoded as png
+	variantType := res.ContentType
+	if format == "gif" {
+		variantType = "image/png"
+	}
+
+	for _, width := range opts.Widths {
+		if width <= 0 || width >= res.Width {
+			continue
+		}
+
+		height := res.Height * width / res.Width
+		if height < 1 {
+			height = 1
+		}
+
+		variant, err := newVariant(fmt.Sprintf("w%d", width), Resize(img, width, height), variantType, opts.JpegQuality)
+		if err != nil {
+			return nil, err
+		}
+		res.Variants = append(res.Variants, *variant)
+	}
+
+	if opts.ThumbnailSize > 0 {
+		variant, err := newVariant(constant.MEDIA_VARIANT_THUMBNAIL, Thumbnail(img, opts.ThumbnailSize), variantType, opts.JpegQuality)
+		if err != nil {
+			return nil, err
+		}
+		res.Variants = append(res.Variants, *variant)
+	}
+
+	return res, nil
+}
+
+func newVariant(name string, img *image.RGBA, contentType string, quality int) (*Variant, error) {
+	data, err := encode(img, contentType, quality)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Variant{
+		Name:        name,
+		Data:        data,
+		ContentType: contentType,
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}, nil
+}
+
+func encode(img image.Image, contentType string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch contentType {
+	case "image/jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	default:
+		err = png.Encode(&buf, img)
+	}
+	return buf.Bytes(), err
+}
+
+// toRGBA converts any image into premultiplied RGBA with its origin at zero.
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	return dst
+}
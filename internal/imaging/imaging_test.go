@@ -0,0 +1,187 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 255 / width), uint8(y * 255 / height), 128, 255})
+		}
+	}
+	return img
+}
+
+// exifSegment builds an APP1 segment holding only an orientation tag.
+func exifSegment(orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = append(tiff, 0, 1)
+	tiff = append(tiff, 0x01, 0x12, 0, 3, 0, 0, 0, 1, byte(orientation>>8), byte(orientation), 0, 0)
+	tiff = append(tiff, 0, 0, 0, 0)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xff, jpegAPP1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func testJPEG(t *testing.T, width, height int, orientation uint16) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, testImage(width, height), nil))
+
+	data := buf.Bytes()
+	res := append([]byte{}, data[:2]...)
+	res = append(res, exifSegment(orientation)...)
+	return append(res, data[2:]...)
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(width, height)))
+
+	// insert a text chunk right after IHDR
+	data := buf.Bytes()
+	chunk := make([]byte, 8, 32)
+	text := []byte("Author\x00someone")
+	binary.BigEndian.PutUint32(chunk, uint32(len(text)))
+	copy(chunk[4:], "tEXt")
+	chunk = append(chunk, text...)
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, crc32.ChecksumIEEE(chunk[4:]))
+	chunk = append(chunk, crc...)
+
+	ihdrEnd := 8 + 12 + 13
+	res := append([]byte{}, data[:ihdrEnd]...)
+	res = append(res, chunk...)
+	return append(res, data[ihdrEnd:]...)
+}
+
+func TestProcess(t *testing.T) {
+	opts := Options{Widths: []int{40, 80, 400}, ThumbnailSize: 16, JpegQuality: 80}
+
+	t.Run("jpeg", func(t *testing.T) {
+		data := testJPEG(t, 100, 50, 1)
+		require.Equal(t, 1, jpegOrientation(data))
+
+		res, err := Process(data, opts)
+		require.NoError(t, err)
+		assert.Equal(t, "image/jpeg", res.ContentType)
+		assert.Equal(t, 100, res.Width)
+		assert.Equal(t, 50, res.Height)
+		assert.NotContains(t, string(res.Data), "Exif")
+		assert.Equal(t, len(data)-len(exifSegment(1)), len(res.Data))
+
+		require.Len(t, res.Variants, 3)
+		assert.Equal(t, Variant{Name: "w40", ContentType: "image/jpeg", Width: 40, Height: 20}, withoutData(res.Variants[0]))
+		assert.Equal(t, Variant{Name: "w80", ContentType: "image/jpeg", Width: 80, Height: 40}, withoutData(res.Variants[1]))
+		assert.Equal(t, Variant{Name: constant.MEDIA_VARIANT_THUMBNAIL, ContentType: "image/jpeg", Width: 16, Height: 16}, withoutData(res.Variants[2]))
+
+		for _, variant := range res.Variants {
+			cfg, err := jpeg.DecodeConfig(bytes.NewReader(variant.Data))
+			require.NoError(t, err)
+			assert.Equal(t, variant.Width, cfg.Width)
+		}
+	})
+
+	t.Run("jpeg rotated", func(t *testing.T) {
+		data := testJPEG(t, 100, 50, 6)
+		require.Equal(t, 6, jpegOrientation(data))
+
+		res, err := Process(data, opts)
+		require.NoError(t, err)
+		assert.Equal(t, 50, res.Width)
+		assert.Equal(t, 100, res.Height)
+		assert.NotContains(t, string(res.Data), "Exif")
+
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(res.Data))
+		require.NoError(t, err)
+		assert.Equal(t, 50, cfg.Width)
+		assert.Equal(t, 100, cfg.Height)
+	})
+
+	t.Run("png", func(t *testing.T) {
+		data := testPNG(t, 60, 60)
+
+		res, err := Process(data, opts)
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", res.ContentType)
+		assert.NotContains(t, string(res.Data), "someone")
+		require.Len(t, res.Variants, 2)
+
+		_, err = png.Decode(bytes.NewReader(res.Data))
+		assert.NoError(t, err)
+	})
+
+	t.Run("gif", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, gif.Encode(&buf, testImage(90, 30), nil))
+
+		res, err := Process(buf.Bytes(), opts)
+		require.NoError(t, err)
+		assert.Equal(t, "image/gif", res.ContentType)
+		assert.Equal(t, buf.Bytes(), res.Data)
+		require.Len(t, res.Variants, 3)
+		assert.Equal(t, "image/png", res.Variants[0].ContentType)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := Process([]byte("not an image"), opts)
+		assert.Equal(t, ErrDecode, err)
+	})
+}
+
+func TestOrient(t *testing.T) {
+	img := testImage(3, 2)
+	for orientation := 1; orientation <= 8; orientation++ {
+		res := orient(img, orientation)
+		if orientation >= 5 {
+			assert.Equal(t, image.Rect(0, 0, 2, 3), res.Bounds(), "orientation %d", orientation)
+		} else {
+			assert.Equal(t, image.Rect(0, 0, 3, 2), res.Bounds(), "orientation %d", orientation)
+		}
+	}
+
+	// rotating 90 degrees clockwise moves the top left pixel to the top right
+	assert.Equal(t, img.RGBAAt(0, 0), orient(img, 6).RGBAAt(1, 0))
+	assert.Equal(t, img.RGBAAt(0, 0), orient(img, 8).RGBAAt(0, 2))
+	assert.Equal(t, img.RGBAAt(0, 0), orient(img, 3).RGBAAt(2, 1))
+}
+
+func TestResize(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		c := color.RGBA{0, 0, 0, 255}
+		if x%2 == 0 {
+			c = color.RGBA{200, 100, 50, 255}
+		}
+		img.SetRGBA(x, 0, c)
+		img.SetRGBA(x, 1, c)
+	}
+
+	res := Resize(img, 2, 1)
+	assert.Equal(t, image.Rect(0, 0, 2, 1), res.Bounds())
+	assert.Equal(t, color.RGBA{100, 50, 25, 255}, res.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{100, 50, 25, 255}, res.RGBAAt(1, 0))
+
+	thumbnail := Thumbnail(testImage(30, 10), 50)
+	assert.Equal(t, image.Rect(0, 0, 10, 10), thumbnail.Bounds())
+}
+
+func withoutData(variant Variant) Variant {
+	variant.Data = nil
+	return variant
+}
@@ -0,0 +1,186 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+const (
+	jpegSOS  = 0xda
+	jpegAPP1 = 0xe1
+	jpegIPTC = 0xed
+	jpegCOM  = 0xfe
+)
+
+// jpegSegments calls fn for every marker segment before the image data, stopping when fn returns false.
+func jpegSegments(data []byte, fn func(marker byte, segment []byte) bool) (rest int, err error) {
+	if len(data) < 2 || data[0] != 0xff || data[1] != 0xd8 {
+		return 0, ErrDecode
+	}
+
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xff {
+			return 0, ErrDecode
+		}
+		marker := data[i+1]
+		if marker == 0xff {
+			// fill byte before a marker
+			i++
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 0, ErrDecode
+		}
+		if marker == jpegSOS {
+			return i, nil
+		}
+		if !fn(marker, data[i:i+2+length]) {
+			return i, nil
+		}
+		i += 2 + length
+	}
+	return 0, ErrDecode
+}
+
+// stripJPEG drops the EXIF, XMP, IPTC and comment segments without re-encoding the image.
+func stripJPEG(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(data[:2])
+
+	rest, err := jpegSegments(data, func(marker byte, segment []byte) bool {
+		if marker != jpegAPP1 && marker != jpegIPTC && marker != jpegCOM {
+			buf.Write(segment)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf.Write(data[rest:])
+	return buf.Bytes(), nil
+}
+
+// jpegOrientation reads the EXIF orientation tag, 1 (upright) is returned when it is missing.
+func jpegOrientation(data []byte) int {
+	orientation := 1
+	jpegSegments(data, func(marker byte, segment []byte) bool {
+		payload := segment[4:]
+		if marker != jpegAPP1 || !bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+			return true
+		}
+
+		tiff := payload[6:]
+		if len(tiff) < 8 {
+			return false
+		}
+
+		var order binary.ByteOrder
+		switch string(tiff[:2]) {
+		case "II":
+			order = binary.LittleEndian
+		case "MM":
+			order = binary.BigEndian
+		default:
+			return false
+		}
+
+		ifd := int(order.Uint32(tiff[4:]))
+		if ifd+2 > len(tiff) {
+			return false
+		}
+
+		count := int(order.Uint16(tiff[ifd:]))
+		for n := 0; n < count; n++ {
+			entry := ifd + 2 + n*12
+			if entry+12 > len(tiff) {
+				break
+			}
+			if order.Uint16(tiff[entry:]) == 0x0112 {
+				value := int(order.Uint16(tiff[entry+8:]))
+				if value >= 1 && value <= 8 {
+					orientation = value
+				}
+				break
+			}
+		}
+		return false
+	})
+	return orientation
+}
+
+// orient transforms img so that it displays upright according to an EXIF orientation value.
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = width-1-x, y
+			case 3:
+				dx, dy = width-1-x, height-1-y
+			case 4:
+				dx, dy = x, height-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = height-1-y, x
+			case 7:
+				dx, dy = height-1-y, width-1-x
+			case 8:
+				dx, dy = y, width-1-x
+			default:
+				dx, dy = x, y
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], img.Pix[img.PixOffset(x, y):][:4])
+		}
+	}
+	return dst
+}
+
+// pngMetadataChunks are ancillary chunks that may carry camera, location or authoring details.
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// stripPNG drops the metadata chunks without re-encoding the image.
+func stripPNG(data []byte) ([]byte, error) {
+	const signature = "\x89PNG\r\n\x1a\n"
+	if !bytes.HasPrefix(data, []byte(signature)) {
+		return nil, ErrDecode
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(signature)
+
+	i := len(signature)
+	for i+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, ErrDecode
+		}
+
+		if !pngMetadataChunks[string(data[i+4:i+8])] {
+			buf.Write(data[i:end])
+		}
+		i = end
+	}
+	return buf.Bytes(), nil
+}
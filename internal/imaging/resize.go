@@ -0,0 +1,116 @@
+package imaging
+
+import (
+	"image"
+)
+
+type weight struct {
+	index  int
+	weight float64
+}
+
+// weights computes, for every destination pixel along one axis, how much each source pixel it covers contributes.
+func weights(srcSize, dstSize int) [][]weight {
+	scale := float64(srcSize) / float64(dstSize)
+	res := make([][]weight, dstSize)
+	for i := range res {
+		start := float64(i) * scale
+		end := start + scale
+
+		var total float64
+		for j := int(start); j < srcSize && float64(j) < end; j++ {
+			overlap := min(end, float64(j+1)) - max(start, float64(j))
+			if overlap > 0 {
+				res[i] = append(res[i], weight{j, overlap})
+				total += overlap
+			}
+		}
+		for k := range res[i] {
+			res[i][k].weight /= total
+		}
+	}
+	return res
+}
+
+// Resize scales img to width x height by area averaging, which keeps downscaled images free of aliasing.
+func Resize(img *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := img.Bounds().Dx(), img.Bounds().Dy()
+
+	// horizontal pass into an intermediate buffer, then a vertical pass into the result
+	xWeights := weights(srcWidth, width)
+	tmp := make([]float64, width*srcHeight*4)
+	for y := 0; y < srcHeight; y++ {
+		row := img.Pix[y*img.Stride:]
+		for x, ws := range xWeights {
+			i := (y*width + x) * 4
+			for _, w := range ws {
+				p := w.index * 4
+				tmp[i] += float64(row[p]) * w.weight
+				tmp[i+1] += float64(row[p+1]) * w.weight
+				tmp[i+2] += float64(row[p+2]) * w.weight
+				tmp[i+3] += float64(row[p+3]) * w.weight
+			}
+		}
+	}
+
+	yWeights := weights(srcHeight, height)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y, ws := range yWeights {
+		for x := 0; x < width; x++ {
+			var r, g, b, a float64
+			for _, w := range ws {
+				i := (w.index*width + x) * 4
+				r += tmp[i] * w.weight
+				g += tmp[i+1] * w.weight
+				b += tmp[i+2] * w.weight
+				a += tmp[i+3] * w.weight
+			}
+			p := dst.PixOffset(x, y)
+			dst.Pix[p] = clamp(r)
+			dst.Pix[p+1] = clamp(g)
+			dst.Pix[p+2] = clamp(b)
+			dst.Pix[p+3] = clamp(a)
+		}
+	}
+	return dst
+}
+
+// Thumbnail crops the center square of img and scales it down to size x size.
+func Thumbnail(img *image.RGBA, size int) *image.RGBA {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	side := width
+	if height < side {
+		side = height
+	}
+	if size > side {
+		size = side
+	}
+
+	x, y := (width-side)/2, (height-side)/2
+	square := img.SubImage(image.Rect(x, y, x+side, y+side)).(*image.RGBA)
+	return Resize(toRGBA(square), size, size)
+}
+
+func clamp(v float64) uint8 {
+	v += 0.5
+	if v < 0 {
+		return 0
+	} else if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
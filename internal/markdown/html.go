@@ -0,0 +1,236 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	spaces    = regexp.MustCompile(`[ \t\f\v]+`)
+	blankRuns = regexp.MustCompile(`\n{3,}`)
+	escaped   = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+)
+
+// FromHTML converts an HTML fragment to Markdown. Paragraphs, headings, emphasis, links, images,
+// lists, quotes, code and rules are converted, other elements are reduced to their text. Blank lines
+// in text are kept as paragraph breaks, as editors such as WordPress store paragraphs without tags.
+func FromHTML(fragment string) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	c := &converter{}
+	for _, node := range nodes {
+		c.node(node)
+	}
+	return tidy(c.sb.String()), nil
+}
+
+// tidy drops trailing spaces and collapses the blank lines left between blocks.
+func tidy(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimSpace(blankRuns.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+type converter struct {
+	sb     strings.Builder
+	prefix string
+	pre    bool
+}
+
+func (c *converter) write(s string) {
+	s = strings.ReplaceAll(s, "\n", "\n"+c.prefix)
+	c.sb.WriteString(s)
+}
+
+// block starts a block element on its own paragraph.
+func (c *converter) block() {
+	c.write("\n\n")
+}
+
+func (c *converter) children(n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.node(child)
+	}
+}
+
+func (c *converter) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		c.text(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Figure, atom.Table:
+		c.block()
+		c.children(n)
+		c.block()
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		c.block()
+		c.write(strings.Repeat("#", int(n.Data[1]-'0')) + " ")
+		c.write(strings.TrimSpace(c.inline(n)))
+		c.block()
+	case atom.Br:
+		c.write("\\\n")
+	case atom.Hr:
+		c.block()
+		c.write("---")
+		c.block()
+	case atom.Strong, atom.B:
+		c.wrap(n, "**")
+	case atom.Em, atom.I:
+		c.wrap(n, "_")
+	case atom.Del, atom.S, atom.Strike:
+		c.wrap(n, "~~")
+	case atom.Code:
+		if c.pre {
+			c.children(n)
+		} else {
+			c.write("`" + textContent(n) + "`")
+		}
+	case atom.Pre:
+		c.block()
+		c.write("```\n")
+		c.pre = true
+		c.children(n)
+		c.pre = false
+		c.write("\n```")
+		c.block()
+	case atom.A:
+		text := strings.TrimSpace(c.inline(n))
+		href := attr(n, "href")
+		if href == "" {
+			c.write(text)
+		} else {
+			c.write(fmt.Sprintf("[%s](%s)", text, href))
+		}
+	case atom.Img:
+		c.write(fmt.Sprintf("![%s](%s)", escaped.Replace(attr(n, "alt")), attr(n, "src")))
+	case atom.Blockquote:
+		lines := strings.Split(tidy(c.inline(n)), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		c.block()
+		c.write(strings.Join(lines, "\n"))
+		c.block()
+	case atom.Ul, atom.Ol:
+		c.block()
+		c.list(n)
+		c.block()
+	case atom.Script, atom.Style, atom.Iframe:
+	default:
+		c.children(n)
+	}
+}
+
+// list writes the items of a list, nested lists are indented below their item.
+func (c *converter) list(n *html.Node) {
+	number := 0
+	for item := n.FirstChild; item != nil; item = item.NextSibling {
+		if item.Type != html.ElementNode || item.DataAtom != atom.Li {
+			continue
+		}
+
+		number++
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", number)
+		}
+		if number > 1 {
+			c.write("\n")
+		}
+		c.write(marker)
+
+		prefix := c.prefix
+		c.prefix += strings.Repeat(" ", len(marker))
+		for child := item.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && (child.DataAtom == atom.Ul || child.DataAtom == atom.Ol) {
+				c.write("\n")
+				c.list(child)
+			} else if child.Type == html.ElementNode && child.DataAtom == atom.P {
+				c.children(child)
+			} else {
+				c.node(child)
+			}
+		}
+		c.prefix = prefix
+	}
+}
+
+func (c *converter) wrap(n *html.Node, marker string) {
+	text := c.inline(n)
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		c.write(text)
+		return
+	}
+	c.write(marker + trimmed + marker)
+}
+
+// inline renders the children of n on their own, to be wrapped in markers.
+func (c *converter) inline(n *html.Node) string {
+	inner := &converter{pre: c.pre}
+	inner.children(n)
+	return inner.sb.String()
+}
+
+func (c *converter) text(s string) {
+	if c.pre {
+		c.write(s)
+		return
+	}
+
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = spaces.ReplaceAllString(s, " ")
+	if strings.Contains(s, "\n\n") {
+		// a blank line separates paragraphs, single line breaks are only whitespace
+		paragraphs := strings.Split(s, "\n\n")
+		for i, paragraph := range paragraphs {
+			paragraphs[i] = escaped.Replace(strings.ReplaceAll(paragraph, "\n", " "))
+		}
+		c.write(strings.Join(paragraphs, "\n\n"))
+		return
+	}
+	c.write(escaped.Replace(strings.ReplaceAll(s, "\n", " ")))
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
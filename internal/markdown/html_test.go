@@ -0,0 +1,60 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "paragraphs",
+			html: "<p>First <strong>bold</strong> and <em>soft</em></p><p>Second<br>line</p>",
+			want: "First **bold** and _soft_\n\nSecond\\\nline",
+		},
+		{
+			name: "untagged paragraphs",
+			html: "<!-- wp:paragraph -->First line\nwraps here\n\nSecond paragraph",
+			want: "First line wraps here\n\nSecond paragraph",
+		},
+		{
+			name: "headings and links",
+			html: `<h2>Title</h2><p>See <a href="https://example.com">the site</a> <img src="/a.png" alt="A"></p>`,
+			want: "## Title\n\nSee [the site](https://example.com) ![A](/a.png)",
+		},
+		{
+			name: "lists",
+			html: "<ul><li>One</li><li>Two<ol><li>Nested</li></ol></li></ul>",
+			want: "- One\n- Two\n  1. Nested",
+		},
+		{
+			name: "quote",
+			html: "<blockquote><p>Quoted</p><p>Again</p></blockquote>",
+			want: "> Quoted\n>\n> Again",
+		},
+		{
+			name: "code",
+			html: "<p>Run <code>go test</code></p><pre><code>if a &lt; b {\n\treturn\n}</code></pre>",
+			want: "Run `go test`\n\n```\nif a < b {\n\treturn\n}\n```",
+		},
+		{
+			name: "escaping",
+			html: "<p>2 * 3 = [six]</p><script>alert(1)</script>",
+			want: `2 \* 3 = \[six\]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromHTML(tt.html)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
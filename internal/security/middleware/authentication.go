@@ -20,25 +20,54 @@ func JWTVerifier(next http.Handler) http.Handler {
 			return
 		}
 
-		tokenParse, err := jwt.Parse(tokenHeader, func(jwtToken *jwt.Token) (interface{}, error) {
-			if jwtToken.Method != jwt.SigningMethodHS256 {
-				return nil, constant.ErrUnauthorized
-			}
-			return []byte(config.Cfg().JwtSecretKey), nil
-		})
-
-		if err != nil || !tokenParse.Valid {
+		ctx, err := withClaims(r.Context(), tokenHeader)
+		if err != nil {
 			web.MarshalError(w, http.StatusUnauthorized, constant.ErrUnauthorized)
 			return
 		}
 
-		claims := tokenParse.Claims.(jwt.MapClaims)
-		claimsID, err := strconv.ParseInt(fmt.Sprint(claims["id"]), 10, 64)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// JWTOptional attaches the token claims to the request context when a valid token is sent,
+// anonymous requests and requests with an invalid token are passed through untouched.
+func JWTOptional(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenHeader := r.Header.Get(constant.API_KEY_HEADER)
+		if tokenHeader == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, err := withClaims(r.Context(), tokenHeader)
 		if err != nil {
-			web.MarshalError(w, http.StatusUnauthorized, constant.ErrUnauthorized)
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsIDKey, claimsID)))
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+func withClaims(ctx context.Context, tokenHeader string) (context.Context, error) {
+	tokenParse, err := jwt.Parse(tokenHeader, func(jwtToken *jwt.Token) (interface{}, error) {
+		if jwtToken.Method != jwt.SigningMethodHS256 {
+			return nil, constant.ErrUnauthorized
+		}
+		return []byte(config.Cfg().JwtSecretKey), nil
+	})
+
+	if err != nil || !tokenParse.Valid {
+		return nil, constant.ErrUnauthorized
+	}
+
+	claims := tokenParse.Claims.(jwt.MapClaims)
+	claimsID, err := strconv.ParseInt(fmt.Sprint(claims["id"]), 10, 64)
+	if err != nil {
+		return nil, constant.ErrUnauthorized
+	}
+
+	claimsRole, _ := claims["role"].(string)
+	return WithClaims(ctx, claimsID, claimsRole), nil
+}
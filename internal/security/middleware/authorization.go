@@ -2,18 +2,39 @@ package middleware
 
 import (
 	"context"
+
+	"github.com/anonychun/go-blog-api/internal/constant"
 )
 
 type key string
 
-const claimsIDKey = key("id")
+const (
+	claimsIDKey   = key("id")
+	claimsRoleKey = key("role")
+)
+
+// WithClaims attaches claims to ctx without a token, for trusted callers such as command line tools.
+func WithClaims(ctx context.Context, id int64, role string) context.Context {
+	ctx = context.WithValue(ctx, claimsIDKey, id)
+	return context.WithValue(ctx, claimsRoleKey, role)
+}
 
 func GetClaimsID(ctx context.Context) (int64, bool) {
 	claimsID, valid := ctx.Value(claimsIDKey).(int64)
 	return claimsID, valid
 }
 
+func GetClaimsRole(ctx context.Context) (string, bool) {
+	claimsRole, valid := ctx.Value(claimsRoleKey).(string)
+	return claimsRole, valid
+}
+
 func IsMe(ctx context.Context, id int64) bool {
 	claimsID, valid := GetClaimsID(ctx)
 	return valid && claimsID == id
 }
+
+func IsAdmin(ctx context.Context) bool {
+	claimsRole, valid := GetClaimsRole(ctx)
+	return valid && claimsRole == constant.ROLE_ADMIN
+}
@@ -0,0 +1,124 @@
+package outbound
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// MaxRedirects is the number of redirects followed before a request fails.
+const MaxRedirects = 5
+
+var (
+	ErrAddress   = errors.New("outbound: address is not public")
+	ErrURL       = errors.New("outbound: url is not allowed")
+	ErrRedirects = errors.New("outbound: too many redirects")
+)
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// reserved holds the ranges which are not reachable on the public internet, besides the loopback,
+// link-local, multicast and unspecified addresses net.IP already tells apart.
+var reserved = parseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"240.0.0.0/4",
+	"64:ff9b::/96",
+	"fc00::/7",
+)
+
+// NewClient returns an HTTP client for URLs chosen by remote parties. It only connects to public
+// addresses, checked on the resolved address so DNS cannot point it inside the network, and only to
+// the given schemes on their default port, redirects included.
+func NewClient(timeout time.Duration, schemes ...string) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be the address checked instead of the destination
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	allowed := make(map[string]bool, len(schemes))
+	for _, scheme := range schemes {
+		allowed[scheme] = true
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &guard{transport, allowed},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= MaxRedirects {
+				return ErrRedirects
+			}
+			return nil
+		},
+	}
+}
+
+// IsPublic reports whether ip is reachable on the public internet.
+func IsPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+
+	for _, network := range reserved {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+type guard struct {
+	transport http.RoundTripper
+	schemes   map[string]bool
+}
+
+func (g *guard) RoundTrip(req *http.Request) (*http.Response, error) {
+	port := req.URL.Port()
+	if !g.schemes[req.URL.Scheme] || (port != "" && port != defaultPorts[req.URL.Scheme]) {
+		return nil, fmt.Errorf("%w: %s", ErrURL, req.URL.Redacted())
+	}
+	return g.transport.RoundTrip(req)
+}
+
+func control(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrAddress, host)
+	}
+	return nil
+}
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
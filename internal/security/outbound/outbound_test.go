@@ -0,0 +1,91 @@
+package outbound
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.20.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.public, IsPublic(net.ParseIP(tt.ip)), tt.ip)
+	}
+}
+
+func TestControl(t *testing.T) {
+	assert.NoError(t, control("tcp4", "93.184.216.34:443", nil))
+	assert.True(t, errors.Is(control("tcp4", "127.0.0.1:443", nil), ErrAddress))
+	assert.True(t, errors.Is(control("tcp6", "[::1]:443", nil), ErrAddress))
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestGuard(t *testing.T) {
+	g := &guard{
+		transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+		schemes: map[string]bool{"https": true},
+	}
+
+	for _, url := range []string{"https://example.com/actor", "https://example.com:443/actor"} {
+		_, err := g.RoundTrip(httptest.NewRequest(http.MethodGet, url, nil))
+		assert.NoError(t, err, url)
+	}
+
+	for _, url := range []string{"http://example.com/actor", "https://example.com:8443/actor", "ftp://example.com/actor"} {
+		_, err := g.RoundTrip(httptest.NewRequest(http.MethodGet, url, nil))
+		assert.True(t, errors.Is(err, ErrURL), url)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// the test server listens on a loopback address and another port
+	_, err := NewClient(time.Second, "http").Get(server.URL)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrURL))
+
+	redirects := 0
+	client := NewClient(time.Second, "http")
+	client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		redirects++
+		header := http.Header{"Location": {"/again"}}
+		return &http.Response{StatusCode: http.StatusFound, Header: header, Body: http.NoBody, Request: req}, nil
+	})
+	_, err = client.Get("http://example.com/")
+	assert.True(t, errors.Is(err, ErrRedirects))
+	assert.Equal(t, MaxRedirects, redirects)
+}
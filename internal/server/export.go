@@ -0,0 +1,310 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	"github.com/anonychun/go-blog-api/internal/export"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/storage"
+	"github.com/anonychun/go-blog-api/internal/theme"
+	"github.com/go-chi/chi"
+	chimiddleware "github.com/go-chi/chi/middleware"
+)
+
+// Export renders the published posts, the author pages, the feeds and the sitemap to dir as static files.
+// Posts are only rendered again when their updated_at moved since the previous export, unless full is set.
+func Export(dir string, full bool) error {
+	if config.Cfg().ThemePath == "" {
+		return errors.New("export requires THEME_PATH to be set")
+	}
+
+	postgresClient, err := postgres.NewClient()
+	if err != nil {
+		return err
+	}
+	defer postgresClient.Close()
+
+	redisClient, err := redis.NewClient()
+	if err != nil {
+		return err
+	}
+	defer redisClient.Close()
+
+	mediaStorage, err := storage.NewStorage()
+	if err != nil {
+		return err
+	}
+
+	siteTheme, err := theme.Load(config.Cfg().ThemePath, false)
+	if err != nil {
+		return err
+	}
+
+	// the previous manifest is needed even with full, it lists the pages of posts which are gone
+	manifest, err := export.LoadManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	posts, err := listPublishedPosts(context.Background(), newPostService(postgresClient, redisClient, mediaStorage))
+	if err != nil {
+		return err
+	}
+
+	// the pages are rendered in-process, without the rate limit and request logging of the public router
+	router := chi.NewRouter()
+	router.Use(chimiddleware.Recoverer)
+	mountRoutes(router, postgresClient, redisClient, mediaStorage, siteTheme)
+
+	exporter := export.New(router, dir)
+	stale := make(map[string]bool)
+	authors := make(map[string]bool)
+	exported := make(map[string]bool)
+	written := 0
+	for _, post := range posts {
+		path := fmt.Sprintf("/posts/%d", post.ID)
+		related := authorPaths(post)
+		exported[path] = true
+		for _, author := range related {
+			authors[author] = true
+		}
+		if !full && !manifest.Changed(path, postModified(post)) {
+			continue
+		}
+
+		err = exporter.Write(path)
+		if err != nil {
+			return err
+		}
+		if page, found := manifest.Pages[path]; found {
+			markStale(stale, page.Related)
+		}
+		markStale(stale, related)
+		manifest.Pages[path] = &export.Page{Modified: postModified(post), Related: related}
+		written++
+	}
+
+	removed := 0
+	for path, page := range manifest.Pages {
+		if exported[path] {
+			continue
+		}
+
+		err = exporter.Remove(path)
+		if err != nil {
+			return err
+		}
+		markStale(stale, page.Related)
+		delete(manifest.Pages, path)
+		removed++
+	}
+
+	if full || written > 0 || removed > 0 {
+		err = exportListing(exporter, "/")
+		if err != nil {
+			return err
+		}
+
+		for _, path := range sortedPaths(stale) {
+			if !authors[path] {
+				err = removeAuthor(exporter, path)
+			} else {
+				err = exportAuthor(exporter, path)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, path := range []string{"/v1/feed.rss", "/v1/feed.atom", "/v1/feed.json"} {
+			err = exporter.Write(path)
+			if err != nil {
+				return err
+			}
+		}
+
+		manifest.Sitemaps, err = exportSitemap(exporter, manifest.Sitemaps)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = exporter.CopyDir(siteTheme.StaticDir(), "/static")
+	if err != nil {
+		return err
+	}
+
+	err = manifest.Save(dir)
+	if err != nil {
+		return err
+	}
+
+	logger.Log().Info().Msgf("exported %d posts to %s, %d rendered and %d removed", len(posts), dir, written, removed)
+	return nil
+}
+
+func newPostService(postgresClient postgres.Client, redisClient redis.Client, mediaStorage storage.Storage) service.PostService {
+	return service.NewPostService(
+		repository.NewPostRepository(postgresClient, redisClient),
+		repository.NewCategoryRepository(postgresClient, redisClient),
+		repository.NewSeriesRepository(postgresClient),
+		repository.NewReactionRepository(postgresClient, redisClient),
+		repository.NewViewRepository(postgresClient, redisClient),
+		repository.NewTrendingRepository(postgresClient, redisClient),
+		repository.NewMediaRepository(postgresClient, redisClient),
+		repository.NewPostAuthorRepository(postgresClient, redisClient),
+		repository.NewPostReviewRepository(postgresClient, redisClient),
+		repository.NewAccountRepository(postgresClient, redisClient),
+		repository.NewActivityPubRepository(postgresClient),
+		repository.NewWebmentionRepository(postgresClient),
+		mediaStorage)
+}
+
+func listPublishedPosts(ctx context.Context, postService service.PostService) ([]*model.PostResponse, error) {
+	limit := config.Cfg().PaginationLimit
+	var posts []*model.PostResponse
+	for offset := 0; ; offset += limit {
+		page, err := postService.List(ctx, model.PostListRequest{
+			Limit:    limit,
+			Offset:   offset,
+			TagMatch: constant.TAG_MATCH_ANY,
+			Status:   constant.POST_STATUS_PUBLISHED,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		posts = append(posts, page...)
+		if len(page) < limit {
+			return posts, nil
+		}
+	}
+}
+
+// postModified is the time the post was last changed, posts which were never updated fall back to the
+// time they were published.
+func postModified(post *model.PostResponse) time.Time {
+	if post.UpdatedAt != nil {
+		return *post.UpdatedAt
+	} else if post.PublishedAt != nil {
+		return *post.PublishedAt
+	}
+	return post.CreatedAt
+}
+
+// authorPaths lists the author pages the post shows up on.
+func authorPaths(post *model.PostResponse) []string {
+	paths := []string{fmt.Sprintf("/accounts/%d", post.AccountID)}
+	for _, author := range post.Authors {
+		path := fmt.Sprintf("/accounts/%d", author.AccountID)
+		if author.Role != constant.POST_AUTHOR_REVIEWER && path != paths[0] {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func markStale(stale map[string]bool, paths []string) {
+	for _, path := range paths {
+		stale[path] = true
+	}
+}
+
+func sortedPaths(paths map[string]bool) []string {
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// exportListing writes every page of a paginated listing, pages left over from a longer listing are
+// removed first.
+func exportListing(exporter *export.Exporter, path string) error {
+	base := strings.TrimSuffix(path, "/")
+	err := exporter.Remove(base + "/page")
+	if err != nil {
+		return err
+	}
+
+	err = exporter.Write(path)
+	if err != nil {
+		return err
+	}
+
+	for page := 2; ; page++ {
+		err = exporter.Write(fmt.Sprintf("%s/page/%d", base, page))
+		if errors.Is(err, export.ErrNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func exportAuthor(exporter *export.Exporter, path string) error {
+	err := exportListing(exporter, path)
+	if err != nil {
+		return err
+	}
+
+	for _, feed := range []string{"/feed.rss", "/feed.atom"} {
+		err = exporter.Write("/v1" + path + feed)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeAuthor removes the author page of an account which has no published posts anymore.
+func removeAuthor(exporter *export.Exporter, path string) error {
+	for _, page := range []string{path, path + "/page", "/v1" + path + "/feed.rss", "/v1" + path + "/feed.atom"} {
+		err := exporter.Remove(page)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportSitemap writes the sitemap index and the sitemap pages it lists, the pages left over from the
+// previous export, which had previous pages, are removed. It returns the number of pages written.
+func exportSitemap(exporter *export.Exporter, previous int) (int, error) {
+	err := exporter.Write("/sitemap.xml")
+	if err != nil {
+		return 0, err
+	}
+
+	pages := 0
+	for page := 1; ; page++ {
+		err = exporter.Write(fmt.Sprintf("/sitemap-%d.xml", page))
+		if errors.Is(err, export.ErrNotFound) {
+			break
+		} else if err != nil {
+			return 0, err
+		}
+		pages = page
+	}
+
+	for page := pages + 1; page <= previous+1; page++ {
+		err = exporter.Remove(fmt.Sprintf("/sitemap-%d.xml", page))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return pages, nil
+}
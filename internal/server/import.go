@@ -0,0 +1,184 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anonychun/go-blog-api/internal/app/model"
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/constant"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/security/middleware"
+)
+
+// ImportWordPress imports the WXR export at path, see runImport for how dryRun and yes are handled.
+func ImportWordPress(path string, dryRun bool, yes bool) error {
+	postgresClient, err := postgres.NewClient()
+	if err != nil {
+		return err
+	}
+	defer postgresClient.Close()
+
+	redisClient, err := redis.NewClient()
+	if err != nil {
+		return err
+	}
+	defer redisClient.Close()
+
+	importService := service.NewImportService(repository.NewImportRepository(postgresClient, redisClient))
+	// the command line is trusted like an administrator
+	ctx := middleware.WithClaims(context.Background(), 0, constant.ROLE_ADMIN)
+
+	return runImport(dryRun, yes, "imported wordpress export", func(dry bool) (*model.ImportResponse, error) {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		return importService.WordPress(ctx, model.WordPressImportRequest{File: file, DryRun: dry})
+	})
+}
+
+// runImport reports what an import would do and asks for confirmation before writing it. With dryRun it
+// stops after the report, with yes it writes straight away without a report.
+func runImport(dryRun bool, yes bool, msg string, run func(dry bool) (*model.ImportResponse, error)) error {
+	if !yes {
+		res, err := run(true)
+		if err != nil {
+			return err
+		}
+		logImport(res, msg)
+
+		if dryRun {
+			return nil
+		}
+
+		ok, err := confirm("Write the import?")
+		if err != nil || !ok {
+			return err
+		}
+	}
+
+	res, err := run(dryRun)
+	if err != nil {
+		return err
+	}
+	logImport(res, msg)
+	return nil
+}
+
+// confirm asks a yes or no question on the terminal, anything but yes is a no.
+func confirm(question string) (bool, error) {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// ImportMarkdown imports the .md files in dir, importing the same files again updates the posts they
+// created. See runImport for how dryRun and yes are handled.
+func ImportMarkdown(dir string, dryRun bool, yes bool) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return err
+	}
+
+	files := make([]*model.MarkdownFile, 0, len(paths))
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, &model.MarkdownFile{Name: filepath.Base(path), Data: data})
+	}
+
+	postgresClient, err := postgres.NewClient()
+	if err != nil {
+		return err
+	}
+	defer postgresClient.Close()
+
+	redisClient, err := redis.NewClient()
+	if err != nil {
+		return err
+	}
+	defer redisClient.Close()
+
+	importService := service.NewImportService(repository.NewImportRepository(postgresClient, redisClient))
+	ctx := middleware.WithClaims(context.Background(), 0, constant.ROLE_ADMIN)
+
+	return runImport(dryRun, yes, "imported markdown files", func(dry bool) (*model.ImportResponse, error) {
+		return importService.Markdown(ctx, model.MarkdownImportRequest{Files: files, DryRun: dry})
+	})
+}
+
+// ExportMarkdown writes every post to dir as a .md file with front matter in format, yaml or toml.
+func ExportMarkdown(dir string, format string) error {
+	postgresClient, err := postgres.NewClient()
+	if err != nil {
+		return err
+	}
+	defer postgresClient.Close()
+
+	redisClient, err := redis.NewClient()
+	if err != nil {
+		return err
+	}
+	defer redisClient.Close()
+
+	importService := service.NewImportService(repository.NewImportRepository(postgresClient, redisClient))
+	ctx := middleware.WithClaims(context.Background(), 0, constant.ROLE_ADMIN)
+
+	files, err := importService.ExportMarkdown(ctx, model.MarkdownExportRequest{Format: format})
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, file.Name), file.Data, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.Log().Info().Int("posts", len(files)).Str("dir", dir).Msg("exported markdown files")
+	return nil
+}
+
+func logImport(res *model.ImportResponse, msg string) {
+	for _, warning := range res.Warnings {
+		logger.Log().Warn().Msg(warning)
+	}
+
+	logger.Log().Info().
+		Bool("dry_run", res.DryRun).
+		Int("accounts_created", res.AccountsCreated).
+		Int("accounts_matched", res.AccountsMatched).
+		Int("categories_created", res.CategoriesCreated).
+		Int("categories_matched", res.CategoriesMatched).
+		Int("tags", res.Tags).
+		Int("posts_created", res.PostsCreated).
+		Int("posts_updated", res.PostsUpdated).
+		Int("posts_existing", res.PostsExisting).
+		Int("posts_skipped", res.PostsSkipped).
+		Msg(msg)
+}
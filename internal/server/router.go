@@ -11,6 +11,8 @@ import (
 	"github.com/anonychun/go-blog-api/internal/db/postgres"
 	"github.com/anonychun/go-blog-api/internal/db/redis"
 	"github.com/anonychun/go-blog-api/internal/security/middleware"
+	"github.com/anonychun/go-blog-api/internal/storage"
+	"github.com/anonychun/go-blog-api/internal/theme"
 	"github.com/go-chi/chi"
 	chimiddleware "github.com/go-chi/chi/middleware"
 	"github.com/go-chi/cors"
@@ -26,29 +28,109 @@ import (
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name X-API-Key
-func NewRouter(postgresClient postgres.Client, redisClient redis.Client) *chi.Mux {
+
+// NewRouter builds the API router, siteTheme is nil when the HTML frontend is disabled.
+func NewRouter(postgresClient postgres.Client, redisClient redis.Client, mediaStorage storage.Storage, siteTheme *theme.Theme) *chi.Mux {
 	router := chi.NewRouter()
 
 	router.Use(httprate.LimitByIP(
 		config.Cfg().HttpRateLimitRequest,
 		config.Cfg().HttpRateLimitTime,
 	))
-	router.Use(cors.AllowAll().Handler)
+	router.Use(cors.Handler(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{
+			http.MethodHead,
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+		},
+		AllowedHeaders: []string{"*"},
+		// let browsers read the version used for If-Match
+		ExposedHeaders: []string{"ETag"},
+	}))
 	router.Use(chimiddleware.Logger)
 	router.Use(chimiddleware.Recoverer)
 
+	mountRoutes(router, postgresClient, redisClient, mediaStorage, siteTheme)
+	return router
+}
+
+// mountRoutes registers the handlers of the application on router, apart from the middlewares which only
+// make sense for requests coming from the network.
+func mountRoutes(router chi.Router, postgresClient postgres.Client, redisClient redis.Client, mediaStorage storage.Storage, siteTheme *theme.Theme) {
 	accountRepository := repository.NewAccountRepository(postgresClient, redisClient)
 	postRepository := repository.NewPostRepository(postgresClient, redisClient)
+	tagRepository := repository.NewTagRepository(postgresClient)
+	categoryRepository := repository.NewCategoryRepository(postgresClient, redisClient)
+	seriesRepository := repository.NewSeriesRepository(postgresClient)
+	commentRepository := repository.NewCommentRepository(postgresClient, redisClient)
+	reactionRepository := repository.NewReactionRepository(postgresClient, redisClient)
+	bookmarkRepository := repository.NewBookmarkRepository(postgresClient)
+	viewRepository := repository.NewViewRepository(postgresClient, redisClient)
+	trendingRepository := repository.NewTrendingRepository(postgresClient, redisClient)
+	mediaRepository := repository.NewMediaRepository(postgresClient, redisClient)
+	postAuthorRepository := repository.NewPostAuthorRepository(postgresClient, redisClient)
+	postReviewRepository := repository.NewPostReviewRepository(postgresClient, redisClient)
+	sitemapRepository := repository.NewSitemapRepository(postgresClient, redisClient)
+	activityPubRepository := repository.NewActivityPubRepository(postgresClient)
+	webmentionRepository := repository.NewWebmentionRepository(postgresClient)
+	importRepository := repository.NewImportRepository(postgresClient, redisClient)
 
 	authService := service.NewAuthService(accountRepository)
 	accountService := service.NewAccountService(accountRepository)
-	postService := service.NewPostService(postRepository)
+	postService := service.NewPostService(postRepository, categoryRepository, seriesRepository, reactionRepository, viewRepository, trendingRepository, mediaRepository, postAuthorRepository, postReviewRepository, accountRepository, activityPubRepository, webmentionRepository, mediaStorage)
+	tagService := service.NewTagService(tagRepository)
+	categoryService := service.NewCategoryService(categoryRepository, postRepository)
+	seriesService := service.NewSeriesService(seriesRepository, postRepository)
+	commentService := service.NewCommentService(commentRepository, postRepository)
+	reactionService := service.NewReactionService(reactionRepository, postRepository)
+	bookmarkService := service.NewBookmarkService(bookmarkRepository, postRepository)
+	mediaService := service.NewMediaService(mediaRepository, mediaStorage)
+	postAuthorService := service.NewPostAuthorService(postAuthorRepository, postRepository, accountRepository)
+	feedService := service.NewFeedService(postService, postRepository, accountRepository, mediaRepository, mediaStorage)
+	sitemapService := service.NewSitemapService(sitemapRepository)
+	activityPubService := service.NewActivityPubService(activityPubRepository, accountRepository, postRepository)
+	webmentionService := service.NewWebmentionService(webmentionRepository, postRepository)
+	importService := service.NewImportService(importRepository)
+	oembedService := service.NewOEmbedService(postRepository, mediaRepository, mediaStorage)
 
 	authHandler := handler.NewAuthHandler(authService)
 	accountHandler := handler.NewAccountHandler(accountService)
 	postHandler := handler.NewPostHandler(postService)
+	tagHandler := handler.NewTagHandler(tagService)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	seriesHandler := handler.NewSeriesHandler(seriesService)
+	commentHandler := handler.NewCommentHandler(commentService)
+	reactionHandler := handler.NewReactionHandler(reactionService)
+	bookmarkHandler := handler.NewBookmarkHandler(bookmarkService)
+	mediaHandler := handler.NewMediaHandler(mediaService)
+	postAuthorHandler := handler.NewPostAuthorHandler(postAuthorService)
+	feedHandler := handler.NewFeedHandler(feedService)
+	sitemapHandler := handler.NewSitemapHandler(sitemapService)
+	activityPubHandler := handler.NewActivityPubHandler(activityPubService)
+	webmentionHandler := handler.NewWebmentionHandler(webmentionService)
+	oembedHandler := handler.NewOEmbedHandler(oembedService)
+	importHandler := handler.NewImportHandler(importService)
 
 	router.Options("/*", func(w http.ResponseWriter, r *http.Request) {})
+	if fileServer, ok := mediaStorage.(http.Handler); ok {
+		router.Handle("/media/*", http.StripPrefix("/media", fileServer))
+	}
+	router.Get("/sitemap.xml", sitemapHandler.Get())
+	router.Get("/sitemap-{page}.xml", sitemapHandler.GetPage())
+	router.Get("/.well-known/webfinger", activityPubHandler.WebFinger())
+	if siteTheme != nil {
+		siteHandler := handler.NewSiteHandler(siteTheme, postService, accountService)
+		router.Get("/", siteHandler.Index())
+		router.Get("/page/{page}", siteHandler.Index())
+		router.Get("/posts/{post_id}", siteHandler.Post())
+		router.Get("/accounts/{account_id}", siteHandler.Account())
+		router.Get("/accounts/{account_id}/page/{page}", siteHandler.Account())
+		router.Handle("/static/*", siteHandler.Static())
+	}
 	api := router.Route("/v1", func(router chi.Router) {})
 
 	api.Route("/accounts", func(r chi.Router) {
@@ -60,19 +142,94 @@ func NewRouter(postgresClient postgres.Client, redisClient redis.Client) *chi.Mu
 		r.With(middleware.JWTVerifier).Put("/{account_id}", accountHandler.Update())
 		r.With(middleware.JWTVerifier).Put("/{account_id}/password", accountHandler.UpdatePassword())
 		r.With(middleware.JWTVerifier).Delete("/{account_id}", accountHandler.Delete())
+
+		r.With(middleware.JWTVerifier).Get("/{account_id}/bookmarks", bookmarkHandler.List())
+		r.With(middleware.JWTVerifier).Get("/{account_id}/bookmarks/folders", bookmarkHandler.ListFolders())
+		r.With(middleware.JWTVerifier).Put("/{account_id}/bookmarks/{post_id}", bookmarkHandler.Create())
+		r.With(middleware.JWTVerifier).Delete("/{account_id}/bookmarks/{post_id}", bookmarkHandler.Delete())
+
+		r.Get("/{account_id}/feed.rss", feedHandler.AccountRSS())
+		r.Get("/{account_id}/feed.atom", feedHandler.AccountAtom())
+
+		r.Get("/{account_id}/actor", activityPubHandler.GetActor())
+		r.Post("/{account_id}/inbox", activityPubHandler.Inbox())
+		r.Get("/{account_id}/outbox", activityPubHandler.GetOutbox())
+		r.Get("/{account_id}/followers", activityPubHandler.GetFollowers())
 	})
 
 	api.Route("/posts", func(r chi.Router) {
 		r.With(middleware.JWTVerifier).Post("/", postHandler.Create())
-		r.Get("/", postHandler.List())
-		r.Get("/{post_id}", postHandler.Get())
+		r.With(middleware.JWTOptional).Get("/", postHandler.List())
+		r.With(middleware.JWTOptional).Get("/trending", postHandler.ListTrending())
+		r.With(middleware.JWTOptional).Get("/{post_id}", postHandler.Get())
 		r.With(middleware.JWTVerifier).Put("/{post_id}", postHandler.Update())
 		r.With(middleware.JWTVerifier).Delete("/{post_id}", postHandler.Delete())
+		r.With(middleware.JWTVerifier).Get("/{post_id}/stats", postHandler.ListStats())
+		r.Get("/{post_id}/object", activityPubHandler.GetObject())
+		r.With(middleware.JWTOptional).Get("/{post_id}/webmentions", webmentionHandler.List())
+
+		r.With(middleware.JWTOptional).Get("/{post_id}/authors", postAuthorHandler.List())
+		r.With(middleware.JWTVerifier).Put("/{post_id}/authors/{account_id}", postAuthorHandler.Update())
+		r.With(middleware.JWTVerifier).Delete("/{post_id}/authors/{account_id}", postAuthorHandler.Delete())
+
+		r.With(middleware.JWTVerifier).Post("/{post_id}/review/submit", postHandler.Submit())
+		r.With(middleware.JWTVerifier).Post("/{post_id}/review/approve", postHandler.Approve())
+		r.With(middleware.JWTVerifier).Post("/{post_id}/review/request-changes", postHandler.RequestChanges())
+		r.With(middleware.JWTVerifier).Post("/{post_id}/publish", postHandler.Publish())
+		r.With(middleware.JWTVerifier).Post("/{post_id}/unpublish", postHandler.Unpublish())
+		r.With(middleware.JWTVerifier).Get("/{post_id}/reviews", postHandler.ListReviews())
+
+		r.With(middleware.JWTVerifier).Post("/{post_id}/comments", commentHandler.Create())
+		r.With(middleware.JWTOptional).Get("/{post_id}/comments", commentHandler.List())
+		r.With(middleware.JWTVerifier).Put("/{post_id}/comments/{comment_id}", commentHandler.Update())
+		r.With(middleware.JWTVerifier).Delete("/{post_id}/comments/{comment_id}", commentHandler.Delete())
+
+		r.With(middleware.JWTVerifier).Put("/{post_id}/reactions/{reaction_type}", reactionHandler.Add())
+		r.With(middleware.JWTVerifier).Delete("/{post_id}/reactions/{reaction_type}", reactionHandler.Remove())
+	})
+
+	api.Get("/search", postHandler.Search())
+	api.Get("/feed.rss", feedHandler.RSS())
+	api.Get("/feed.atom", feedHandler.Atom())
+	api.Get("/feed.json", feedHandler.JSON())
+	api.Post("/webmention", webmentionHandler.Create())
+	api.Get("/oembed", oembedHandler.Get())
+
+	api.Route("/tags", func(r chi.Router) {
+		r.Get("/", tagHandler.List())
+	})
+
+	api.Route("/categories", func(r chi.Router) {
+		r.With(middleware.JWTVerifier).Post("/", categoryHandler.Create())
+		r.Get("/", categoryHandler.List())
+		r.Get("/{category_id}", categoryHandler.Get())
+		r.With(middleware.JWTVerifier).Put("/{category_id}", categoryHandler.Update())
+		r.With(middleware.JWTVerifier).Delete("/{category_id}", categoryHandler.Delete())
+		r.Get("/{category_id}/posts", categoryHandler.ListPosts())
+	})
+
+	api.Route("/series", func(r chi.Router) {
+		r.With(middleware.JWTVerifier).Post("/", seriesHandler.Create())
+		r.With(middleware.JWTOptional).Get("/", seriesHandler.List())
+		r.With(middleware.JWTOptional).Get("/{series_id}", seriesHandler.Get())
+		r.With(middleware.JWTVerifier).Put("/{series_id}", seriesHandler.Update())
+		r.With(middleware.JWTVerifier).Put("/{series_id}/posts", seriesHandler.UpdatePosts())
+		r.With(middleware.JWTVerifier).Delete("/{series_id}", seriesHandler.Delete())
+	})
+
+	api.Route("/media", func(r chi.Router) {
+		r.With(middleware.JWTVerifier).Post("/", mediaHandler.Create())
+		r.With(middleware.JWTVerifier).Get("/", mediaHandler.List())
+		r.With(middleware.JWTOptional).Get("/{media_id}", mediaHandler.Get())
+		r.With(middleware.JWTVerifier).Put("/{media_id}", mediaHandler.Update())
+		r.With(middleware.JWTVerifier).Delete("/{media_id}", mediaHandler.Delete())
+	})
+
+	api.Route("/imports", func(r chi.Router) {
+		r.With(middleware.JWTVerifier).Post("/wordpress", importHandler.WordPress())
 	})
 
 	api.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("doc.json"),
 	))
-
-	return router
 }
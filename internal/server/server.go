@@ -12,6 +12,8 @@ import (
 	"github.com/anonychun/go-blog-api/internal/db/postgres"
 	"github.com/anonychun/go-blog-api/internal/db/redis"
 	"github.com/anonychun/go-blog-api/internal/logger"
+	"github.com/anonychun/go-blog-api/internal/storage"
+	"github.com/anonychun/go-blog-api/internal/theme"
 )
 
 func Start() error {
@@ -27,9 +29,27 @@ func Start() error {
 	}
 	defer redisClient.Close()
 
+	mediaStorage, err := storage.NewStorage()
+	if err != nil {
+		return err
+	}
+
+	var siteTheme *theme.Theme
+	if config.Cfg().ThemePath != "" {
+		siteTheme, err = theme.Load(config.Cfg().ThemePath, config.Cfg().ThemeReload)
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	startWorkers(ctx, postgresClient, redisClient, mediaStorage)
+
 	httpServer := &http.Server{
 		Addr:    fmt.Sprintf(":%d", config.Cfg().AppPort),
-		Handler: NewRouter(postgresClient, redisClient),
+		Handler: NewRouter(postgresClient, redisClient, mediaStorage, siteTheme),
 	}
 
 	idleConnsClosed := make(chan struct{})
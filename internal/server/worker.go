@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+
+	"github.com/anonychun/go-blog-api/internal/app/repository"
+	"github.com/anonychun/go-blog-api/internal/app/service"
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/db/postgres"
+	"github.com/anonychun/go-blog-api/internal/db/redis"
+	"github.com/anonychun/go-blog-api/internal/storage"
+	"github.com/anonychun/go-blog-api/internal/worker"
+)
+
+// startWorkers launches the background jobs of the application, they stop once ctx is cancelled.
+func startWorkers(ctx context.Context, postgresClient postgres.Client, redisClient redis.Client, mediaStorage storage.Storage) {
+	postRepository := repository.NewPostRepository(postgresClient, redisClient)
+	categoryRepository := repository.NewCategoryRepository(postgresClient, redisClient)
+	seriesRepository := repository.NewSeriesRepository(postgresClient)
+	reactionRepository := repository.NewReactionRepository(postgresClient, redisClient)
+	viewRepository := repository.NewViewRepository(postgresClient, redisClient)
+	trendingRepository := repository.NewTrendingRepository(postgresClient, redisClient)
+	mediaRepository := repository.NewMediaRepository(postgresClient, redisClient)
+	postAuthorRepository := repository.NewPostAuthorRepository(postgresClient, redisClient)
+	postReviewRepository := repository.NewPostReviewRepository(postgresClient, redisClient)
+	accountRepository := repository.NewAccountRepository(postgresClient, redisClient)
+	activityPubRepository := repository.NewActivityPubRepository(postgresClient)
+	webmentionRepository := repository.NewWebmentionRepository(postgresClient)
+
+	postService := service.NewPostService(postRepository, categoryRepository, seriesRepository, reactionRepository, viewRepository, trendingRepository, mediaRepository, postAuthorRepository, postReviewRepository, accountRepository, activityPubRepository, webmentionRepository, mediaStorage)
+	reactionService := service.NewReactionService(reactionRepository, postRepository)
+	mediaService := service.NewMediaService(mediaRepository, mediaStorage)
+	activityPubService := service.NewActivityPubService(activityPubRepository, accountRepository, postRepository)
+	webmentionService := service.NewWebmentionService(webmentionRepository, postRepository)
+
+	go worker.Every(ctx, "reaction reconcile", config.Cfg().ReactionReconcileInterval, reactionService.Reconcile)
+	go worker.Every(ctx, "post view flush", config.Cfg().ViewFlushInterval, postService.FlushViews)
+	go worker.Every(ctx, "trending recompute", config.Cfg().TrendingInterval, postService.RecomputeTrending)
+	go worker.Every(ctx, "media garbage collection", config.Cfg().MediaGCInterval, mediaService.CollectGarbage)
+	go worker.Every(ctx, "activitypub delivery", config.Cfg().ActivityPubDeliveryInterval, activityPubService.Deliver)
+	go worker.Every(ctx, "webmention verification", config.Cfg().WebmentionInterval, webmentionService.Verify)
+	go worker.Every(ctx, "webmention sending", config.Cfg().WebmentionInterval, webmentionService.Send)
+}
@@ -0,0 +1,64 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs is the number of URLs a single sitemap may hold, larger sites are split and listed by an index.
+const MaxURLs = 50000
+
+const ContentType = "application/xml; charset=utf-8"
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	URLs    []entry  `xml:"url"`
+}
+
+type index struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Sitemaps []entry  `xml:"sitemap"`
+}
+
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// URLSet renders a sitemap listing the given pages.
+func URLSet(urls []URL) ([]byte, error) {
+	return marshal(urlSet{XMLNS: namespace, URLs: entries(urls)})
+}
+
+// Index renders a sitemap index pointing to the given sitemaps.
+func Index(sitemaps []URL) ([]byte, error) {
+	return marshal(index{XMLNS: namespace, Sitemaps: entries(sitemaps)})
+}
+
+func entries(urls []URL) []entry {
+	res := make([]entry, len(urls))
+	for i, url := range urls {
+		res[i].Loc = url.Loc
+		if !url.LastMod.IsZero() {
+			res[i].LastMod = url.LastMod.UTC().Format(time.RFC3339)
+		}
+	}
+	return res
+}
+
+func marshal(doc interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
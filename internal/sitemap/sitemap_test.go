@@ -0,0 +1,55 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLSet(t *testing.T) {
+	modified := time.Date(2021, 6, 1, 8, 30, 0, 0, time.FixedZone("WIB", 7*60*60))
+	data, err := URLSet([]URL{
+		{Loc: "https://example.com/posts/1?a=1&b=2", LastMod: modified},
+		{Loc: "https://example.com/posts/2"},
+	})
+	require.NoError(t, err)
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+		URLs    []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"url"`
+	}
+	require.NoError(t, xml.Unmarshal(data, &doc))
+	require.Len(t, doc.URLs, 2)
+
+	assert.Equal(t, "https://example.com/posts/1?a=1&b=2", doc.URLs[0].Loc)
+	assert.Equal(t, "2021-06-01T01:30:00Z", doc.URLs[0].LastMod)
+	assert.NotContains(t, string(data), "<lastmod></lastmod>")
+	assert.Contains(t, string(data), "&amp;")
+}
+
+func TestIndex(t *testing.T) {
+	data, err := Index([]URL{
+		{Loc: "https://example.com/sitemap-1.xml", LastMod: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{Loc: "https://example.com/sitemap-2.xml", LastMod: time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)},
+	})
+	require.NoError(t, err)
+
+	var doc struct {
+		XMLName  xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+		Sitemaps []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"sitemap"`
+	}
+	require.NoError(t, xml.Unmarshal(data, &doc))
+	require.Len(t, doc.Sitemaps, 2)
+
+	assert.Equal(t, "https://example.com/sitemap-2.xml", doc.Sitemaps[1].Loc)
+	assert.Equal(t, "2021-07-01T00:00:00Z", doc.Sitemaps[1].LastMod)
+}
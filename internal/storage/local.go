@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// NewLocalStorage stores objects as files under root, it also serves them over http without directory listings.
+func NewLocalStorage(root, publicUrl string) (Storage, error) {
+	err := os.MkdirAll(root, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &localStorage{root, strings.TrimRight(publicUrl, "/")}, nil
+}
+
+type localStorage struct {
+	root      string
+	publicUrl string
+}
+
+func (s *localStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	dst := s.path(key)
+	err := os.MkdirAll(filepath.Dir(dst), 0755)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so readers never see a partial object
+	tmp, err := ioutil.TempFile(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, body)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (s *localStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := os.Open(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotExist
+	}
+	return file, err
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *localStorage) URL(key string) string {
+	return s.publicUrl + "/" + key
+}
+
+func (s *localStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	info, err := os.Stat(s.path(key))
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, s.path(key))
+}
+
+// path maps a key to a file inside root, keys can never escape it.
+func (s *localStorage) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(path.Clean("/"+key)))
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/anonychun/go-blog-api/internal/config"
+	"github.com/anonychun/go-blog-api/internal/constant"
+)
+
+var ErrNotExist = errors.New("storage: object does not exist")
+
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+func NewStorage() (Storage, error) {
+	switch config.Cfg().StorageDriver {
+	case constant.STORAGE_DRIVER_LOCAL:
+		return NewLocalStorage(config.Cfg().StorageLocalPath, config.Cfg().StoragePublicUrl)
+	case constant.STORAGE_DRIVER_S3:
+		return NewS3Storage(S3Options{
+			Endpoint:  config.Cfg().S3Endpoint,
+			Region:    config.Cfg().S3Region,
+			Bucket:    config.Cfg().S3Bucket,
+			AccessKey: config.Cfg().S3AccessKey,
+			SecretKey: config.Cfg().S3SecretKey,
+			PublicUrl: config.Cfg().StoragePublicUrl,
+			Timeout:   config.Cfg().S3Timeout,
+		}), nil
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", config.Cfg().StorageDriver)
+	}
+}
@@ -0,0 +1,199 @@
+package theme
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LayoutFile is the template every page is rendered through, it has to define the "layout" template.
+const LayoutFile = "layout.html"
+
+// StaticDir holds the assets of a theme, served as they are.
+const StaticDir = "static"
+
+var ErrPageNotFound = errors.New("theme: page template not found")
+
+// Theme is a directory of html/template files: a layout, optional partials and one file per page, plus
+// the static assets under static/.
+type Theme struct {
+	dir    string
+	reload bool
+
+	mu       sync.RWMutex
+	pages    map[string]*template.Template
+	modified time.Time
+}
+
+// Load parses the theme in dir, with reload the templates are parsed again whenever a file of the theme
+// changes so themes can be edited without restarting the server.
+func Load(dir string, reload bool) (*Theme, error) {
+	t := &Theme{dir: dir, reload: reload}
+	err := t.parse()
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Render executes the layout with the given page and writes the result, nothing is written when the
+// template fails.
+func (t *Theme) Render(w io.Writer, page string, data interface{}) error {
+	if t.reload {
+		err := t.reloadIfModified()
+		if err != nil {
+			return err
+		}
+	}
+
+	t.mu.RLock()
+	tmpl, found := t.pages[page]
+	t.mu.RUnlock()
+	if !found {
+		return fmt.Errorf("%w: %s", ErrPageNotFound, page)
+	}
+
+	var buf bytes.Buffer
+	err := tmpl.ExecuteTemplate(&buf, "layout", data)
+	if err != nil {
+		return err
+	}
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// Static serves the assets of the theme, directories are not listed.
+func (t *Theme) Static() http.Handler {
+	dir := t.StaticDir()
+	files := http.FileServer(http.Dir(dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path.Clean("/"+r.URL.Path))))
+		if err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
+
+// StaticDir is the directory holding the assets of the theme.
+func (t *Theme) StaticDir() string {
+	return filepath.Join(t.dir, StaticDir)
+}
+
+func (t *Theme) parse() error {
+	modified, err := lastModified(t.dir)
+	if err != nil {
+		return err
+	}
+
+	partials, err := filepath.Glob(filepath.Join(t.dir, "partials", "*.html"))
+	if err != nil {
+		return err
+	}
+	shared := append([]string{filepath.Join(t.dir, LayoutFile)}, partials...)
+
+	files, err := filepath.Glob(filepath.Join(t.dir, "*.html"))
+	if err != nil {
+		return err
+	}
+
+	pages := make(map[string]*template.Template)
+	for _, file := range files {
+		if filepath.Base(file) == LayoutFile {
+			continue
+		}
+
+		name := strings.TrimSuffix(filepath.Base(file), ".html")
+		tmpl, err := template.New(name).Funcs(funcs).ParseFiles(append(shared, file)...)
+		if err != nil {
+			return err
+		}
+		pages[name] = tmpl
+	}
+
+	t.mu.Lock()
+	t.pages = pages
+	t.modified = modified
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *Theme) reloadIfModified() error {
+	modified, err := lastModified(t.dir)
+	if err != nil {
+		return err
+	}
+
+	t.mu.RLock()
+	changed := modified.After(t.modified)
+	t.mu.RUnlock()
+	if !changed {
+		return nil
+	}
+	return t.parse()
+}
+
+// lastModified returns the latest modification time of the templates, static assets are served from disk
+// and do not need a reload.
+func lastModified(dir string) (time.Time, error) {
+	var modified time.Time
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && path == filepath.Join(dir, StaticDir) {
+			return filepath.SkipDir
+		}
+		if info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+		return nil
+	})
+	return modified, err
+}
+
+var funcs = template.FuncMap{
+	"paragraphs": paragraphs,
+	"date":       date,
+	"join":       strings.Join,
+}
+
+// paragraphs renders a plain text body as HTML paragraphs, single line breaks are kept.
+func paragraphs(text string) template.HTML {
+	var sb strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		sb.WriteString("<p>")
+		sb.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		sb.WriteString("</p>")
+	}
+	return template.HTML(sb.String())
+}
+
+// date formats a time or a time pointer, a nil pointer formats as an empty string.
+func date(value interface{}, layout string) string {
+	switch t := value.(type) {
+	case time.Time:
+		return t.Format(layout)
+	case *time.Time:
+		if t != nil {
+			return t.Format(layout)
+		}
+	}
+	return ""
+}
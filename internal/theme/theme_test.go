@@ -0,0 +1,120 @@
+package theme
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+}
+
+func testTheme(t *testing.T) string {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, LayoutFile), `{{define "layout"}}<title>{{.Title}}</title>{{template "content" .}}{{template "footer"}}{{end}}`)
+	writeFile(t, filepath.Join(dir, "partials", "footer.html"), `{{define "footer"}}<footer>bye</footer>{{end}}`)
+	writeFile(t, filepath.Join(dir, "post.html"), `{{define "content"}}<h1>{{.Title}}</h1>{{paragraphs .Body}}<time>{{date .Published "2006-01-02"}}</time>{{end}}`)
+	writeFile(t, filepath.Join(dir, "index.html"), `{{define "content"}}<p>{{join .Tags ", "}}</p>{{end}}`)
+	writeFile(t, filepath.Join(dir, "static", "style.css"), `body { margin: 0 }`)
+	return dir
+}
+
+func TestRender(t *testing.T) {
+	theme, err := Load(testTheme(t), false)
+	require.NoError(t, err)
+
+	published := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err = theme.Render(&buf, "post", map[string]interface{}{
+		"Title":     "<Hello>",
+		"Body":      "First & one\nstill first\n\nSecond",
+		"Published": &published,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `<title>&lt;Hello&gt;</title><h1>&lt;Hello&gt;</h1><p>First &amp; one<br>still first</p><p>Second</p><time>2021-06-01</time><footer>bye</footer>`, buf.String())
+
+	buf.Reset()
+	err = theme.Render(&buf, "index", map[string]interface{}{"Title": "Blog", "Tags": []string{"go", "web"}})
+	require.NoError(t, err)
+	assert.Equal(t, `<title>Blog</title><p>go, web</p><footer>bye</footer>`, buf.String())
+
+	t.Run("missing page", func(t *testing.T) {
+		buf.Reset()
+		err := theme.Render(&buf, "account", nil)
+		assert.ErrorIs(t, err, ErrPageNotFound)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("failing template writes nothing", func(t *testing.T) {
+		buf.Reset()
+		err := theme.Render(&buf, "post", map[string]interface{}{"Title": "x", "Body": 1})
+		assert.Error(t, err)
+		assert.Empty(t, buf.String())
+	})
+}
+
+func TestReload(t *testing.T) {
+	dir := testTheme(t)
+	later := time.Now().Add(time.Minute)
+
+	static, err := Load(dir, false)
+	require.NoError(t, err)
+	reloading, err := Load(dir, true)
+	require.NoError(t, err)
+
+	page := filepath.Join(dir, "index.html")
+	writeFile(t, page, `{{define "content"}}<p>changed</p>{{end}}`)
+	require.NoError(t, os.Chtimes(page, later, later))
+
+	var buf bytes.Buffer
+	require.NoError(t, static.Render(&buf, "index", map[string]interface{}{"Title": "Blog", "Tags": []string{"go"}}))
+	assert.Contains(t, buf.String(), "<p>go</p>")
+
+	buf.Reset()
+	require.NoError(t, reloading.Render(&buf, "index", map[string]interface{}{"Title": "Blog"}))
+	assert.Contains(t, buf.String(), "<p>changed</p>")
+
+	t.Run("broken edit", func(t *testing.T) {
+		writeFile(t, page, `{{define "content"}}{{.Title}{{end}}`)
+		later = later.Add(time.Minute)
+		require.NoError(t, os.Chtimes(page, later, later))
+		assert.Error(t, reloading.Render(&buf, "index", nil))
+	})
+}
+
+func TestLoad(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing"), false)
+	assert.Error(t, err)
+
+	dir := testTheme(t)
+	writeFile(t, filepath.Join(dir, "broken.html"), `{{define "content"}}{{end`)
+	_, err = Load(dir, false)
+	assert.Error(t, err)
+}
+
+func TestStatic(t *testing.T) {
+	theme, err := Load(testTheme(t), false)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	theme.Static().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/style.css", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `body { margin: 0 }`, rec.Body.String())
+
+	writeFile(t, filepath.Join(theme.StaticDir(), "fonts", "sans.woff2"), "font")
+	for _, url := range []string{"/", "/fonts/", "/missing.css"} {
+		rec = httptest.NewRecorder()
+		theme.Static().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, url)
+	}
+}
@@ -1,8 +1,13 @@
 package web
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/anonychun/go-blog-api/internal/app/model"
 )
@@ -13,6 +18,20 @@ func MarshalPayload(w http.ResponseWriter, code int, payload interface{}) {
 	json.NewEncoder(w).Encode(payload)
 }
 
+// SetETag tags the response with the version of the resource, clients send it back in If-Match.
+func SetETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// ServeDocument writes a generated document tagged with a hash of its content, clients revalidate it
+// through If-None-Match or If-Modified-Since and get a 304 when nothing changed.
+func ServeDocument(w http.ResponseWriter, r *http.Request, contentType string, modified time.Time, data []byte) {
+	sum := sha256.Sum256(data)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", strconv.Quote(hex.EncodeToString(sum[:16])))
+	http.ServeContent(w, r, "", modified, bytes.NewReader(data))
+}
+
 func MarshalError(w http.ResponseWriter, code int, err error) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/anonychun/go-blog-api/internal/config"
 	"github.com/anonychun/go-blog-api/internal/constant"
@@ -36,6 +38,10 @@ func GetUrlQueryString(r *http.Request, key string) string {
 	return r.URL.Query().Get(key)
 }
 
+func GetUrlQueryStrings(r *http.Request, key string) []string {
+	return r.URL.Query()[key]
+}
+
 func GetUrlQueryInt(r *http.Request, key string) (int, error) {
 	i, err := strconv.Atoi(key)
 	if err != nil {
@@ -52,6 +58,37 @@ func GetUrlQueryInt64(r *http.Request, key string) (int64, error) {
 	return i, nil
 }
 
+// GetUrlQueryDate parses a YYYY-MM-DD query parameter, falling back to def when it is absent.
+func GetUrlQueryDate(r *http.Request, key string, def time.Time) (time.Time, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return def, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, constant.ErrUrlQueryParameter
+	}
+	return t, nil
+}
+
+// GetIfMatch returns the version a conditional request expects the resource to be at, zero when the
+// request has no If-Match header or matches any version.
+func GetIfMatch(r *http.Request) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	} else if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return 0, constant.ErrPrecondition
+	}
+
+	version, err := strconv.ParseInt(value[1:len(value)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, constant.ErrPrecondition
+	}
+	return version, nil
+}
+
 func GetPagination(r *http.Request) (limit, offset int, err error) {
 	limitQuery := r.URL.Query().Get("limit")
 	offsetQuery := r.URL.Query().Get("offset")
@@ -82,6 +119,35 @@ func GetPagination(r *http.Request) (limit, offset int, err error) {
 	return
 }
 
+// GetUploadedFile returns the multipart file sent in the given form field, requests larger than the
+// configured media size are rejected before the body is parsed.
+func GetUploadedFile(w http.ResponseWriter, r *http.Request, key string) (multipart.File, *multipart.FileHeader, error) {
+	return getUploadedFile(w, r, key, config.Cfg().MediaMaxSize, constant.ErrMediaFile, constant.ErrMediaTooLarge)
+}
+
+// GetImportFile is GetUploadedFile for exports of other blogs, which are bounded by the import size instead.
+func GetImportFile(w http.ResponseWriter, r *http.Request, key string) (multipart.File, *multipart.FileHeader, error) {
+	return getUploadedFile(w, r, key, config.Cfg().ImportMaxSize, constant.ErrImportFile, constant.ErrImportTooLarge)
+}
+
+func getUploadedFile(w http.ResponseWriter, r *http.Request, key string, limit int64, errMissing, errTooLarge error) (multipart.File, *multipart.FileHeader, error) {
+	// leave room for the multipart boundaries and headers around the file
+	maxSize := limit + 1<<20
+	if r.ContentLength > maxSize {
+		return nil, nil, errTooLarge
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+
+	file, header, err := r.FormFile(key)
+	if err != nil {
+		return nil, nil, errMissing
+	} else if header.Size > limit {
+		file.Close()
+		return nil, nil, errTooLarge
+	}
+	return file, header, nil
+}
+
 func SaveUploadedFile(file *multipart.FileHeader, dst string) error {
 	src, err := file.Open()
 	if err != nil {
@@ -0,0 +1,251 @@
+package webmention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/security/outbound"
+	"golang.org/x/net/html"
+)
+
+var (
+	// ErrNoLink is returned when the source does not link to the target (anymore).
+	ErrNoLink = errors.New("webmention: source does not link to target")
+
+	// ErrSourceGone is returned when the source answers 404 or 410, its mention has to be removed.
+	ErrSourceGone = errors.New("webmention: source is gone")
+)
+
+const maxDocumentSize = 1 << 20
+
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient returns a client for sources and endpoints chosen by whoever sends or hosts a mention, so it
+// only connects to public addresses and follows a limited number of redirects.
+func NewClient(timeout time.Duration) *Client {
+	return &Client{httpClient: outbound.NewClient(timeout, "http", "https")}
+}
+
+// Discover returns the Webmention endpoint of a target, looking at the Link header before the document.
+// An empty endpoint means the target does not accept webmentions.
+func (c *Client) Discover(ctx context.Context, target string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("webmention: %s responded %s", target, resp.Status)
+	}
+
+	// redirects change the base the endpoint is resolved against
+	base := resp.Request.URL
+
+	for _, link := range resp.Header.Values("Link") {
+		if endpoint := parseLinkHeader(link); endpoint != "" {
+			return resolve(base, endpoint)
+		}
+	}
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return "", nil
+	}
+
+	doc, err := html.Parse(io.LimitReader(resp.Body, maxDocumentSize))
+	if err != nil {
+		return "", err
+	}
+
+	endpoint, found := findEndpoint(doc)
+	if !found {
+		return "", nil
+	}
+	return resolve(base, endpoint)
+}
+
+// Send notifies an endpoint that source mentions target.
+func (c *Client) Send(ctx context.Context, endpoint, source, target string) error {
+	form := url.Values{"source": {source}, "target": {target}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxDocumentSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webmention: %s responded %s", endpoint, resp.Status)
+	}
+	return nil
+}
+
+// Verify fetches the source and checks that it links to the target, ErrNoLink and ErrSourceGone tell a
+// mention that has to be dropped apart from a source that could not be fetched right now.
+func (c *Client) Verify(ctx context.Context, source, target string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/html, text/plain;q=0.9, */*;q=0.1")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSourceGone
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("webmention: %s responded %s", source, resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxDocumentSize))
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		if !strings.Contains(string(data), target) {
+			return ErrNoLink
+		}
+		return nil
+	}
+
+	doc, err := html.Parse(strings.NewReader(string(data)))
+	if err != nil {
+		return err
+	}
+
+	if !linksTo(doc, resp.Request.URL, target) {
+		return ErrNoLink
+	}
+	return nil
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+
+// Links extracts the absolute http(s) URLs written in a plain text body, in order and without duplicates.
+// Punctuation closing a sentence is not part of the URL.
+func Links(text string) []string {
+	var links []string
+	seen := make(map[string]bool)
+	for _, link := range urlPattern.FindAllString(text, -1) {
+		link = strings.TrimRight(link, ".,;:!?)]}")
+		if seen[link] {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+	}
+	return links
+}
+
+// parseLinkHeader returns the target of the first webmention relation in a Link header.
+func parseLinkHeader(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			pair := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(pair) == 2 && strings.EqualFold(pair[0], "rel") && hasRel(strings.Trim(pair[1], `"`)) {
+				return strings.Trim(target, "<>")
+			}
+		}
+	}
+	return ""
+}
+
+// findEndpoint walks the document for the first <link> or <a> with a webmention relation, an empty href
+// is a valid endpoint meaning the document itself.
+func findEndpoint(n *html.Node) (string, bool) {
+	if n.Type == html.ElementNode && (n.Data == "link" || n.Data == "a") {
+		href, hasHref := attr(n, "href")
+		rel, _ := attr(n, "rel")
+		if hasHref && hasRel(rel) {
+			return href, true
+		}
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if endpoint, found := findEndpoint(child); found {
+			return endpoint, true
+		}
+	}
+	return "", false
+}
+
+func linksTo(n *html.Node, base *url.URL, target string) bool {
+	if n.Type == html.ElementNode {
+		for _, name := range []string{"href", "src"} {
+			value, found := attr(n, name)
+			if !found {
+				continue
+			}
+			if link, err := resolve(base, value); err == nil && link == target {
+				return true
+			}
+		}
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if linksTo(child, base, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRel(rel string) bool {
+	for _, value := range strings.Fields(rel) {
+		if strings.EqualFold(value, "webmention") {
+			return true
+		}
+	}
+	return false
+}
+
+func attr(n *html.Node, name string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func resolve(base *url.URL, ref string) (string, error) {
+	u, err := base.Parse(strings.TrimSpace(ref))
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,176 @@
+package webmention
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/security/outbound"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mention struct {
+	source string
+	target string
+}
+
+// site is a fake remote site, each path serves one of the ways an endpoint can be advertised.
+func newSite(t *testing.T) (*httptest.Server, func() []mention) {
+	var mu sync.Mutex
+	var received []mention
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/header", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", `<https://example.org/other>; rel="me"`)
+		w.Header().Add("Link", `</endpoint?version=1>; rel="webmention"`)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><link rel="webmention" href="/wrong"></head></html>`)
+	})
+	mux.HandleFunc("/link", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<html><head><link rel="stylesheet" href="/style.css"><link rel="nofollow webmention" href="endpoint"></head></html>`)
+	})
+	mux.HandleFunc("/anchor", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><a href="/endpoint#a" rel="webmention">endpoint</a></body></html>`)
+	})
+	mux.HandleFunc("/self", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><link rel="webmention" href=""></head></html>`)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/sub/link", http.StatusFound)
+	})
+	mux.HandleFunc("/sub/link", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><link rel="webmention" href="endpoint"></head></html>`)
+	})
+	mux.HandleFunc("/none", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body>no endpoint</body></html>`)
+	})
+	mux.HandleFunc("/endpoint", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.FormValue("source") == "" || r.FormValue("target") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		received = append(received, mention{r.FormValue("source"), r.FormValue("target")})
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/post", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><p>Read <a href="https://blog.example/posts/1">this</a>.</p><img src="/img.png"></body></html>`)
+	})
+	mux.HandleFunc("/note", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, `see https://blog.example/posts/1`)
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, func() []mention {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]mention(nil), received...)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	site, _ := newSite(t)
+	client := &Client{httpClient: site.Client()}
+
+	tests := []struct {
+		path     string
+		endpoint string
+	}{
+		{"/header", site.URL + "/endpoint?version=1"},
+		{"/link", site.URL + "/endpoint"},
+		{"/anchor", site.URL + "/endpoint#a"},
+		{"/self", site.URL + "/self"},
+		{"/redirect", site.URL + "/sub/endpoint"},
+		{"/none", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			endpoint, err := client.Discover(context.Background(), site.URL+test.path)
+			require.NoError(t, err)
+			assert.Equal(t, test.endpoint, endpoint)
+		})
+	}
+
+	_, err := client.Discover(context.Background(), site.URL+"/broken")
+	assert.Error(t, err)
+}
+
+func TestSend(t *testing.T) {
+	site, received := newSite(t)
+	client := &Client{httpClient: site.Client()}
+
+	endpoint, err := client.Discover(context.Background(), site.URL+"/link")
+	require.NoError(t, err)
+
+	err = client.Send(context.Background(), endpoint, "https://blog.example/posts/1", site.URL+"/link")
+	require.NoError(t, err)
+	assert.Equal(t, []mention{{"https://blog.example/posts/1", site.URL + "/link"}}, received())
+
+	err = client.Send(context.Background(), site.URL+"/broken", "https://blog.example/posts/1", site.URL+"/link")
+	assert.Error(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	site, _ := newSite(t)
+	client := &Client{httpClient: site.Client()}
+	ctx := context.Background()
+
+	assert.NoError(t, client.Verify(ctx, site.URL+"/post", "https://blog.example/posts/1"))
+	assert.NoError(t, client.Verify(ctx, site.URL+"/post", site.URL+"/img.png"))
+	assert.NoError(t, client.Verify(ctx, site.URL+"/note", "https://blog.example/posts/1"))
+	assert.ErrorIs(t, client.Verify(ctx, site.URL+"/post", "https://blog.example/posts/2"), ErrNoLink)
+	assert.ErrorIs(t, client.Verify(ctx, site.URL+"/gone", "https://blog.example/posts/1"), ErrSourceGone)
+	assert.ErrorIs(t, client.Verify(ctx, site.URL+"/missing", "https://blog.example/posts/1"), ErrSourceGone)
+
+	err := client.Verify(ctx, site.URL+"/broken", "https://blog.example/posts/1")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNoLink)
+	assert.NotErrorIs(t, err, ErrSourceGone)
+}
+
+func TestPrivateAddress(t *testing.T) {
+	site, _ := newSite(t)
+	client := NewClient(5 * time.Second)
+	ctx := context.Background()
+
+	// the fake site listens on a loopback address and another port
+	assert.ErrorIs(t, client.Verify(ctx, site.URL+"/post", "https://blog.example/posts/1"), outbound.ErrURL)
+	assert.ErrorIs(t, client.Verify(ctx, "http://127.0.0.1/post", "https://blog.example/posts/1"), outbound.ErrAddress)
+	assert.ErrorIs(t, client.Verify(ctx, "http://169.254.169.254/latest/meta-data", "https://blog.example/posts/1"), outbound.ErrAddress)
+
+	_, err := client.Discover(ctx, "http://localhost/post")
+	assert.ErrorIs(t, err, outbound.ErrAddress)
+}
+
+func TestLinks(t *testing.T) {
+	body := "Inspired by https://example.org/a-post, and (https://example.org/b).\n\n" +
+		"Again: https://example.org/a-post. Not ftp://example.org or example.org/c. " +
+		"Query http://example.org/search?q=go&page=2!"
+
+	assert.Equal(t, []string{
+		"https://example.org/a-post",
+		"https://example.org/b",
+		"http://example.org/search?q=go&page=2",
+	}, Links(body))
+	assert.Empty(t, Links("no links here"))
+}
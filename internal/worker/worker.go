@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/anonychun/go-blog-api/internal/logger"
+)
+
+type Job func(ctx context.Context) error
+
+// Every runs job once per interval until ctx is cancelled, failures are logged and the
+// job is retried on the next tick. It blocks, so callers usually run it in a goroutine.
+func Every(ctx context.Context, name string, interval time.Duration, job Job) {
+	if interval <= 0 {
+		logger.Log().Warn().Msgf("worker %s disabled, interval must be positive", name)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := job(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.Log().Err(err).Msgf("worker %s failed", name)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery(t *testing.T) {
+	t.Run("run until cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		runs := make(chan struct{}, 10)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			Every(ctx, "test", time.Millisecond, func(ctx context.Context) error {
+				runs <- struct{}{}
+				return nil
+			})
+		}()
+
+		<-runs
+		<-runs
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("worker did not stop after cancel")
+		}
+	})
+
+	t.Run("disabled interval", func(t *testing.T) {
+		called := false
+		Every(context.Background(), "test", 0, func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+		assert.False(t, called)
+	})
+}
@@ -0,0 +1,131 @@
+package wxr
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Post types and statuses of WordPress items that matter to an import.
+const (
+	PostTypePost = "post"
+
+	StatusPublish = "publish"
+	StatusFuture  = "future"
+	StatusDraft   = "draft"
+	StatusPending = "pending"
+	StatusPrivate = "private"
+
+	DomainCategory = "category"
+	DomainTag      = "post_tag"
+)
+
+const dateLayout = "2006-01-02 15:04:05"
+
+var ErrFormat = errors.New("wxr: not a WordPress export")
+
+// Export is a WordPress eXtended RSS file. The wp: elements are matched by their local name only, so
+// the 1.0, 1.1 and 1.2 versions of the format are all understood.
+type Export struct {
+	Title      string     `xml:"channel>title"`
+	Link       string     `xml:"channel>link"`
+	Version    string     `xml:"channel>wxr_version"`
+	Authors    []Author   `xml:"channel>author"`
+	Categories []Category `xml:"channel>category"`
+	Items      []Item     `xml:"channel>item"`
+}
+
+type Author struct {
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+}
+
+// Category is a category defined by the export, Parent holds the nicename of the parent category.
+type Category struct {
+	Nicename string `xml:"category_nicename"`
+	Parent   string `xml:"category_parent"`
+	Name     string `xml:"cat_name"`
+}
+
+type Item struct {
+	Title       string         `xml:"title"`
+	Link        string         `xml:"link"`
+	Creator     string         `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Content     string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PostID      int64          `xml:"post_id"`
+	PostDate    string         `xml:"post_date"`
+	PostDateGMT string         `xml:"post_date_gmt"`
+	PostName    string         `xml:"post_name"`
+	Status      string         `xml:"status"`
+	PostType    string         `xml:"post_type"`
+	Terms       []ItemCategory `xml:"category"`
+}
+
+// ItemCategory attaches a category or a tag to an item, depending on Domain.
+type ItemCategory struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+// Parse decodes a WXR document, ErrFormat is returned for XML which is not a WordPress export.
+func Parse(r io.Reader) (*Export, error) {
+	var doc struct {
+		XMLName xml.Name
+		Export
+	}
+
+	err := xml.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+	} else if doc.XMLName.Local != "rss" || doc.Version == "" {
+		return nil, ErrFormat
+	}
+
+	export := doc.Export
+	for i := range export.Items {
+		for j := range export.Items[i].Terms {
+			term := &export.Items[i].Terms[j]
+			term.Name = strings.TrimSpace(term.Name)
+		}
+	}
+	return &export, nil
+}
+
+// Date is when the item was written. The GMT date is preferred, WordPress leaves it empty for drafts
+// in which case the local date of the blog is taken as UTC.
+func (i *Item) Date() (time.Time, error) {
+	for _, value := range []string{i.PostDateGMT, i.PostDate} {
+		if value == "" || strings.HasPrefix(value, "0000-00-00") {
+			continue
+		}
+		return time.Parse(dateLayout, value)
+	}
+	return time.Time{}, fmt.Errorf("%w: item %d has no date", ErrFormat, i.PostID)
+}
+
+// Categories lists the categories of the item, their nicename refers to the categories of the export.
+func (i *Item) Categories() []ItemCategory {
+	var categories []ItemCategory
+	for _, term := range i.Terms {
+		if term.Domain == DomainCategory && term.Name != "" {
+			categories = append(categories, term)
+		}
+	}
+	return categories
+}
+
+// Tags lists the names of the tags of the item.
+func (i *Item) Tags() []string {
+	var tags []string
+	for _, term := range i.Terms {
+		if term.Domain == DomainTag && term.Name != "" {
+			tags = append(tags, term.Name)
+		}
+	}
+	return tags
+}
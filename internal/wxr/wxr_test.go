@@ -0,0 +1,112 @@
+package wxr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testExport = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<title>Old Blog</title>
+	<link>https://old.example.com</link>
+	<wp:wxr_version>1.2</wp:wxr_version>
+	<wp:author>
+		<wp:author_id>1</wp:author_id>
+		<wp:author_login><![CDATA[jane]]></wp:author_login>
+		<wp:author_email><![CDATA[jane@example.com]]></wp:author_email>
+		<wp:author_display_name><![CDATA[Jane Doe]]></wp:author_display_name>
+	</wp:author>
+	<wp:category>
+		<wp:term_id>2</wp:term_id>
+		<wp:category_nicename><![CDATA[news]]></wp:category_nicename>
+		<wp:category_parent><![CDATA[]]></wp:category_parent>
+		<wp:cat_name><![CDATA[News]]></wp:cat_name>
+	</wp:category>
+	<wp:category>
+		<wp:term_id>3</wp:term_id>
+		<wp:category_nicename><![CDATA[releases]]></wp:category_nicename>
+		<wp:category_parent><![CDATA[news]]></wp:category_parent>
+		<wp:cat_name><![CDATA[Releases]]></wp:cat_name>
+	</wp:category>
+	<item>
+		<title>Hello world</title>
+		<link>https://old.example.com/2014/03/hello-world/</link>
+		<dc:creator><![CDATA[jane]]></dc:creator>
+		<content:encoded><![CDATA[<p>Welcome &amp; enjoy</p>]]></content:encoded>
+		<excerpt:encoded><![CDATA[Short]]></excerpt:encoded>
+		<wp:post_id>10</wp:post_id>
+		<wp:post_date><![CDATA[2014-03-01 17:30:00]]></wp:post_date>
+		<wp:post_date_gmt><![CDATA[2014-03-01 10:30:00]]></wp:post_date_gmt>
+		<wp:post_name><![CDATA[hello-world]]></wp:post_name>
+		<wp:status><![CDATA[publish]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+		<category domain="category" nicename="releases"><![CDATA[Releases]]></category>
+		<category domain="post_tag" nicename="go"><![CDATA[Go]]></category>
+		<category domain="post_tag" nicename="web"><![CDATA[ Web ]]></category>
+	</item>
+	<item>
+		<title>Unfinished</title>
+		<dc:creator><![CDATA[jane]]></dc:creator>
+		<content:encoded><![CDATA[]]></content:encoded>
+		<wp:post_id>11</wp:post_id>
+		<wp:post_date><![CDATA[2015-01-02 08:00:00]]></wp:post_date>
+		<wp:post_date_gmt><![CDATA[0000-00-00 00:00:00]]></wp:post_date_gmt>
+		<wp:status><![CDATA[draft]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+	</item>
+</channel>
+</rss>`
+
+func TestParse(t *testing.T) {
+	export, err := Parse(strings.NewReader(testExport))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Old Blog", export.Title)
+	assert.Equal(t, []Author{{Login: "jane", Email: "jane@example.com", DisplayName: "Jane Doe"}}, export.Authors)
+	assert.Equal(t, []Category{
+		{Nicename: "news", Name: "News"},
+		{Nicename: "releases", Parent: "news", Name: "Releases"},
+	}, export.Categories)
+	require.Len(t, export.Items, 2)
+
+	item := export.Items[0]
+	assert.Equal(t, "Hello world", item.Title)
+	assert.Equal(t, "jane", item.Creator)
+	assert.Equal(t, "<p>Welcome &amp; enjoy</p>", item.Content)
+	assert.Equal(t, int64(10), item.PostID)
+	assert.Equal(t, "hello-world", item.PostName)
+	assert.Equal(t, StatusPublish, item.Status)
+	assert.Equal(t, PostTypePost, item.PostType)
+	assert.Equal(t, []ItemCategory{{Domain: DomainCategory, Nicename: "releases", Name: "Releases"}}, item.Categories())
+	assert.Equal(t, []string{"Go", "Web"}, item.Tags())
+
+	date, err := item.Date()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2014, 3, 1, 10, 30, 0, 0, time.UTC), date)
+
+	date, err = export.Items[1].Date()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2015, 1, 2, 8, 0, 0, 0, time.UTC), date)
+	assert.Empty(t, export.Items[1].Tags())
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, doc := range []string{
+		"not xml at all",
+		`<rss version="2.0"><channel><title>Plain feed</title></channel></rss>`,
+		`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`,
+	} {
+		_, err := Parse(strings.NewReader(doc))
+		assert.True(t, errors.Is(err, ErrFormat), doc)
+	}
+}
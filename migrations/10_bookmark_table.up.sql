@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS bookmark (
+    account_id INT NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    post_id INT NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    folder VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS bookmark_account_id_created_at_idx ON bookmark(account_id, created_at DESC, post_id DESC);
\ No newline at end of file
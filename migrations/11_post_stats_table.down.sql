@@ -0,0 +1 @@
+DROP TABLE IF EXISTS post_stats;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS post_stats (
+    post_id INT NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    uniques BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, day)
+);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE post DROP COLUMN IF EXISTS cover_media_id;
+DROP TABLE IF EXISTS media;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS media (
+    id SERIAL PRIMARY KEY,
+    key VARCHAR(255) NOT NULL UNIQUE,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    account_id INT NOT NULL REFERENCES account(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS media_account_id_idx ON media(account_id);
+
+ALTER TABLE post ADD COLUMN IF NOT EXISTS cover_media_id INT REFERENCES media(id) ON DELETE SET NULL;
\ No newline at end of file
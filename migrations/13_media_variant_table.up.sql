@@ -0,0 +1,13 @@
+ALTER TABLE media ADD COLUMN IF NOT EXISTS width INT;
+ALTER TABLE media ADD COLUMN IF NOT EXISTS height INT;
+
+CREATE TABLE IF NOT EXISTS media_variant (
+    media_id INT NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    key VARCHAR(255) NOT NULL UNIQUE,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    PRIMARY KEY (media_id, name)
+);
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS post_cover_media_id_idx;
+DROP TABLE IF EXISTS post_media;
+ALTER TABLE media DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE media DROP COLUMN IF EXISTS caption;
+ALTER TABLE media DROP COLUMN IF EXISTS alt_text;
+ALTER TABLE media DROP COLUMN IF EXISTS filename;
\ No newline at end of file
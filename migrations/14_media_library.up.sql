@@ -0,0 +1,20 @@
+ALTER TABLE media ADD COLUMN IF NOT EXISTS filename VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE media ADD COLUMN IF NOT EXISTS alt_text TEXT NOT NULL DEFAULT '';
+ALTER TABLE media ADD COLUMN IF NOT EXISTS caption TEXT NOT NULL DEFAULT '';
+ALTER TABLE media ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS post_media (
+    post_id INT NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    media_id INT NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+    PRIMARY KEY (post_id, media_id)
+);
+
+CREATE INDEX IF NOT EXISTS post_media_media_id_idx ON post_media(media_id);
+CREATE INDEX IF NOT EXISTS post_cover_media_id_idx ON post(cover_media_id);
+
+INSERT INTO post_media (post_id, media_id)
+SELECT DISTINCT post.id, media.id
+FROM post
+INNER JOIN media ON POSITION(media.key IN post.body) > 0
+    OR EXISTS (SELECT 1 FROM media_variant WHERE media_variant.media_id = media.id AND POSITION(media_variant.key IN post.body) > 0)
+ON CONFLICT DO NOTHING;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS post_author;
\ No newline at end of file
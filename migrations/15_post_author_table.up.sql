@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS post_author (
+    post_id INT NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    account_id INT NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    role VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, account_id)
+);
+
+CREATE INDEX IF NOT EXISTS post_author_account_id_idx ON post_author(account_id);
+
+INSERT INTO post_author (post_id, account_id, role, created_at)
+SELECT id, account_id, 'owner', created_at FROM post
+ON CONFLICT DO NOTHING;
\ No newline at end of file
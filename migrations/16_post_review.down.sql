@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS post_review;
+DROP INDEX IF EXISTS post_status_idx;
+ALTER TABLE post DROP COLUMN IF EXISTS published_at;
+ALTER TABLE post DROP COLUMN IF EXISTS status;
\ No newline at end of file
@@ -0,0 +1,17 @@
+ALTER TABLE post ADD COLUMN IF NOT EXISTS status VARCHAR(255) NOT NULL DEFAULT 'draft';
+ALTER TABLE post ADD COLUMN IF NOT EXISTS published_at TIMESTAMP;
+
+UPDATE post SET status = 'published', published_at = created_at WHERE published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS post_status_idx ON post(status);
+
+CREATE TABLE IF NOT EXISTS post_review (
+    id SERIAL PRIMARY KEY,
+    action VARCHAR(255) NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    post_id INT NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    account_id INT NOT NULL REFERENCES account(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS post_review_post_id_idx ON post_review(post_id);
\ No newline at end of file
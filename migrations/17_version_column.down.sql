@@ -0,0 +1,2 @@
+ALTER TABLE account DROP COLUMN IF EXISTS version;
+ALTER TABLE post DROP COLUMN IF EXISTS version;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE post ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE account ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS activity_delivery;
+DROP TABLE IF EXISTS follower;
+DROP TABLE IF EXISTS actor_key;
\ No newline at end of file
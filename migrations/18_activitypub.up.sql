@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS actor_key (
+    account_id INT PRIMARY KEY REFERENCES account(id) ON DELETE CASCADE,
+    public_key TEXT NOT NULL,
+    private_key TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS follower (
+    account_id INT NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    actor VARCHAR(2048) NOT NULL,
+    inbox VARCHAR(2048) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, actor)
+);
+
+CREATE TABLE IF NOT EXISTS activity_delivery (
+    id SERIAL PRIMARY KEY,
+    account_id INT NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    inbox VARCHAR(2048) NOT NULL,
+    activity TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS activity_delivery_next_attempt_at_idx ON activity_delivery(next_attempt_at);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS webmention_delivery;
+DROP TABLE IF EXISTS webmention;
\ No newline at end of file
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS webmention (
+    id SERIAL PRIMARY KEY,
+    post_id INT NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    source VARCHAR(2048) NOT NULL,
+    target VARCHAR(2048) NOT NULL,
+    status VARCHAR(255) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_check_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    verified_at TIMESTAMP,
+    UNIQUE (post_id, source)
+);
+
+CREATE INDEX IF NOT EXISTS webmention_next_check_at_idx ON webmention(next_check_at);
+
+CREATE TABLE IF NOT EXISTS webmention_delivery (
+    id SERIAL PRIMARY KEY,
+    post_id INT NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    source VARCHAR(2048) NOT NULL,
+    target VARCHAR(2048) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (post_id, target)
+);
+
+CREATE INDEX IF NOT EXISTS webmention_delivery_next_attempt_at_idx ON webmention_delivery(next_attempt_at);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS post_slug_idx;
+ALTER TABLE post DROP COLUMN IF EXISTS slug;
\ No newline at end of file
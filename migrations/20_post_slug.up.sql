@@ -0,0 +1,3 @@
+ALTER TABLE post ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS post_slug_idx ON post(slug);
\ No newline at end of file
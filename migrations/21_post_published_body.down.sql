@@ -0,0 +1 @@
+ALTER TABLE post DROP COLUMN IF EXISTS published_body;
\ No newline at end of file
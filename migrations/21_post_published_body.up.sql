@@ -0,0 +1,3 @@
+ALTER TABLE post ADD COLUMN IF NOT EXISTS published_body TEXT NOT NULL DEFAULT '';
+
+UPDATE post SET published_body = body WHERE status = 'published';
\ No newline at end of file
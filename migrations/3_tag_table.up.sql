@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS tag (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS post_tag (
+    post_id INT NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    tag_id INT NOT NULL REFERENCES tag(id) ON DELETE CASCADE,
+    PRIMARY KEY (post_id, tag_id)
+);
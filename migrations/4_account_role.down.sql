@@ -0,0 +1 @@
+ALTER TABLE account DROP COLUMN IF EXISTS role;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE account ADD COLUMN IF NOT EXISTS role VARCHAR(32) NOT NULL DEFAULT 'author';
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE post DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS category;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS category (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    parent_id INT REFERENCES category(id)
+);
+
+CREATE INDEX IF NOT EXISTS category_parent_id_idx ON category(parent_id);
+
+ALTER TABLE post ADD COLUMN IF NOT EXISTS category_id INT REFERENCES category(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS post_category_id_idx ON post(category_id);
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS series (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    account_id INT NOT NULL REFERENCES account(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS series_post (
+    series_id INT NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+    post_id INT NOT NULL UNIQUE REFERENCES post(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    PRIMARY KEY (series_id, post_id),
+    UNIQUE (series_id, position)
+);
\ No newline at end of file
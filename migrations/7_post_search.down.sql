@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS post_search_vector_idx;
+DROP TRIGGER IF EXISTS post_search_vector_trigger ON post;
+DROP FUNCTION IF EXISTS post_search_vector_update();
+ALTER TABLE post DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE post DROP COLUMN IF EXISTS search_language;
\ No newline at end of file
@@ -0,0 +1,22 @@
+ALTER TABLE post ADD COLUMN IF NOT EXISTS search_language REGCONFIG NOT NULL DEFAULT 'english';
+ALTER TABLE post ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+CREATE OR REPLACE FUNCTION post_search_vector_update() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector(NEW.search_language, COALESCE(NEW.title, '')), 'A') ||
+        setweight(to_tsvector(NEW.search_language, COALESCE(NEW.body, '')), 'B');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS post_search_vector_trigger ON post;
+CREATE TRIGGER post_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF title, body, search_language ON post
+    FOR EACH ROW EXECUTE PROCEDURE post_search_vector_update();
+
+UPDATE post SET search_vector =
+    setweight(to_tsvector(search_language, title), 'A') ||
+    setweight(to_tsvector(search_language, body), 'B');
+
+CREATE INDEX IF NOT EXISTS post_search_vector_idx ON post USING GIN (search_vector);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS comment;
\ No newline at end of file
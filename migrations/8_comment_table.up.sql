@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS comment (
+    id SERIAL PRIMARY KEY,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    post_id INT NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    account_id INT NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    parent_id INT REFERENCES comment(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS comment_post_id_idx ON comment(post_id);
+CREATE INDEX IF NOT EXISTS comment_parent_id_idx ON comment(parent_id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS reaction;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS reaction (
+    post_id INT NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    account_id INT NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, account_id, type)
+);
\ No newline at end of file